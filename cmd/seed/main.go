@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Flgado/fitnessStudioApp/config"
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
+	dbfactory "github.com/Flgado/fitnessStudioApp/internal/database/dbFactory"
+	"github.com/Flgado/fitnessStudioApp/internal/seed"
+	"github.com/Flgado/fitnessStudioApp/utils"
+)
+
+const usage = `Usage: seed [flags]
+
+Fills the database of the configuration profile with a generated dataset for local demos and load tests:
+an owner, members with memberships, rooms, instructors, a month of classes and bookings.
+Run it on an empty database after the migrations, the same flags give the same dataset.
+The prod profile is refused, and the password of the accounts has no default.
+
+Flags:
+`
+
+func main() {
+	users := flag.Int("users", 50, "number of members")
+	month := flag.String("month", time.Now().UTC().Format("2006-01"), "month of the classes, YYYY-MM")
+	bookings := flag.Int("bookings", 4, "number of classes each member tries to book")
+	randomSeed := flag.Uint64("seed", 1, "random seed of the dataset")
+	password := flag.String("password", "", "password of the owner and of the members, required")
+	profile := flag.String("profile", config.DefaultProfile(), "configuration profile: local, test or prod, "+config.ProfileEnv+" by default")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	start, err := time.Parse("2006-01", *month)
+	if err != nil {
+		log.Fatalf("month must be YYYY-MM: %v", err)
+	}
+
+	if *users < 0 || *bookings < 0 {
+		log.Fatal("users and bookings cannot be negative")
+	}
+
+	// The dataset signs in an owner, it must never reach the production database
+	if *profile == config.ProfileProd {
+		log.Fatalf("the seed command does not run on the %s profile", config.ProfileProd)
+	}
+
+	if len(*password) < auth.MinPasswordLength || len(*password) > auth.MaxPasswordLength {
+		log.Fatalf("-password is required, with between %d and %d characters", auth.MinPasswordLength, auth.MaxPasswordLength)
+	}
+
+	cfgFile, err := config.LoadConfig(utils.GetConfigPath(*profile))
+	if err != nil {
+		log.Fatalf("LoadConfig: %v", err)
+	}
+
+	cfg, err := config.ParseConfig(cfgFile)
+	if err != nil {
+		log.Fatalf("ParseConfig: %v", err)
+	}
+
+	if err := config.ValidateDatabase(cfg); err != nil {
+		log.Fatalf("Invalid configuration of the %s profile:\n%v", *profile, err)
+	}
+
+	db, err := dbfactory.NewDBFactory(cfg).GetDbContext()
+	if err != nil {
+		log.Fatalf("Impossible to connect to the database: %v", err)
+	}
+	defer db.Close()
+
+	summary, err := seed.NewSeeder(db).Run(context.Background(), seed.Options{
+		Users:           *users,
+		Month:           start,
+		BookingsPerUser: *bookings,
+		Seed:            *randomSeed,
+		Password:        *password,
+	})
+	if err != nil {
+		log.Fatalf("seed: %v", err)
+	}
+
+	fmt.Printf("%d members, %d rooms, %d instructors, %d classes and %d bookings created in %s\n",
+		summary.Users, summary.Rooms, summary.Instructors, summary.Classes, summary.Bookings, start.Format("2006-01"))
+	fmt.Printf("sign in as %s or as any member with the password given with -password\n", seed.OwnerEmail)
+}
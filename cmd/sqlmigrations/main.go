@@ -1,61 +1,274 @@
 package main
 
 import (
-	"database/sql"
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/Flgado/fitnessStudioApp/config"
+	dbfactory "github.com/Flgado/fitnessStudioApp/internal/database/dbFactory"
+	"github.com/Flgado/fitnessStudioApp/internal/database/migrations"
 	"github.com/Flgado/fitnessStudioApp/utils"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/lib/pq"
+	"github.com/golang-migrate/migrate/v4/source"
 )
 
+const usage = `Usage: sqlmigrations [flags] <command>
+
+Commands:
+  up          apply all the pending migrations
+  down N      revert the last N migrations, asks for confirmation
+  goto V      migrate up or down to version V, asks for confirmation to go down
+  version     print the version of the database
+  status      print the version of the database and the pending migrations
+  force V     set the version of the database without running migrations, to recover from a failed migration
+
+Flags:
+`
+
+// migrator runs the migration commands against the database of the configuration profile.
+type migrator struct {
+	m      *migrate.Migrate
+	src    source.Driver
+	dryRun bool
+	yes    bool
+}
+
 func main() {
-	configPath := utils.GetMigrationConfigPath()
-	cfgFile, err := config.LoadConfig(configPath)
+	dryRun := flag.Bool("dry-run", false, "list the migration files up, down and goto would run, without running them")
+	yes := flag.Bool("yes", false, "do not ask for confirmation before reverting migrations")
+	profile := flag.String("profile", config.DefaultProfile(), "configuration profile: local, test or prod, "+config.ProfileEnv+" by default")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfgFile, err := config.LoadConfig(utils.GetConfigPath(*profile))
 	if err != nil {
 		log.Fatalf("LoadConfig: %v", err)
 	}
 
-	cfg, err := config.ParseMigrationConfig(cfgFile)
+	cfg, err := config.ParseConfig(cfgFile)
 	if err != nil {
 		log.Fatalf("ParseConfig: %v", err)
 	}
 
-	db, err := sql.Open("postgres", cfg.DBURL)
+	if err := config.ValidateDatabase(cfg); err != nil {
+		log.Fatalf("Invalid configuration of the %s profile:\n%v", *profile, err)
+	}
+
+	db, err := dbfactory.NewDBFactory(cfg).GetDbContext()
 	if err != nil {
-		log.Fatal(err)
+		log.Fatalf("Impossible to connect to the database: %v", err)
 	}
 	defer db.Close()
 
-	err = db.Ping()
+	driver, err := postgres.WithInstance(db.DB, &postgres.Config{})
 	if err != nil {
-		db.Close()
-		log.Panic(err)
+		log.Fatal(err)
 	}
 
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	src, err := migrations.Source()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://cmd//sqlmigrations/migrations",
-		"postgres", driver)
-
+	m, err := migrate.NewWithInstance(migrations.SourceName, src, "postgres", driver)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// Attempt to rollback
-	if err := m.Down(); err != nil && err != migrate.ErrNoChange {
-		log.Printf("Rollback failed: %v", err)
+	mg := migrator{m: m, src: src, dryRun: *dryRun, yes: *yes}
+	if err := mg.run(flag.Arg(0), flag.Args()[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (mg migrator) run(command string, args []string) error {
+	switch command {
+	case "up":
+		return mg.up()
+	case "down":
+		n, err := argument(command, args)
+		if err != nil {
+			return err
+		}
+		if n < 1 {
+			return errors.New("down needs the number of migrations to revert, at least 1")
+		}
+		return mg.down(n)
+	case "goto":
+		v, err := argument(command, args)
+		if err != nil {
+			return err
+		}
+		if v < 0 {
+			return errors.New("goto needs a positive version")
+		}
+		return mg.goTo(uint(v))
+	case "version":
+		return mg.version()
+	case "status":
+		return mg.status()
+	case "force":
+		v, err := argument(command, args)
+		if err != nil {
+			return err
+		}
+		return mg.m.Force(v)
+	default:
+		return fmt.Errorf("unknown command %q, run with -h for the list of commands", command)
 	}
+}
 
-	// Reapply migrations
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		log.Fatal(err)
+func (mg migrator) up() error {
+	planned, err := mg.plan(func(versions []uint, current uint, applied bool) ([]uint, bool, error) {
+		return planUp(versions, current, applied), false, nil
+	})
+	if err != nil || mg.dryRun || len(planned) == 0 {
+		return err
+	}
+
+	return ignoreNoChange(mg.m.Up())
+}
+
+func (mg migrator) down(n int) error {
+	planned, err := mg.plan(func(versions []uint, current uint, applied bool) ([]uint, bool, error) {
+		reverted, err := planDown(versions, current, applied, n)
+		return reverted, true, err
+	})
+	if err != nil || mg.dryRun {
+		return err
+	}
+
+	if !mg.confirm(planned) {
+		return errors.New("down cancelled")
+	}
+
+	return mg.m.Steps(-n)
+}
+
+func (mg migrator) goTo(target uint) error {
+	planned, err := mg.plan(func(versions []uint, current uint, applied bool) ([]uint, bool, error) {
+		return planGoto(versions, current, applied, target)
+	})
+	if err != nil || mg.dryRun || len(planned) == 0 {
+		return err
+	}
+
+	if !planned[0].Up && !mg.confirm(planned) {
+		return errors.New("goto cancelled")
+	}
+
+	return ignoreNoChange(mg.m.Migrate(target))
+}
+
+func (mg migrator) version() error {
+	current, dirty, err := mg.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		fmt.Println("no migration applied")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		fmt.Printf("%d (dirty, fix the database and run force)\n", current)
+		return nil
 	}
+
+	fmt.Println(current)
+	return nil
+}
+
+func (mg migrator) status() error {
+	if err := mg.version(); err != nil {
+		return err
+	}
+
+	mg.dryRun = true
+	return mg.up()
+}
+
+// plan lists the migration files a command runs. A dirty database is refused, it needs force first.
+func (mg migrator) plan(planner func(versions []uint, current uint, applied bool) ([]uint, bool, error)) ([]step, error) {
+	current, dirty, err := mg.m.Version()
+	applied := !errors.Is(err, migrate.ErrNilVersion)
+	if err != nil && applied {
+		return nil, err
+	}
+
+	if dirty {
+		return nil, fmt.Errorf("the database is dirty at version %d, fix it and run force", current)
+	}
+
+	versions, err := migrations.Versions(mg.src)
+	if err != nil {
+		return nil, err
+	}
+
+	planned, down, err := planner(versions, current, applied)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := steps(mg.src, planned, !down)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		fmt.Println("no pending migration")
+	}
+
+	for _, file := range files {
+		fmt.Println(file)
+	}
+
+	return files, nil
+}
+
+// confirm asks to type yes before reverting migrations, unless -yes is set.
+func (mg migrator) confirm(planned []step) bool {
+	if mg.yes {
+		return true
+	}
+
+	fmt.Printf("Reverting %d migrations drops their tables and data. Type yes to continue: ", len(planned))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(answer) == "yes"
+}
+
+func argument(command string, args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%s needs one argument, run with -h for the usage", command)
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("%s needs an integer argument: %w", command, err)
+	}
+
+	return n, nil
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
 }
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+// step is a migration file to run, up or down.
+type step struct {
+	Version    uint
+	Identifier string
+	Up         bool
+}
+
+func (s step) String() string {
+	direction := "down"
+	if s.Up {
+		direction = "up"
+	}
+
+	return fmt.Sprintf("%06d_%s.%s.sql", s.Version, s.Identifier, direction)
+}
+
+// planUp returns the versions applied by up, the ones after the current version.
+// applied is false when the database has no migration yet.
+func planUp(versions []uint, current uint, applied bool) []uint {
+	if !applied {
+		return slices.Clone(versions)
+	}
+
+	var pending []uint
+	for _, v := range versions {
+		if v > current {
+			pending = append(pending, v)
+		}
+	}
+
+	return pending
+}
+
+// planDown returns the versions reverted by down n, from the current version backwards.
+func planDown(versions []uint, current uint, applied bool, n int) ([]uint, error) {
+	if !applied {
+		return nil, errors.New("no migration is applied")
+	}
+
+	i := slices.Index(versions, current)
+	if i < 0 {
+		return nil, fmt.Errorf("version %d of the database has no migration file", current)
+	}
+
+	if n > i+1 {
+		return nil, fmt.Errorf("only %d migrations are applied, cannot revert %d", i+1, n)
+	}
+
+	var reverted []uint
+	for j := i; j > i-n; j-- {
+		reverted = append(reverted, versions[j])
+	}
+
+	return reverted, nil
+}
+
+// planGoto returns the versions applied or reverted to move the database to the target version.
+// down is true when the versions are reverted.
+func planGoto(versions []uint, current uint, applied bool, target uint) (planned []uint, down bool, err error) {
+	if !slices.Contains(versions, target) {
+		return nil, false, fmt.Errorf("version %d has no migration file", target)
+	}
+
+	if !applied || target > current {
+		for _, v := range planUp(versions, current, applied) {
+			if v <= target {
+				planned = append(planned, v)
+			}
+		}
+
+		return planned, false, nil
+	}
+
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i] <= current && versions[i] > target {
+			planned = append(planned, versions[i])
+		}
+	}
+
+	return planned, true, nil
+}
+
+// steps names the migration files of the versions.
+func steps(src source.Driver, versions []uint, up bool) ([]step, error) {
+	var planned []step
+	for _, v := range versions {
+		read := src.ReadUp
+		if !up {
+			read = src.ReadDown
+		}
+
+		r, identifier, err := read(v)
+		if err != nil {
+			return nil, fmt.Errorf("migration %d: %w", v, err)
+		}
+		r.Close()
+
+		planned = append(planned, step{Version: v, Identifier: identifier, Up: up})
+	}
+
+	return planned, nil
+}
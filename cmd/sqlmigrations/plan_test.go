@@ -0,0 +1,73 @@
+//go:build unittests
+// +build unittests
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testVersions = []uint{1, 2, 3, 5, 8}
+
+func TestPlanUp(t *testing.T) {
+	assert.Equal(t, testVersions, planUp(testVersions, 0, false))
+	assert.Equal(t, []uint{5, 8}, planUp(testVersions, 3, true))
+	assert.Empty(t, planUp(testVersions, 8, true))
+}
+
+func TestPlanDown(t *testing.T) {
+	testCases := []struct {
+		testName string
+		current  uint
+		applied  bool
+		n        int
+		expected []uint
+		valid    bool
+	}{
+		{"last", 8, true, 1, []uint{8}, true},
+		{"several", 5, true, 3, []uint{5, 3, 2}, true},
+		{"all", 8, true, 5, []uint{8, 5, 3, 2, 1}, true},
+		{"more than applied", 3, true, 4, nil, false},
+		{"nothing applied", 0, false, 1, nil, false},
+		{"unknown version", 4, true, 1, nil, false},
+	}
+
+	for _, tc := range testCases {
+		// Act
+		reverted, err := planDown(testVersions, tc.current, tc.applied, tc.n)
+
+		// Assert
+		assert.Equal(t, tc.valid, err == nil, tc.testName)
+		assert.Equal(t, tc.expected, reverted, tc.testName)
+	}
+}
+
+func TestPlanGoto(t *testing.T) {
+	testCases := []struct {
+		testName string
+		current  uint
+		applied  bool
+		target   uint
+		expected []uint
+		down     bool
+		valid    bool
+	}{
+		{"up from nothing", 0, false, 3, []uint{1, 2, 3}, false, true},
+		{"up", 2, true, 8, []uint{3, 5, 8}, false, true},
+		{"down", 8, true, 2, []uint{8, 5, 3}, true, true},
+		{"same version", 5, true, 5, nil, true, true},
+		{"unknown target", 2, true, 4, nil, false, false},
+	}
+
+	for _, tc := range testCases {
+		// Act
+		planned, down, err := planGoto(testVersions, tc.current, tc.applied, tc.target)
+
+		// Assert
+		assert.Equal(t, tc.valid, err == nil, tc.testName)
+		assert.Equal(t, tc.expected, planned, tc.testName)
+		assert.Equal(t, tc.down, down, tc.testName)
+	}
+}
@@ -2,17 +2,40 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
-type MigrationConfig struct {
-	DBURL string
-}
+// EnvPrefix starts the environment variables overriding the configuration files. A setting is overridden by
+// the prefix and its key in upper case, with underscores for the dots, e.g. FITNESS_POSTGRES_POSTGRESQLHOST
+// for postgres.PostgresqlHost.
+const EnvPrefix = "FITNESS"
+
+// ProfileEnv selects the configuration profile when the -profile flag is not given.
+const ProfileEnv = EnvPrefix + "_PROFILE"
+
+// ProfileProd is the profile of the production servers.
+const ProfileProd = "prod"
+
+// sslModes are the sslmode values of lib/pq.
+var sslModes = []string{"disable", "require", "verify-ca", "verify-full"}
+
 type Config struct {
-	Postgres PostgresConfig
-	Server   Server
+	Postgres   PostgresConfig
+	Migrations Migrations
+	Server     Server
+	Jobs       Jobs
+	Policies   Policies
+	Payments   Payments
+	Auth       Auth
 }
 type PostgresConfig struct {
 	PostgresqlHost     string
@@ -20,23 +43,101 @@ type PostgresConfig struct {
 	PostgresqlUser     string
 	PostgresqlPassword string
 	PostgresqlDbname   string
-	PostgresqlSSLMode  bool
-	PgDriver           string
+	// PostgresqlSSLMode is the sslmode of the connection: disable, require, verify-ca or verify-full
+	PostgresqlSSLMode string
+	PgDriver          string
+}
+
+type Migrations struct {
+	// AutoMigrate applies the pending migrations when the server starts, one server at a time
+	AutoMigrate bool
 }
 
 type Server struct {
 	Port string
 }
 
+type Jobs struct {
+	// NoShowInterval is the time between two runs of the no-show marking, e.g. "5m"
+	NoShowInterval time.Duration
+	// DropInExpiryInterval is the time between two runs of the expiry of the unpaid drop-ins, e.g. "1m"
+	DropInExpiryInterval time.Duration
+}
+
+// Policies holds the booking rules of the studio. ClassTypes overrides the default rules
+// for the classes of a type, a field left out keeps the default value.
+type Policies struct {
+	Booking    BookingPolicy
+	ClassTypes map[string]PolicyOverride
+}
+
+type BookingPolicy struct {
+	// OpensBefore is how long before the start of a class its bookings open, zero for no limit
+	OpensBefore time.Duration
+	// ClosesBefore is how long before the start of a class its bookings close
+	ClosesBefore time.Duration
+	// LateCancelWithin is how long before the start of a class a cancellation counts as late
+	LateCancelWithin time.Duration
+	// LateCancel is "allow" to accept and record the late cancellations or "reject" to refuse them
+	LateCancel string
+}
+
+// PolicyOverride changes some rules of the BookingPolicy for the classes of a type. The durations are
+// pointers so an override can set them to zero, e.g. "OpensBefore: 0" removes the booking window.
+type PolicyOverride struct {
+	OpensBefore      *time.Duration
+	ClosesBefore     *time.Duration
+	LateCancelWithin *time.Duration
+	// LateCancel is "allow" or "reject", empty keeps the default mode
+	LateCancel string
+}
+
+type Payments struct {
+	// Provider names the payment provider, "fake" for the in-process provider used locally and in tests
+	Provider string
+	// WebhookSecret signs the callbacks of the provider
+	WebhookSecret string
+	// Currency is the ISO 4217 code of the prices, e.g. "EUR"
+	Currency string
+	// DropInPrice is the price in cents of a single class paid without a membership
+	DropInPrice int
+	// DropInHoldTTL is how long the seat of a drop-in is held waiting for its payment, e.g. "15m"
+	DropInHoldTTL time.Duration
+}
+
+type Auth struct {
+	// TokenSecret signs the access tokens, at least 32 characters
+	TokenSecret string
+	// TokenTTL is how long an access token is valid, e.g. "1h"
+	TokenTTL time.Duration
+}
+
+// DefaultProfile returns the profile of the ProfileEnv environment variable, local when it is not set.
+func DefaultProfile() string {
+	if profile := os.Getenv(ProfileEnv); profile != "" {
+		return profile
+	}
+
+	return "local"
+}
+
+// LoadConfig reads a configuration file, with the environment variables of EnvPrefix overriding its settings.
+// Every setting can be overridden, even when the file does not have it, except the entries of the maps.
 func LoadConfig(filename string) (*viper.Viper, error) {
 	v := viper.New()
 
 	v.SetConfigName(filename)
 	v.AddConfigPath(".")
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
+	if err := bindEnvs(v, reflect.TypeOf(Config{}), ""); err != nil {
+		return nil, err
+	}
+
 	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			return nil, errors.New("config file not found")
+			return nil, fmt.Errorf("config file %s not found", filename)
 		}
 		return nil, err
 	}
@@ -44,6 +145,37 @@ func LoadConfig(filename string) (*viper.Viper, error) {
 	return v, nil
 }
 
+// bindEnvs binds the settings of a struct to their environment variables, AutomaticEnv alone only
+// overrides the settings present in the file.
+func bindEnvs(v *viper.Viper, t reflect.Type, prefix string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := prefix + field.Name
+
+		if field.Type.Kind() == reflect.Struct {
+			if err := bindEnvs(v, field.Type, key+"."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Map {
+			continue
+		}
+
+		if err := v.BindEnv(key, EnvName(key)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EnvName returns the environment variable overriding a setting, e.g. FITNESS_SERVER_PORT for server.Port.
+func EnvName(key string) string {
+	return strings.ToUpper(EnvPrefix + "_" + strings.ReplaceAll(key, ".", "_"))
+}
+
 func ParseConfig(v *viper.Viper) (Config, error) {
 	var c Config
 
@@ -56,14 +188,70 @@ func ParseConfig(v *viper.Viper) (Config, error) {
 	return c, nil
 }
 
-func ParseMigrationConfig(v *viper.Viper) (MigrationConfig, error) {
-	var c MigrationConfig
+// ValidateDatabase checks the settings of the database connection, and reports all the missing or invalid ones.
+// Every binary connects to the database, the seed command and the migration tool need nothing else.
+//
+// param: c Config - Parsed configuration.
+//
+// @return error - Error listing every missing or invalid setting with its environment variable, nil if there is none.
+func ValidateDatabase(c Config) error {
+	var errs []error
+	required := func(key, value string) {
+		if value == "" {
+			errs = append(errs, fmt.Errorf("%s is required (%s)", key, EnvName(key)))
+		}
+	}
 
-	err := v.Unmarshal(&c)
-	if err != nil {
-		log.Printf("unable to decode into struct, %v", err)
-		return MigrationConfig{}, err
+	required("postgres.PostgresqlHost", c.Postgres.PostgresqlHost)
+	required("postgres.PostgresqlUser", c.Postgres.PostgresqlUser)
+	required("postgres.PostgresqlDbname", c.Postgres.PostgresqlDbname)
+	errs = append(errs, port("postgres.PostgresqlPort", c.Postgres.PostgresqlPort))
+
+	if !slices.Contains(sslModes, c.Postgres.PostgresqlSSLMode) {
+		key := "postgres.PostgresqlSSLMode"
+		errs = append(errs, fmt.Errorf("%s must be one of %s, got %q (%s)", key, strings.Join(sslModes, ", "), c.Postgres.PostgresqlSSLMode, EnvName(key)))
 	}
 
-	return c, nil
+	if c.Postgres.PgDriver != "postgres" {
+		key := "postgres.PgDriver"
+		errs = append(errs, fmt.Errorf("%s must be postgres, got %q (%s)", key, c.Postgres.PgDriver, EnvName(key)))
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateServer checks the settings only the server uses, and reports all the missing or invalid ones.
+// The other settings of the policies, payments and auth sections are checked by the components using them.
+//
+// param: c Config - Parsed configuration.
+//
+// @return error - Error listing every missing or invalid setting with its environment variable, nil if there is none.
+func ValidateServer(c Config) error {
+	errs := []error{port("server.Port", c.Server.Port)}
+
+	notNegative := func(key string, value time.Duration) {
+		if value < 0 {
+			errs = append(errs, fmt.Errorf("%s cannot be negative (%s)", key, EnvName(key)))
+		}
+	}
+
+	notNegative("jobs.NoShowInterval", c.Jobs.NoShowInterval)
+	notNegative("jobs.DropInExpiryInterval", c.Jobs.DropInExpiryInterval)
+	notNegative("payments.DropInHoldTTL", c.Payments.DropInHoldTTL)
+
+	return errors.Join(errs...)
+}
+
+// port checks that a setting is a TCP port number.
+func port(key, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s is required (%s)", key, EnvName(key))
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("%s must be a port number, got %q (%s)", key, value, EnvName(key))
+	}
+
+	return nil
 }
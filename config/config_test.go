@@ -0,0 +1,160 @@
+//go:build unittests
+// +build unittests
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func load(t *testing.T, profile string) Config {
+	v, err := LoadConfig("config-" + profile)
+	assert.NoError(t, err)
+
+	c, err := ParseConfig(v)
+	assert.NoError(t, err)
+
+	return c
+}
+
+func TestProfiles(t *testing.T) {
+	for _, profile := range []string{"local", "test"} {
+		// Act
+		c := load(t, profile)
+
+		// Assert
+		assert.NoError(t, ValidateDatabase(c), profile)
+		assert.NoError(t, ValidateServer(c), profile)
+		assert.Equal(t, "disable", c.Postgres.PostgresqlSSLMode, profile)
+	}
+}
+
+func TestLoadConfig_UnknownProfile(t *testing.T) {
+	// Act
+	_, err := LoadConfig("config-staging")
+
+	// Assert
+	assert.EqualError(t, err, "config file config-staging not found")
+}
+
+func TestLoadConfig_EnvOverrides(t *testing.T) {
+	// Arrange
+	t.Setenv("FITNESS_POSTGRES_POSTGRESQLHOST", "db.internal")
+	t.Setenv("FITNESS_POSTGRES_POSTGRESQLUSER", "studio")
+	t.Setenv("FITNESS_POSTGRES_POSTGRESQLSSLMODE", "require")
+	t.Setenv("FITNESS_SERVER_PORT", "9090")
+	t.Setenv("FITNESS_MIGRATIONS_AUTOMIGRATE", "true")
+	t.Setenv("FITNESS_JOBS_NOSHOWINTERVAL", "10m")
+	t.Setenv("FITNESS_POLICIES_CLASSTYPES_SPINNING_LATECANCEL", "allow")
+
+	// Act
+	c := load(t, "local")
+
+	// Assert
+	assert.Equal(t, "db.internal", c.Postgres.PostgresqlHost)
+	assert.Equal(t, "studio", c.Postgres.PostgresqlUser)
+	assert.Equal(t, "require", c.Postgres.PostgresqlSSLMode)
+	assert.Equal(t, "FitnessStudio", c.Postgres.PostgresqlDbname)
+	assert.Equal(t, "9090", c.Server.Port)
+	assert.True(t, c.Migrations.AutoMigrate)
+	assert.Equal(t, 10*time.Minute, c.Jobs.NoShowInterval)
+	assert.Equal(t, "allow", c.Policies.ClassTypes["spinning"].LateCancel)
+	assert.Equal(t, time.Hour, *c.Policies.ClassTypes["spinning"].ClosesBefore)
+	assert.Nil(t, c.Policies.ClassTypes["spinning"].OpensBefore)
+}
+
+func TestParseConfig_PolicyOverrideSetToZero(t *testing.T) {
+	// Arrange
+	v := viper.New()
+	v.SetConfigType("yaml")
+	assert.NoError(t, v.ReadConfig(strings.NewReader(`
+policies:
+  Booking:
+    OpensBefore: 336h
+    ClosesBefore: 15m
+  ClassTypes:
+    open-gym:
+      OpensBefore: 0
+      ClosesBefore: 0s
+`)))
+
+	// Act
+	c, err := ParseConfig(v)
+
+	// Assert
+	assert.NoError(t, err)
+	override := c.Policies.ClassTypes["open-gym"]
+	assert.Equal(t, time.Duration(0), *override.OpensBefore)
+	assert.Equal(t, time.Duration(0), *override.ClosesBefore)
+	assert.Nil(t, override.LateCancelWithin)
+}
+
+func TestLoadConfig_EnvOverridesSettingsMissingFromTheFile(t *testing.T) {
+	// Arrange
+	t.Setenv("FITNESS_POSTGRES_POSTGRESQLHOST", "db.internal")
+	t.Setenv("FITNESS_POSTGRES_POSTGRESQLUSER", "studio")
+	t.Setenv("FITNESS_POSTGRES_POSTGRESQLPASSWORD", "secret")
+	t.Setenv("FITNESS_PAYMENTS_PROVIDER", "fake")
+	t.Setenv("FITNESS_PAYMENTS_WEBHOOKSECRET", "webhook-secret")
+	t.Setenv("FITNESS_AUTH_TOKENSECRET", "a-token-secret-of-at-least-32-characters")
+
+	// Act
+	c := load(t, "prod")
+
+	// Assert
+	assert.NoError(t, ValidateDatabase(c))
+	assert.NoError(t, ValidateServer(c))
+	assert.Equal(t, "db.internal", c.Postgres.PostgresqlHost)
+	assert.Equal(t, "secret", c.Postgres.PostgresqlPassword)
+	assert.Equal(t, "verify-full", c.Postgres.PostgresqlSSLMode)
+	assert.Equal(t, "fake", c.Payments.Provider)
+	assert.Equal(t, "webhook-secret", c.Payments.WebhookSecret)
+	assert.Equal(t, "a-token-secret-of-at-least-32-characters", c.Auth.TokenSecret)
+}
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	// Arrange
+	c := Config{
+		Postgres: PostgresConfig{PostgresqlPort: "54x", PostgresqlSSLMode: "true", PgDriver: "postgres"},
+		Server:   Server{Port: "8080"},
+		Jobs:     Jobs{NoShowInterval: -time.Minute},
+		Payments: Payments{DropInHoldTTL: -time.Minute},
+	}
+
+	// Act
+	err := errors.Join(ValidateDatabase(c), ValidateServer(c))
+
+	// Assert
+	assert.Error(t, err)
+	lines := strings.Split(err.Error(), "\n")
+	assert.Equal(t, []string{
+		"postgres.PostgresqlHost is required (FITNESS_POSTGRES_POSTGRESQLHOST)",
+		"postgres.PostgresqlUser is required (FITNESS_POSTGRES_POSTGRESQLUSER)",
+		"postgres.PostgresqlDbname is required (FITNESS_POSTGRES_POSTGRESQLDBNAME)",
+		`postgres.PostgresqlPort must be a port number, got "54x" (FITNESS_POSTGRES_POSTGRESQLPORT)`,
+		`postgres.PostgresqlSSLMode must be one of disable, require, verify-ca, verify-full, got "true" (FITNESS_POSTGRES_POSTGRESQLSSLMODE)`,
+		"jobs.NoShowInterval cannot be negative (FITNESS_JOBS_NOSHOWINTERVAL)",
+		"payments.DropInHoldTTL cannot be negative (FITNESS_PAYMENTS_DROPINHOLDTTL)",
+	}, lines)
+}
+
+func TestValidateDatabase_IgnoresTheServerSettings(t *testing.T) {
+	// Arrange
+	// the seed command and the migration tool only connect to the database
+	c := load(t, "local")
+	c.Server.Port = ""
+	c.Jobs.NoShowInterval = -time.Minute
+
+	// Act
+	err := ValidateDatabase(c)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Error(t, ValidateServer(c))
+}
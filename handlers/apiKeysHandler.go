@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/go-chi/chi"
+)
+
+type APIKeysHandler struct {
+	uc usecases.APIKeysUseCases
+}
+
+func NewAPIKeysHandler(uc usecases.APIKeysUseCases) *APIKeysHandler {
+	return &APIKeysHandler{uc: uc}
+}
+
+// HandlerCreateAPIKey handles the HTTP request to create an API key.
+// @Description Create an API key for a machine client, e.g. the front-desk kiosk, acting on behalf of the authenticated owner.
+// @Description Scopes are <resource>:read (GET requests) or <resource>:write (other requests) with a resource among bookings, classes,
+// @Description instructors, memberships, payments, rooms and users. The key is only returned in this response.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param request body api.CreateAPIKey true "Name and scopes of the key"
+// @Success 200 {object} api.NewAPIKey
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Router /v1/fitnessstudio/api-keys [post]
+func (h APIKeysHandler) HandlerCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var key api.CreateAPIKey
+	err := json.NewDecoder(r.Body).Decode(&key)
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Request body not expected",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	created, err := h.uc.CreateAPIKey(r.Context(), callerId(r), key)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, created)
+}
+
+// HandlerGetAPIKeys handles the HTTP request to list the API keys.
+// @Description List the API keys, revoked ones included, with their last use. The keys themselves are never returned.
+// @Tags API Keys
+// @Produce json
+// @Success 200 {object} []api.APIKey
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Router /v1/fitnessstudio/api-keys [get]
+func (h APIKeysHandler) HandlerGetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.uc.GetAPIKeys(r.Context())
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, keys)
+}
+
+// HandlerRevokeAPIKey handles the HTTP request to revoke an API key.
+// @Description Revoke an API key, the requests sending it get a 401 from now on.
+// @Tags API Keys
+// @Produce json
+// @Param keyId path int true "API key ID"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Router /v1/fitnessstudio/api-keys/{keyId} [delete]
+func (h APIKeysHandler) HandlerRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	keyId, err := strconv.Atoi(chi.URLParam(r, "keyId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"KeyId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	err = h.uc.RevokeAPIKey(r.Context(), keyId)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, map[string]string{"message": "API key revoked"})
+}
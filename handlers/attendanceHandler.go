@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/go-chi/chi"
+)
+
+type AttendanceHandler struct {
+	uc usecases.AttendanceUseCase
+}
+
+func NewAttendanceHandler(uc usecases.AttendanceUseCase) *AttendanceHandler {
+	return &AttendanceHandler{uc: uc}
+}
+
+// HandlerCheckIn handles the HTTP request to check a member in for a booked class.
+// @Description Mark a booking as attended, recording the check-in time and the authenticated user checking the member in.
+// @Description The check-in opens one hour before the class starts. Bookings without check-in are marked as no-shows once the class has ended.
+// @Description Owners, the instructor of the class and API keys can check the member in, members cannot check themselves in.
+// @Tags Bookings
+// @Accept json
+// @Produce json
+// @Param userId path int true "User ID"
+// @Param classId path int true "Class ID"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 402 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/bookings/users/{userId}/classes/{classId}/checkin [post]
+func (h *AttendanceHandler) HandlerCheckIn(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := strconv.Atoi(chi.URLParam(r, "userId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"UserId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	classId, err := strconv.Atoi(chi.URLParam(r, "classId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"ClassId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	// The authenticated caller checks the member in
+	err = h.uc.CheckIn(ctx, userId, classId, callerId(r))
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, map[string]string{"message": "Succesfull Checked In"})
+}
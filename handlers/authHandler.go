@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+)
+
+type AuthHandler struct {
+	uc usecases.AuthUseCases
+}
+
+func NewAuthHandler(uc usecases.AuthUseCases) *AuthHandler {
+	return &AuthHandler{uc: uc}
+}
+
+// HandlerRegister handles the HTTP request to create a user able to sign in.
+// @Description Create a user with an email and a password (8 to 72 characters), and sign the user in.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body api.Register true "User and credentials"
+// @Success 200 {object} api.Token
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /v1/fitnessstudio/auth/register [post]
+func (h AuthHandler) HandlerRegister(w http.ResponseWriter, r *http.Request) {
+	var register api.Register
+	err := json.NewDecoder(r.Body).Decode(&register)
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Request body not expected",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	token, err := h.uc.Register(r.Context(), register)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, token)
+}
+
+// HandlerLogin handles the HTTP request to sign in.
+// @Description Sign in with an email and a password. Send the access token in the Authorization header
+// @Description of the other requests as "Bearer <access_token>".
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body api.Login true "Credentials"
+// @Success 200 {object} api.Token
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /v1/fitnessstudio/auth/login [post]
+func (h AuthHandler) HandlerLogin(w http.ResponseWriter, r *http.Request) {
+	var login api.Login
+	err := json.NewDecoder(r.Body).Decode(&login)
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Request body not expected",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	token, err := h.uc.Login(r.Context(), login.Email, login.Password)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, token)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+)
+
+// APIKeyHeader carries the API key of the machine clients.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeys is the middleware identifying the machine clients sending an API key.
+//
+// Requests without the X-API-Key header go through untouched. An unknown or revoked key gets a HTTP 401
+// error, and a key without the scope of the route, see auth.ScopeFor, a HTTP 403 error. The identity of the
+// key is put in the request context, so Authenticate lets the request through.
+//
+// param: uc usecases.APIKeysUseCases - Use cases checking the keys and recording their use.
+//
+// @return func(http.Handler) http.Handler - Middleware for the chi routers.
+func APIKeys(uc usecases.APIKeysUseCases) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(APIKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			identity, err := uc.Authenticate(r.Context(), key)
+			if err != nil {
+				responseWithErrors(w, *r, err)
+				return
+			}
+
+			scope, ok := auth.ScopeFor(r.Method, r.URL.Path)
+			if !ok {
+				forbiddenKey(w, r, "API keys cannot be used for this operation.")
+				return
+			}
+
+			if !slices.Contains(identity.Scopes, scope) {
+				forbiddenKey(w, r, "The API key does not have the scope "+scope+".")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+		})
+	}
+}
+
+func forbiddenKey(w http.ResponseWriter, r *http.Request, details string) {
+	e := utils.E(http.StatusForbidden,
+		nil,
+		map[string]string{"message": "Forbidden"},
+		details,
+		"Ask an owner of the studio for a key with the scope of the operation.")
+
+	responseWithErrors(w, *r, e)
+}
+
+// Authenticate is the middleware requiring a valid bearer access token.
+//
+// The identity of the caller is put in the request context, see auth.IdentityFrom.
+// Requests already identified by APIKeys go through, others without a valid token get a HTTP 401 error.
+//
+// param: tokens *auth.Tokens - Verifier of the access tokens.
+//
+// @return func(http.Handler) http.Handler - Middleware for the chi routers.
+func Authenticate(tokens *auth.Tokens) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := auth.IdentityFrom(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := r.Header.Get("Authorization")
+			scheme, token, found := strings.Cut(header, " ")
+			if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+				unauthorized(w, r, "The request has no bearer access token.")
+				return
+			}
+
+			identity, err := tokens.Verify(strings.TrimSpace(token))
+			if err != nil {
+				unauthorized(w, r, "The access token is invalid or expired.")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+		})
+	}
+}
+
+// callerId returns the ID of the authenticated user of a request behind Authenticate.
+// For an API key it is the owner who created the key.
+func callerId(r *http.Request) int {
+	identity, _ := auth.IdentityFrom(r.Context())
+	return identity.UserId
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, details string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="fitnessstudio"`)
+
+	e := utils.E(http.StatusUnauthorized,
+		nil,
+		map[string]string{"message": "Unauthorized"},
+		details,
+		"Sign in at /v1/fitnessstudio/auth/login and send the token as \"Authorization: Bearer <access_token>\".")
+
+	responseWithErrors(w, *r, e)
+}
@@ -3,10 +3,12 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
 	"github.com/Flgado/fitnessStudioApp/internal/usecases"
 	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/go-chi/chi"
 )
 
 type MakeReservationHandler struct {
@@ -18,13 +20,23 @@ func NewMakeReservationHandler(uc usecases.MakeBookUseCase) *MakeReservationHand
 }
 
 // HandlerCreateBooking handles the HTTP request make a class reservation.
-// @Description Booking a class
+// @Description Booking a class for the authenticated user. If the class is full the user is added to the class waitlist
+// @Description and is booked automatically as soon as a seat is released. Cancelled, in progress and finished classes cannot be booked.
+// @Description The booking window and cutoff of the class type policy apply, a refused booking names the rule in its message.
+// @Description The booking takes one credit of a membership valid on the class date, refunded when the booking is cancelled.
 // @Tags Bookings
 // @Produce json
 // @Param request body api.MakeBooking true "Booking body"
 // @Success 200
+// @Success 202 {object} api.BookingResult
 // @Failure 400 {object} ErrorResponse
+// @Failure 402 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
 // @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /v1/fitnessstudio/bookings [post]
 func (h *MakeReservationHandler) HandlerCreateBooking(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -41,12 +53,84 @@ func (h *MakeReservationHandler) HandlerCreateBooking(w http.ResponseWriter, r *
 		return
 	}
 
-	err = h.uc.Book(ctx, reservation.UserId, reservation.ClassId)
+	userId, ok := actingUser(w, r, reservation.UserId)
+	if !ok {
+		return
+	}
+
+	result, err := h.uc.Book(ctx, userId, reservation.ClassId)
 
 	if err != nil {
 		responseWithErrors(w, *r, err)
 		return
 	}
 
+	if result.Status == api.BookingStatusWaitlisted {
+		respondWithJson(w, http.StatusAccepted, result)
+		return
+	}
+
 	respondWithJson(w, http.StatusOK, map[string]string{"message": "Succesfull Booked"})
 }
+
+// HandlerCancelBooking handles the HTTP request to cancel a class reservation.
+// @Description Cancel a class booking, releasing the reserved spot to the first user in the waitlist.
+// @Description If the user is on the class waitlist, the waitlist entry is removed instead.
+// @Description A cancellation close to the start of the class is late: it is recorded and reported,
+// @Description or refused when the policy of the class type rejects late cancellations.
+// @Description Bookings of classes in progress or finished cannot be cancelled, their credit is not refunded.
+// @Tags Bookings
+// @Produce json
+// @Param userId path int true "User ID"
+// @Param classId path int true "Class ID"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/bookings/users/{userId}/classes/{classId} [delete]
+func (h *MakeReservationHandler) HandlerCancelBooking(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userId, err := strconv.Atoi(chi.URLParam(r, "userId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"UserId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	classId, err := strconv.Atoi(chi.URLParam(r, "classId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"ClassId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	result, err := h.uc.Cancel(ctx, userId, classId)
+
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	if result.Late {
+		respondWithJson(w, http.StatusOK, map[string]string{"message": "Booking Cancelled Late"})
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, map[string]string{"message": "Booking Succesfull Cancelled"})
+}
@@ -19,12 +19,17 @@ func NewBookingInfoHandler(uc usecases.BookingUseCase) *BookingInfoHandler {
 
 // HandlerGetUserClasses handles the HTTP request to get classe booked by user
 // @Summary Get the list of classes by user
-// @Description Returns a list of classes booked by user
+// @Description Returns a list of classes booked by user, including the classes where the user is waitlisted and its queue position.
+// @Description Booked classes have an attendance status: pending, attended (with the check-in time) or no_show.
 // @Tags Bookings
 // @Produce json
 // @Param userId path int true "User ID"
 // @Success 200 {array} []ClassBooked
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /v1/fitnessstudio/bookings/users/{userId}/classes [get]
 func (h BookingInfoHandler) HandlerGetUserClasses(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -54,13 +59,17 @@ func (h BookingInfoHandler) HandlerGetUserClasses(w http.ResponseWriter, r *http
 
 // HandlerGetClassUsers handles the HTTP request to get users registered in a class
 // @Summary Get a list of users who have booked the class.
-// @Description Return the list of users who have booked the class.
+// @Description Return the list of users who have booked the class. Instructors only see the users of the classes they teach.
 // @Tags Bookings
 // @Produce json
 // @Param classId path int true "Class Id"
 // @Success 200 {array} []api.UsersBooked
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /v1/fitnessstudio/bookings/classes/{classId}/users [get]
 func (h BookingInfoHandler) HandlerGetClassUsers(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -28,13 +28,19 @@ func NewClassesHandler(uc usecases.ClassesUseCases) *ClassesHandler {
 // @Param className query string false "Filter by class name"
 // @Param startDate query string false "Filter classes with start date greater than or equal to the specified date. Format: dddd-dd-dd"
 // @Param endDate query string false "Filter classes with end date less than or equal to the specified date. Format: dddd-dd-dd"
+// @Param roomId query integer false "Filter classes by room"
+// @Param instructorId query integer false "Filter classes by instructor"
+// @Param status query string false "Filter classes by status (scheduled or cancelled)"
 // @Param capacityGte query integer false "Filter classes with capacity greater than or equal to the specified value"
 // @Param capacityLe query integer false "Filter classes with capacity less than or equal to the specified value"
 // @Param numRegistrationsGte query integer false "Filter classes with number of registrations greater than or equal to the specified value"
 // @Param numRegistrationsLe query integer false "Filter classes with number of registrations less than or equal to the specified value"
 // @Success 200 {array} api.ReadClass "Successful operation"
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
 // @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /v1/fitnessstudio/classes [get]
 func (h ClassesHandler) HandlerGetClasses(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -59,15 +65,26 @@ func (h ClassesHandler) HandlerGetClasses(w http.ResponseWriter, r *http.Request
 
 // HandlerAddClass handles the HTTP request to add a new class.
 // @Summary Create multiple classes.
-// @Description Creates new classes using the provided details. New classes will be created for each day within the range specified by the start date and end date.
-// @Description If any of these days are unavailable, the endpoint will return the corresponding classes, indicating that scheduling was not possible
+// @Description Creates new classes using the provided details. New classes will be created for each day within the range specified by the start date and end date,
+// @Description starting at the given start time (HH:MM, defaults to 00:00) and lasting the given duration in minutes (defaults to 60).
+// @Description An iCalendar recurrence rule (rrule) with FREQ (DAILY or WEEKLY), INTERVAL, BYDAY and COUNT or UNTIL replaces the daily classes, e.g. "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10" for Mondays and Wednesdays.
+// @Description The first class is on the start date, the end date is optional when the rule has COUNT or UNTIL, and exdates (YYYY-MM-DD) lists days without class.
+// @Description Classes take place in a room and their capacity cannot exceed the room maximum capacity.
+// @Description An instructor can optionally be assigned with instructor_id.
+// @Description If any of these classes overlaps an existing class of the same room or of the same instructor, the endpoint will return the corresponding classes, indicating that scheduling was not possible
 // @Tags Classes
 // @Accept json
 // @Produce json
-// @Param body body api.ClassScheduler true "Class details (all fields are required, dates in the format YYYY-MM-DD)"
+// @Param body body api.ClassSchedulerReceiver true "Class details (name, start date, capacity and room_id are required, dates in the format YYYY-MM-DD)"
 // @Success 200 {string} map[string]interface{}{"message": "All Classes Created With Success", "Not Possible To Scheduler": array<api.Class>}
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /v1/fitnessstudio/classes [post]
 func (h ClassesHandler) HandlerAddClass(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -84,6 +101,17 @@ func (h ClassesHandler) HandlerAddClass(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if addClass.RoomId <= 0 {
+		e := utils.E(http.StatusBadRequest,
+			nil,
+			map[string]string{"message": "BadRequest"},
+			"room_id is required",
+			"Select the room where the classes take place")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
 	layout := "2006-01-02"
 	startDate, err := time.Parse(layout, addClass.StartDate)
 
@@ -98,24 +126,51 @@ func (h ClassesHandler) HandlerAddClass(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	endDate, err := time.Parse(layout, addClass.EndDate)
+	// a recurrence rule with COUNT or UNTIL does not need an end date
+	var endDate time.Time
+	if addClass.EndDate != "" || addClass.RRule == "" {
+		endDate, err = time.Parse(layout, addClass.EndDate)
+
+		if err != nil {
+			e := utils.E(http.StatusBadRequest,
+				err,
+				map[string]string{"message": "BadRequest"},
+				"Request body not expected",
+				"Read our documentation for more details")
+
+			responseWithErrors(w, *r, e)
+			return
+		}
+	}
+
+	startTime, err := parseStartTime(addClass.StartTime)
 
 	if err != nil {
-		e := utils.E(http.StatusBadRequest,
-			err,
-			map[string]string{"message": "BadRequest"},
-			"Request body not expected",
-			"Read our documentation for more details")
+		responseWithErrors(w, *r, err)
+		return
+	}
 
-		responseWithErrors(w, *r, e)
+	rule, exDates, err := parseRecurrence(addClass.RRule, addClass.ExDates)
+
+	if err != nil {
+		responseWithErrors(w, *r, err)
 		return
 	}
 
 	createClass := api.ClassScheduler{
-		Name:      addClass.Name,
-		StartDate: startDate,
-		EndDate:   endDate,
-		Capacity:  addClass.Capacity,
+		Name:         addClass.Name,
+		Type:         addClass.Type,
+		StartDate:    startDate.Add(startTime),
+		Duration:     addClass.Duration,
+		Capacity:     addClass.Capacity,
+		RoomId:       addClass.RoomId,
+		InstructorId: addClass.InstructorId,
+		Recurrence:   rule,
+		RRule:        addClass.RRule,
+		ExDates:      exDates,
+	}
+	if !endDate.IsZero() {
+		createClass.EndDate = endDate.Add(startTime)
 	}
 	// returned classes that was not possible to sheduler
 	c, err := h.uc.CreateClass(ctx, createClass)
@@ -134,7 +189,8 @@ func (h ClassesHandler) HandlerAddClass(w http.ResponseWriter, r *http.Request)
 }
 
 // HandlerUpdateClass handles the HTTP request to update a class.
-// @Description Update class.
+// @Description Update class. The date accepts the formats YYYY-MM-DD, YYYY-MM-DDTHH:MM or RFC3339 and the duration is in minutes.
+// @Description An instructor_id of 0 removes the instructor from the class.
 // @Tags Classes
 // @Produce json
 // @Param request body api.PatchClass{} true "Class data to update"
@@ -142,7 +198,11 @@ func (h ClassesHandler) HandlerAddClass(w http.ResponseWriter, r *http.Request)
 // @Failure 400 {object} ErrorResponse
 // @Failure 422 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /v1/fitnessstudio/classes [patch]
 func (h ClassesHandler) HandlerUpdateClass(w http.ResponseWriter, r *http.Request) {
 
@@ -160,7 +220,7 @@ func (h ClassesHandler) HandlerUpdateClass(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	updateClass, err := BuildUpdateClass(patchClass.Date, patchClass.Name, patchClass.Capacity)
+	updateClass, err := BuildUpdateClass(patchClass.Date, patchClass.Name, patchClass.Duration, patchClass.Capacity, patchClass.RoomId, patchClass.InstructorId)
 
 	if err != nil {
 		responseWithErrors(w, *r, err)
@@ -175,6 +235,155 @@ func (h ClassesHandler) HandlerUpdateClass(w http.ResponseWriter, r *http.Reques
 	respondWithJson(w, http.StatusOK, map[string]string{"message": "Class Succesfull updated"})
 }
 
+// HandlerUpdateSeries handles the HTTP request to update a class and the following occurrences of its series.
+// @Description Update a class and all the following classes created by the same scheduler request.
+// @Description The start time (HH:MM) moves every class to that time of its own day and the duration is in minutes. Cancelled classes are not updated.
+// @Tags Classes
+// @Produce json
+// @Param classId path int true "ID of the first class to update"
+// @Param request body api.PatchSeries{} true "Series data to update"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/classes/{classId}/series [patch]
+func (h ClassesHandler) HandlerUpdateSeries(w http.ResponseWriter, r *http.Request) {
+	classId, err := strconv.Atoi(chi.URLParam(r, "classId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"ClassId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	patchSeries := api.PatchSeries{}
+	err = json.NewDecoder(r.Body).Decode(&patchSeries)
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Request body not expected",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	if err = validateRoomId(patchSeries.RoomId); err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	updateSeries := api.UpdateSeries{
+		Name:         patchSeries.Name,
+		Duration:     patchSeries.Duration,
+		Capacity:     patchSeries.Capacity,
+		RoomId:       patchSeries.RoomId,
+		InstructorId: patchSeries.InstructorId,
+	}
+	if patchSeries.StartTime != nil {
+		startTime, err := parseStartTime(*patchSeries.StartTime)
+		if err != nil {
+			responseWithErrors(w, *r, err)
+			return
+		}
+		updateSeries.StartTime = &startTime
+	}
+
+	rows, err := h.uc.UpdateSeries(r.Context(), updateSeries, classId)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, map[string]interface{}{"message": "Series Succesfull updated", "classes_updated": rows})
+}
+
+// HandlerCancelSeries handles the HTTP request to cancel a class and the following occurrences of its series.
+// @Description Cancel a class and all the following classes created by the same scheduler request.
+// @Description The classes keep existing with the cancelled status and their bookings and waitlist entries are released.
+// @Tags Classes
+// @Produce json
+// @Param classId path int true "ID of the first class to cancel"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/classes/{classId}/series/cancel [post]
+func (h ClassesHandler) HandlerCancelSeries(w http.ResponseWriter, r *http.Request) {
+	classId, err := strconv.Atoi(chi.URLParam(r, "classId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"ClassId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	rows, err := h.uc.CancelSeries(r.Context(), classId)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, map[string]interface{}{"message": "Series Succesfull cancelled", "classes_cancelled": rows})
+}
+
+// HandlerCancelClass handles the HTTP request to cancel a class.
+// @Description Cancel a class, e.g. when its instructor is sick. The class keeps existing with the cancelled status,
+// @Description its bookings and waitlist entries are released and every booked member is notified.
+// @Tags Classes
+// @Produce json
+// @Param classId path int true "ID of the class to cancel"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/classes/{classId}/cancel [post]
+func (h ClassesHandler) HandlerCancelClass(w http.ResponseWriter, r *http.Request) {
+	classId, err := strconv.Atoi(chi.URLParam(r, "classId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"ClassId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	err = h.uc.CancelClass(r.Context(), classId)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, map[string]string{"message": "Class Succesfull cancelled"})
+}
+
 // HandlerGetClassById handles the HTTP request to get a class by ID.
 // @Description Get a class by ID
 // @Tags Classes
@@ -183,7 +392,10 @@ func (h ClassesHandler) HandlerUpdateClass(w http.ResponseWriter, r *http.Reques
 // @Success 200 {object} api.ReadClass
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
 // @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /v1/fitnessstudio/classes/{classId} [get]
 func (h ClassesHandler) HandlerGetClassById(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
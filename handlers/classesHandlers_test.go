@@ -0,0 +1,45 @@
+//go:build unittests
+// +build unittests
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateClasses_InvalidRoom(t *testing.T) {
+	// the requests are refused before reaching the use cases
+	h := NewClassesHandler(nil)
+	router := chi.NewRouter()
+	router.Patch("/classes", h.HandlerUpdateClass)
+	router.Patch("/classes/{classId}/series", h.HandlerUpdateSeries)
+
+	testCases := []struct {
+		testName string
+		path     string
+		body     string
+	}{
+		{"class without room", "/classes", `{"id": 1, "room_id": 0}`},
+		{"class with a negative room", "/classes", `{"id": 1, "room_id": -2}`},
+		{"series without room", "/classes/1/series", `{"room_id": 0}`},
+	}
+
+	for _, tc := range testCases {
+		// Arrange
+		req := httptest.NewRequest(http.MethodPatch, tc.path, strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code, tc.testName)
+		assert.Contains(t, w.Body.String(), "room_id is required", tc.testName)
+	}
+}
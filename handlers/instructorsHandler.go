@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/go-chi/chi"
+)
+
+type InstructorsHandler struct {
+	uc usecases.InstructorsUseCases
+}
+
+func NewInstructorsHandler(uc usecases.InstructorsUseCases) *InstructorsHandler {
+	return &InstructorsHandler{uc: uc}
+}
+
+// HandlerGetInstructors handles the HTTP request to get all instructors.
+// @Description Get all instructors
+// @Tags Instructors
+// @Produce json
+// @Success 200 {object} []api.Instructor
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/instructors [get]
+func (h InstructorsHandler) HandlerGetInstructors(w http.ResponseWriter, r *http.Request) {
+	instructors, err := h.uc.GetAllInstructors(r.Context())
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, instructors)
+}
+
+// HandlerGetInstructorById handles the HTTP request to get an instructor by ID.
+// @Description Get an instructor by ID
+// @Tags Instructors
+// @Produce json
+// @Param instructorId path int true "Instructor ID"
+// @Success 200 {object} api.Instructor
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/instructors/{instructorId} [get]
+func (h InstructorsHandler) HandlerGetInstructorById(w http.ResponseWriter, r *http.Request) {
+	instructorId, err := strconv.Atoi(chi.URLParam(r, "instructorId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"InstructorId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	instructor, err := h.uc.GetInstructorById(r.Context(), instructorId)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, instructor)
+}
+
+// HandlerCreateInstructor handles the HTTP request to create a new instructor.
+// @Description Create a new instructor.
+// @Tags Instructors
+// @Produce json
+// @Param request body api.CreateInstructor true "Instructor profile to create"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/instructors [post]
+func (h InstructorsHandler) HandlerCreateInstructor(w http.ResponseWriter, r *http.Request) {
+	var instructor api.CreateInstructor
+	err := json.NewDecoder(r.Body).Decode(&instructor)
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Request body not expected",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	instructor.Name = strings.TrimSpace(instructor.Name)
+	if instructor.Name == "" {
+		responseWithErrors(w, *r, emptyInstructorNameError())
+		return
+	}
+
+	instructor.Email = strings.TrimSpace(instructor.Email)
+	if err = validateInstructorEmail(instructor.Email); err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	err = h.uc.CreateInstructor(r.Context(), instructor)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, map[string]string{"message": "Instructor created with Success"})
+}
+
+// HandlerUpdateInstructor handles the HTTP request to update an instructor.
+// @Description Update an instructor profile
+// @Tags Instructors
+// @Produce json
+// @Param request body api.PatchInstructor true "Instructor data to update"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/instructors [patch]
+func (h InstructorsHandler) HandlerUpdateInstructor(w http.ResponseWriter, r *http.Request) {
+	instructor := api.PatchInstructor{}
+	err := json.NewDecoder(r.Body).Decode(&instructor)
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Request body not expected",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	if instructor.Name != nil {
+		name := strings.TrimSpace(*instructor.Name)
+		if name == "" {
+			responseWithErrors(w, *r, emptyInstructorNameError())
+			return
+		}
+		instructor.Name = &name
+	}
+
+	if instructor.Email != nil {
+		email := strings.TrimSpace(*instructor.Email)
+		if err = validateInstructorEmail(email); err != nil {
+			responseWithErrors(w, *r, err)
+			return
+		}
+		instructor.Email = &email
+	}
+
+	_, err = h.uc.UpdateInstructor(r.Context(), instructor)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, map[string]string{"message": "Instructor Succesfull updated"})
+}
+
+func emptyInstructorNameError() error {
+	return utils.E(http.StatusBadRequest,
+		nil,
+		map[string]string{"message": "BadRequest"},
+		"Instructor name should not be empty",
+		"Use a valid instructor name")
+}
+
+// validateInstructorEmail accepts an empty email, instructors are not required to have one.
+func validateInstructorEmail(email string) error {
+	if email == "" {
+		return nil
+	}
+
+	if _, err := mail.ParseAddress(email); err != nil {
+		return utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Instructor email is not valid",
+			"Use a valid email address")
+	}
+
+	return nil
+}
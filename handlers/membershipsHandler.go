@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/go-chi/chi"
+)
+
+type MembershipsHandler struct {
+	uc usecases.MembershipsUseCases
+}
+
+func NewMembershipsHandler(uc usecases.MembershipsUseCases) *MembershipsHandler {
+	return &MembershipsHandler{uc: uc}
+}
+
+// HandlerGetPlans handles the HTTP request to get all membership plans.
+// @Description Get all membership plans
+// @Tags Memberships
+// @Produce json
+// @Success 200 {object} []api.Plan
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/memberships/plans [get]
+func (h MembershipsHandler) HandlerGetPlans(w http.ResponseWriter, r *http.Request) {
+	plans, err := h.uc.GetAllPlans(r.Context())
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, plans)
+}
+
+// HandlerCreatePlan handles the HTTP request to create a membership plan.
+// @Description Create a membership plan: a monthly unlimited membership (plan_type unlimited)
+// @Description or a class pack (plan_type credits) with the number of classes in credits.
+// @Tags Memberships
+// @Produce json
+// @Param request body api.CreatePlan true "Plan data to create"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/memberships/plans [post]
+func (h MembershipsHandler) HandlerCreatePlan(w http.ResponseWriter, r *http.Request) {
+	var plan api.CreatePlan
+	err := json.NewDecoder(r.Body).Decode(&plan)
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Request body not expected",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	plan.Name = strings.TrimSpace(plan.Name)
+	if plan.Name == "" {
+		e := utils.E(http.StatusBadRequest,
+			nil,
+			map[string]string{"message": "BadRequest"},
+			"Plan name should not be empty",
+			"Use a valid plan name")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	err = h.uc.CreatePlan(r.Context(), plan)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, map[string]string{"message": "Plan created with Success"})
+}
+
+// HandlerGetUserMemberships handles the HTTP request to get the memberships of a user.
+// @Description Get the memberships of a user, including the expired ones, with the credits left of the class packs
+// @Tags Memberships
+// @Produce json
+// @Param userId path int true "User ID"
+// @Success 200 {object} []api.Membership
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/memberships/users/{userId} [get]
+func (h MembershipsHandler) HandlerGetUserMemberships(w http.ResponseWriter, r *http.Request) {
+	userId, err := strconv.Atoi(chi.URLParam(r, "userId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"UserId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	memberships, err := h.uc.GetUserMemberships(r.Context(), userId)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, memberships)
+}
+
+// HandlerAssignMembership handles the HTTP request to give a membership plan to a user.
+// @Description Give a membership plan to a user. Bookings consume one credit of a class pack valid
+// @Description on the class date, unlimited memberships are used first and consume nothing.
+// @Tags Memberships
+// @Accept json
+// @Produce json
+// @Param userId path int true "User ID"
+// @Param request body api.AssignMembership true "Membership data"
+// @Success 200 {object} api.Membership
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/memberships/users/{userId} [post]
+func (h MembershipsHandler) HandlerAssignMembership(w http.ResponseWriter, r *http.Request) {
+	userId, err := strconv.Atoi(chi.URLParam(r, "userId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"UserId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	var assign api.AssignMembership
+	err = json.NewDecoder(r.Body).Decode(&assign)
+	if err != nil || assign.PlanId <= 0 {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"plan_id is required",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	var validFrom time.Time
+	if assign.ValidFrom != "" {
+		validFrom, err = time.Parse("2006-01-02", assign.ValidFrom)
+		if err != nil {
+			responseWithErrors(w, *r, buildFormatParameterError(err, "valid_from"))
+			return
+		}
+	}
+
+	membership, err := h.uc.AssignMembership(r.Context(), userId, assign.PlanId, validFrom)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, membership)
+}
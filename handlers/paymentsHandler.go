@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/go-chi/chi"
+)
+
+// PaymentSignatureHeader carries the signature of the payment provider callbacks.
+const PaymentSignatureHeader = "X-Payment-Signature"
+
+// maxWebhookBody bounds the size of a payment provider callback.
+const maxWebhookBody = 64 << 10
+
+type PaymentsHandler struct {
+	uc usecases.PaymentsUseCases
+}
+
+func NewPaymentsHandler(uc usecases.PaymentsUseCases) *PaymentsHandler {
+	return &PaymentsHandler{uc: uc}
+}
+
+// HandlerPayMembership handles the HTTP request to buy a membership plan.
+// @Description Start the payment of a membership plan at its price for the authenticated user.
+// @Description The membership is granted when the payment is captured or the payment provider reports it as succeeded.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param request body api.PayMembership true "Membership payment"
+// @Success 200 {object} api.Payment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/payments/memberships [post]
+func (h PaymentsHandler) HandlerPayMembership(w http.ResponseWriter, r *http.Request) {
+	var pay api.PayMembership
+	err := json.NewDecoder(r.Body).Decode(&pay)
+	if err != nil || pay.PlanId <= 0 {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"plan_id is required",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	userId, ok := actingUser(w, r, pay.UserId)
+	if !ok {
+		return
+	}
+
+	payment, err := h.uc.PayMembership(r.Context(), userId, pay.PlanId)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, payment)
+}
+
+// HandlerPayDropIn handles the HTTP request to buy a single class.
+// @Description Start the payment of a single class at the drop-in price for the authenticated user. The seat is held
+// @Description as pending_payment and the booking is confirmed when the payment succeeds, or released when it fails.
+// @Description The booking rules of the class apply, and a full class cannot be bought as a drop-in.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param request body api.PayDropIn true "Drop-in payment"
+// @Success 200 {object} api.Payment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/payments/drop-ins [post]
+func (h PaymentsHandler) HandlerPayDropIn(w http.ResponseWriter, r *http.Request) {
+	var pay api.PayDropIn
+	err := json.NewDecoder(r.Body).Decode(&pay)
+	if err != nil || pay.ClassId <= 0 {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"class_id is required",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	userId, ok := actingUser(w, r, pay.UserId)
+	if !ok {
+		return
+	}
+
+	payment, err := h.uc.PayDropIn(r.Context(), userId, pay.ClassId)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, payment)
+}
+
+// HandlerGetPayment handles the HTTP request to get a payment.
+// @Description Get a payment by its ID
+// @Tags Payments
+// @Produce json
+// @Param paymentId path int true "Payment ID"
+// @Success 200 {object} api.Payment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/payments/{paymentId} [get]
+func (h PaymentsHandler) HandlerGetPayment(w http.ResponseWriter, r *http.Request) {
+	paymentId, ok := paymentIdParam(w, r)
+	if !ok {
+		return
+	}
+
+	payment, err := h.uc.GetPayment(r.Context(), paymentId)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, payment)
+}
+
+// HandlerGetUserPayments handles the HTTP request to get the payments of a user.
+// @Description Get the payments of a user, the latest first
+// @Tags Payments
+// @Produce json
+// @Param userId path int true "User ID"
+// @Success 200 {object} []api.Payment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/payments/users/{userId} [get]
+func (h PaymentsHandler) HandlerGetUserPayments(w http.ResponseWriter, r *http.Request) {
+	userId, err := strconv.Atoi(chi.URLParam(r, "userId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"UserId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	payments, err := h.uc.GetUserPayments(r.Context(), userId)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, payments)
+}
+
+// HandlerCapturePayment handles the HTTP request to capture a payment.
+// @Description Collect a pending payment with the payment provider, granting the membership or confirming the drop-in booking.
+// @Tags Payments
+// @Produce json
+// @Param paymentId path int true "Payment ID"
+// @Success 200 {object} api.Payment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/payments/{paymentId}/capture [post]
+func (h PaymentsHandler) HandlerCapturePayment(w http.ResponseWriter, r *http.Request) {
+	paymentId, ok := paymentIdParam(w, r)
+	if !ok {
+		return
+	}
+
+	payment, err := h.uc.Capture(r.Context(), paymentId)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, payment)
+}
+
+// HandlerRefundPayment handles the HTTP request to refund a payment.
+// @Description Refund a captured payment. A refunded membership ends now, keeping the bookings already made,
+// @Description and a refunded drop-in releases its booking to the first user in the waitlist.
+// @Tags Payments
+// @Produce json
+// @Param paymentId path int true "Payment ID"
+// @Success 200 {object} api.Payment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/payments/{paymentId}/refund [post]
+func (h PaymentsHandler) HandlerRefundPayment(w http.ResponseWriter, r *http.Request) {
+	paymentId, ok := paymentIdParam(w, r)
+	if !ok {
+		return
+	}
+
+	payment, err := h.uc.Refund(r.Context(), paymentId)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, payment)
+}
+
+// HandlerPaymentWebhook handles the callbacks of the payment provider.
+// @Description Callback of the payment provider, signed in the X-Payment-Signature header.
+// @Description A payment.succeeded event captures the payment, a payment.failed event releases its pending booking.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param X-Payment-Signature header string true "Signature of the body by the payment provider"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /v1/fitnessstudio/payments/webhook [post]
+func (h PaymentsHandler) HandlerPaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Callback body not readable",
+			"Read the documentation of the payment provider")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	err = h.uc.HandleWebhook(r.Context(), payload, r.Header.Get(PaymentSignatureHeader))
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, map[string]string{"message": "Event processed"})
+}
+
+func paymentIdParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	paymentId, err := strconv.Atoi(chi.URLParam(r, "paymentId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"PaymentId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return 0, false
+	}
+
+	return paymentId, true
+}
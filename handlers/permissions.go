@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/go-chi/chi"
+)
+
+// Permission decides whether the authenticated caller of a request may use a route.
+type Permission func(r *http.Request, caller auth.Identity) (bool, error)
+
+// Relation reports whether a user is related to a resource, e.g. teaches a class or made a payment.
+type Relation func(ctx context.Context, resourceId int, userId int) (bool, error)
+
+// Role grants a route to the callers with one of the roles.
+func Role(roles ...string) Permission {
+	return func(r *http.Request, caller auth.Identity) (bool, error) {
+		return slices.Contains(roles, caller.Role), nil
+	}
+}
+
+// Self grants a route to the user whose ID is the URL parameter, e.g. "userId".
+func Self(param string) Permission {
+	return func(r *http.Request, caller auth.Identity) (bool, error) {
+		userId, err := strconv.Atoi(chi.URLParam(r, param))
+		return err == nil && userId == caller.UserId, nil
+	}
+}
+
+// Related grants a route when the caller is related to the resource whose ID is the URL parameter.
+func Related(param string, relation Relation) Permission {
+	return func(r *http.Request, caller auth.Identity) (bool, error) {
+		resourceId, err := strconv.Atoi(chi.URLParam(r, param))
+		if err != nil {
+			return false, nil
+		}
+
+		return relation(r.Context(), resourceId, caller.UserId)
+	}
+}
+
+// All grants a route when every permission grants it, e.g. Role(api.RoleInstructor) and Related("classId", teaches).
+func All(permissions ...Permission) Permission {
+	return func(r *http.Request, caller auth.Identity) (bool, error) {
+		for _, permission := range permissions {
+			granted, err := permission(r, caller)
+			if err != nil || !granted {
+				return false, err
+			}
+		}
+
+		return true, nil
+	}
+}
+
+// Allow is the middleware letting through the requests granted by any of the permissions.
+//
+// It goes behind Authenticate. API keys are checked like their creator, with the current role of that user,
+// on top of their scopes checked by APIKeys. The requests not granted get a HTTP 403 error.
+//
+// param: permissions ...Permission - Permissions granting the routes, checked in order.
+//
+// @return func(http.Handler) http.Handler - Middleware for the chi routers.
+func Allow(permissions ...Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			caller, ok := auth.IdentityFrom(r.Context())
+			if !ok {
+				unauthorized(w, r, "The request has no bearer access token.")
+				return
+			}
+
+			for _, permission := range permissions {
+				granted, err := permission(r, caller)
+				if err != nil {
+					responseWithErrors(w, *r, err)
+					return
+				}
+
+				if granted {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			forbidden(w, r)
+		})
+	}
+}
+
+// DenyAPIKeys is the middleware keeping the API keys out of a route whatever their scopes,
+// e.g. to change the roles of the users.
+func DenyAPIKeys(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller, _ := auth.IdentityFrom(r.Context())
+		if caller.IsAPIKey() {
+			forbiddenKey(w, r, "API keys cannot be used for this operation.")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// actsFor checks that the caller of a request is the user or an owner, an API key acting as its creator, for the
+// routes taking the user in the body. It answers the request with a HTTP 403 error otherwise.
+func actsFor(w http.ResponseWriter, r *http.Request, userId int) bool {
+	caller, _ := auth.IdentityFrom(r.Context())
+	if caller.Role == api.RoleOwner || caller.UserId == userId {
+		return true
+	}
+
+	forbidden(w, r)
+	return false
+}
+
+// actingUser returns the user a request books or pays for: the user_id of the body for API keys and owners,
+// the caller otherwise. An API key whose creator is no longer an owner only acts for that user.
+// It answers the request with a HTTP 400 or 403 error when there is none.
+func actingUser(w http.ResponseWriter, r *http.Request, userId int) (int, bool) {
+	caller, _ := auth.IdentityFrom(r.Context())
+	if caller.IsAPIKey() && userId <= 0 {
+		e := utils.E(http.StatusBadRequest,
+			nil,
+			map[string]string{"message": "BadRequest"},
+			"user_id is required for API keys",
+			"Send the ID of the member in user_id.")
+
+		responseWithErrors(w, *r, e)
+		return 0, false
+	}
+
+	if userId == 0 || userId == caller.UserId {
+		return caller.UserId, true
+	}
+
+	if caller.Role != api.RoleOwner {
+		forbidden(w, r)
+		return 0, false
+	}
+
+	return userId, true
+}
+
+func forbidden(w http.ResponseWriter, r *http.Request) {
+	e := utils.E(http.StatusForbidden,
+		nil,
+		map[string]string{"message": "Forbidden"},
+		"Your account is not allowed to perform this operation.",
+		"Members can only manage their own bookings and profile, ask an owner of the studio.")
+
+	responseWithErrors(w, *r, e)
+}
@@ -0,0 +1,154 @@
+//go:build unittests
+// +build unittests
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Flgado/fitnessStudioApp/config"
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+// teaches is a relation where user 7 teaches class 1.
+func teaches(ctx context.Context, classId int, userId int) (bool, error) {
+	return classId == 1 && userId == 7, nil
+}
+
+func serve(t *testing.T, middleware func(http.Handler) http.Handler, pattern string, path string, caller *auth.Identity) *httptest.ResponseRecorder {
+	router := chi.NewRouter()
+	router.With(middleware).Get(pattern, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if caller != nil {
+		req = req.WithContext(auth.WithIdentity(req.Context(), *caller))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAllow(t *testing.T) {
+	owner := &auth.Identity{UserId: 1, Role: api.RoleOwner}
+	instructor := &auth.Identity{UserId: 7, Role: api.RoleInstructor}
+	member := &auth.Identity{UserId: 7, Role: api.RoleMember}
+	otherMember := &auth.Identity{UserId: 8, Role: api.RoleMember}
+
+	ownersOrSelf := Allow(Role(api.RoleOwner), Self("userId"))
+	teachers := Allow(Role(api.RoleOwner), All(Role(api.RoleInstructor), Related("classId", teaches)))
+
+	testCases := []struct {
+		testName       string
+		middleware     func(http.Handler) http.Handler
+		pattern        string
+		path           string
+		caller         *auth.Identity
+		expectedStatus int
+	}{
+		{"owner", ownersOrSelf, "/users/{userId}", "/users/7", owner, http.StatusOK},
+		{"self", ownersOrSelf, "/users/{userId}", "/users/7", member, http.StatusOK},
+		{"other member", ownersOrSelf, "/users/{userId}", "/users/7", otherMember, http.StatusForbidden},
+		{"anonymous", ownersOrSelf, "/users/{userId}", "/users/7", nil, http.StatusUnauthorized},
+		{"instructor of the class", teachers, "/classes/{classId}", "/classes/1", instructor, http.StatusOK},
+		{"instructor of another class", teachers, "/classes/{classId}", "/classes/2", instructor, http.StatusForbidden},
+		{"member related to the class", teachers, "/classes/{classId}", "/classes/1", member, http.StatusForbidden},
+		{"invalid class", teachers, "/classes/{classId}", "/classes/abc", instructor, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		// Act
+		w := serve(t, tc.middleware, tc.pattern, tc.path, tc.caller)
+
+		// Assert
+		assert.Equal(t, tc.expectedStatus, w.Code, tc.testName)
+		if tc.expectedStatus == http.StatusForbidden {
+			assert.Contains(t, w.Body.String(), `"Forbidden"`, tc.testName)
+		}
+	}
+}
+
+func TestAllow_RelationError(t *testing.T) {
+	failing := func(ctx context.Context, classId int, userId int) (bool, error) {
+		return false, errors.New("database is down")
+	}
+
+	w := serve(t, Allow(Related("classId", failing)), "/classes/{classId}", "/classes/1",
+		&auth.Identity{UserId: 7, Role: api.RoleInstructor})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+type fakeAPIKeys struct {
+	usecases.APIKeysUseCases
+}
+
+func (f fakeAPIKeys) Authenticate(ctx context.Context, key string) (auth.Identity, error) {
+	switch key {
+	case "fsk_kiosk":
+		return auth.Identity{UserId: 1, Role: api.RoleOwner, APIKeyId: 5, Scopes: []string{"bookings:write", "classes:read"}}, nil
+	case "fsk_demoted":
+		// the owner who created the key became a member
+		return auth.Identity{UserId: 1, Role: api.RoleMember, APIKeyId: 6, Scopes: []string{"bookings:write", "classes:read"}}, nil
+	}
+
+	return auth.Identity{}, utils.E(http.StatusUnauthorized, nil, map[string]string{"message": "Unauthorized"}, "", "")
+}
+
+func TestAPIKeys(t *testing.T) {
+	tokens, err := auth.NewTokens(config.Auth{TokenSecret: "test-token-secret-with-32-characters!"}, utils.SystemClock)
+	assert.NoError(t, err)
+
+	router := chi.NewRouter()
+	router.Use(APIKeys(fakeAPIKeys{}))
+	router.Group(func(r chi.Router) {
+		r.Use(Authenticate(tokens))
+		ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+		r.Get("/v1/fitnessstudio/classes", ok)
+		r.With(Allow(Role(api.RoleOwner))).Post("/v1/fitnessstudio/classes", ok)
+		r.With(Allow(Role(api.RoleOwner))).Delete("/v1/fitnessstudio/bookings/users/{userId}/classes/{classId}", ok)
+		r.With(DenyAPIKeys).Put("/v1/fitnessstudio/bookings/users/{userId}", ok)
+	})
+
+	testCases := []struct {
+		testName       string
+		method         string
+		path           string
+		key            string
+		expectedStatus int
+	}{
+		{"read scope", http.MethodGet, "/v1/fitnessstudio/classes", "fsk_kiosk", http.StatusOK},
+		{"write scope for a route of the owners", http.MethodDelete, "/v1/fitnessstudio/bookings/users/7/classes/1", "fsk_kiosk", http.StatusOK},
+		{"missing scope", http.MethodPost, "/v1/fitnessstudio/classes", "fsk_kiosk", http.StatusForbidden},
+		{"route denied to API keys", http.MethodPut, "/v1/fitnessstudio/bookings/users/7", "fsk_kiosk", http.StatusForbidden},
+		{"creator no longer an owner", http.MethodDelete, "/v1/fitnessstudio/bookings/users/7/classes/1", "fsk_demoted", http.StatusForbidden},
+		{"unknown key", http.MethodGet, "/v1/fitnessstudio/classes", "fsk_unknown", http.StatusUnauthorized},
+		{"no key nor token", http.MethodGet, "/v1/fitnessstudio/classes", "", http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		// Arrange
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.key != "" {
+			req.Header.Set(APIKeyHeader, tc.key)
+		}
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, tc.expectedStatus, w.Code, tc.testName)
+	}
+}
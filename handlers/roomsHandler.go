@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/go-chi/chi"
+)
+
+type RoomsHandler struct {
+	uc usecases.RoomsUseCases
+}
+
+func NewRoomsHandler(uc usecases.RoomsUseCases) *RoomsHandler {
+	return &RoomsHandler{uc: uc}
+}
+
+// HandlerGetRooms handles the HTTP request to get all rooms.
+// @Description Get all rooms
+// @Tags Rooms
+// @Produce json
+// @Success 200 {object} []api.Room
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/rooms [get]
+func (h RoomsHandler) HandlerGetRooms(w http.ResponseWriter, r *http.Request) {
+	rooms, err := h.uc.GetAllRooms(r.Context())
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, rooms)
+}
+
+// HandlerGetRoomById handles the HTTP request to get a room by ID.
+// @Description Get a room by ID
+// @Tags Rooms
+// @Produce json
+// @Param roomId path int true "Room ID"
+// @Success 200 {object} api.Room
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/rooms/{roomId} [get]
+func (h RoomsHandler) HandlerGetRoomById(w http.ResponseWriter, r *http.Request) {
+	roomId, err := strconv.Atoi(chi.URLParam(r, "roomId"))
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"RoomId should be integer",
+			"Read our documentation")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	room, err := h.uc.GetRoomById(r.Context(), roomId)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, room)
+}
+
+// HandlerCreateRoom handles the HTTP request to create a new room.
+// @Description Create a new room.
+// @Tags Rooms
+// @Produce json
+// @Param request body api.CreateRoom true "Room data to create"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/rooms [post]
+func (h RoomsHandler) HandlerCreateRoom(w http.ResponseWriter, r *http.Request) {
+	var room api.CreateRoom
+	err := json.NewDecoder(r.Body).Decode(&room)
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Request body not expected",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	room.Name = strings.TrimSpace(room.Name)
+	if room.Name == "" {
+		e := utils.E(http.StatusBadRequest,
+			nil,
+			map[string]string{"message": "BadRequest"},
+			"Room name should not be empty",
+			"Use a valid room name")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	err = h.uc.CreateRoom(r.Context(), room)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, map[string]string{"message": "Room created with Success"})
+}
+
+// HandlerUpdateRoom handles the HTTP request to update a room.
+// @Description Update a room
+// @Tags Rooms
+// @Produce json
+// @Param request body api.PatchRoom true "Room data to update"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/rooms [patch]
+func (h RoomsHandler) HandlerUpdateRoom(w http.ResponseWriter, r *http.Request) {
+	room := api.PatchRoom{}
+	err := json.NewDecoder(r.Body).Decode(&room)
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Request body not expected",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	if room.Name != nil {
+		name := strings.TrimSpace(*room.Name)
+		if name == "" {
+			e := utils.E(http.StatusBadRequest,
+				nil,
+				map[string]string{"message": "BadRequest"},
+				"Room name should not be empty",
+				"Use a valid room name")
+
+			responseWithErrors(w, *r, e)
+			return
+		}
+		room.Name = &name
+	}
+
+	_, err = h.uc.UpdateRoom(r.Context(), room)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, map[string]string{"message": "Room Succesfull updated"})
+}
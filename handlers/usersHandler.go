@@ -3,8 +3,11 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"net/mail"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
 	"github.com/Flgado/fitnessStudioApp/internal/usecases"
@@ -24,20 +27,43 @@ type GetAllUsers struct {
 	Users []api.UpdateUser
 } // @name GetAllUsers
 
-// HandlerGetUsers handles the HTTP request to get all users.
-// @Description Get all users
+// HandlerGetUsers handles the HTTP request to search the users.
+// @Description Search the users, a page at a time. The search matches part of the name or of the email, case insensitive.
+// @Description The number of users matching the search, in all the pages, is in the X-Total-Count header.
 // @Tags Users
 // @Produce json
+// @Param q query string false "Part of the name or of the email"
+// @Param name query string false "Part of the name"
+// @Param email query string false "Part of the email"
+// @Param role query string false "Role of the users (owner, instructor or member)"
+// @Param sort query string false "Sort field: id, name, email or create_date, prefixed with - for descending order, e.g. -create_date. Default id"
+// @Param page query integer false "Page, starting at 1. Default 1"
+// @Param pageSize query integer false "Users per page, up to 200. Default 50"
 // @Success 200 {object} []User
+// @Header 200 {integer} X-Total-Count "Number of users matching the search"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /v1/fitnessstudio/users [get]
 func (h UsersHandler) HandlerGetUsers(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	users, err := h.uc.GetAllUsers(ctx)
+
+	filters, err := buildUserFilters(r.URL.Query())
 	if err != nil {
 		responseWithErrors(w, *r, err)
 		return
 	}
+
+	users, total, err := h.uc.SearchUsers(ctx, filters)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
 	respondWithJson(w, 200, users)
 }
 
@@ -49,7 +75,11 @@ func (h UsersHandler) HandlerGetUsers(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} User
 // @Failure 404 {object} ErrorResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /v1/fitnessstudio/users/{userId} [get]
 func (h UsersHandler) HandlerGetUserById(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -76,14 +106,55 @@ func (h UsersHandler) HandlerGetUserById(w http.ResponseWriter, r *http.Request)
 	respondWithJson(w, 200, user)
 }
 
+// HandlerGetUserByEmail handles the HTTP request to look up a user by email.
+// @Description Get the user with a contact email, case insensitive
+// @Tags Users
+// @Produce json
+// @Param email query string true "Email of the user"
+// @Success 200 {object} User
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /v1/fitnessstudio/users/by-email [get]
+func (h UsersHandler) HandlerGetUserByEmail(w http.ResponseWriter, r *http.Request) {
+	email := strings.TrimSpace(r.URL.Query().Get("email"))
+	if email == "" {
+		e := utils.E(http.StatusBadRequest,
+			nil,
+			map[string]string{"message": "BadRequest"},
+			"email query parameter is required",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	user, err := h.uc.GetUserByEmail(r.Context(), email)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, user)
+}
+
 // HandlerCreateUser handles the HTTP request to create a new user.
-// @Description Create a new user.
+// @Description Create a new user. Only the name is required, the email is unique and the birth date is in the format YYYY-MM-DD.
 // @Tags Users
 // @Produce json
 // @Param request body api.CreateUser true "User data to create"
 // @Success 200  {object} api.CreateUser
 // @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /v1/fitnessstudio/users [post]
 func (h UsersHandler) HandlerCreateUser(w http.ResponseWriter, r *http.Request) {
 	var user api.CreateUser
@@ -99,20 +170,32 @@ func (h UsersHandler) HandlerCreateUser(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if user.Name == "" {
-		e := utils.E(http.StatusBadRequest,
-			err,
-			map[string]string{"message": "BadRequest"},
-			"User name should not be empty",
-			"Use a valid user name")
+	if user.Name, err = validateUserName(user.Name); err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
 
-		responseWithErrors(w, *r, e)
+	if user.Email, err = validateUserEmail(user.Email); err != nil {
+		responseWithErrors(w, *r, err)
 		return
 	}
 
-	user.Name = strings.TrimSpace(user.Name)
-	err = h.uc.CreateUser(r.Context(), user.Name)
+	if user.Phone, err = validatePhone("Phone", user.Phone); err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
 
+	if user.BirthDate, err = validateBirthDate(user.BirthDate, time.Now()); err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	if user.EmergencyContact, err = validateEmergencyContact(user.EmergencyContact); err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	err = h.uc.CreateUser(r.Context(), user)
 	if err != nil {
 		responseWithErrors(w, *r, err)
 		return
@@ -122,17 +205,24 @@ func (h UsersHandler) HandlerCreateUser(w http.ResponseWriter, r *http.Request)
 }
 
 // HandlerUpdateUser handles the HTTP request to update a user.
-// @Description Update a user
+// @Description Update the profile of a user, only the fields sent are changed. Members can only update their own profile.
+// @Description An empty email, phone or birth date and an emergency contact without name and phone clear them.
+// @Description The email is also the sign in email of the users with credentials: changing it changes how the user signs in, and it cannot be cleared.
 // @Tags Users
 // @Produce json
-// @Param request body api.User true "User data to update"
+// @Param request body api.PatchUser true "User data to update"
 // @Success 200
 // @Failure 404 {object} ErrorResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Router /v1/fitnessstudio/users [patch]
 func (h UsersHandler) HandlerUpdateUser(w http.ResponseWriter, r *http.Request) {
-	user := api.User{}
+	user := api.PatchUser{}
 	err := json.NewDecoder(r.Body).Decode(&user)
 	if err != nil {
 		e := utils.E(http.StatusBadRequest,
@@ -145,22 +235,224 @@ func (h UsersHandler) HandlerUpdateUser(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	user.Name = strings.TrimSpace(user.Name)
-	if user.Name == "" {
+	if !actsFor(w, r, user.Id) {
+		return
+	}
+
+	if user.Name != nil {
+		name, err := validateUserName(*user.Name)
+		if err != nil {
+			responseWithErrors(w, *r, err)
+			return
+		}
+		user.Name = &name
+	}
+
+	if user.Email != nil {
+		email, err := validateUserEmail(*user.Email)
+		if err != nil {
+			responseWithErrors(w, *r, err)
+			return
+		}
+		user.Email = &email
+	}
+
+	if user.Phone != nil {
+		phone, err := validatePhone("Phone", *user.Phone)
+		if err != nil {
+			responseWithErrors(w, *r, err)
+			return
+		}
+		user.Phone = &phone
+	}
+
+	if user.BirthDate != nil {
+		birthDate, err := validateBirthDate(*user.BirthDate, time.Now())
+		if err != nil {
+			responseWithErrors(w, *r, err)
+			return
+		}
+		user.BirthDate = &birthDate
+	}
+
+	if user.EmergencyContact != nil {
+		contact, err := validateEmergencyContact(user.EmergencyContact)
+		if err != nil {
+			responseWithErrors(w, *r, err)
+			return
+		}
+
+		// an emergency contact without name and phone clears it
+		if contact == nil {
+			contact = &api.EmergencyContact{}
+		}
+		user.EmergencyContact = contact
+	}
+
+	_, err = h.uc.UpdateUser(r.Context(), user)
+	if err != nil {
+		responseWithErrors(w, *r, err)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, map[string]string{"Success": "Updated user"})
+}
+
+// HandlerSetUserRole handles the HTTP request to change the role of a user.
+// @Description Change the role of a user: owner, instructor or member. The user gets the new role from the next sign in.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param userId path int true "User ID"
+// @Param request body api.SetRole true "New role"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {string} string "Internal Server Error"
+// @Security BearerAuth
+// @Router /v1/fitnessstudio/users/{userId}/role [put]
+func (h UsersHandler) HandlerSetUserRole(w http.ResponseWriter, r *http.Request) {
+	userId, err := strconv.Atoi(chi.URLParam(r, "userId"))
+	if err != nil {
 		e := utils.E(http.StatusBadRequest,
 			err,
 			map[string]string{"message": "BadRequest"},
-			"User name should not be empty",
-			"Use a valid user name")
+			"UserId should be integer",
+			"Read our documentation")
 
 		responseWithErrors(w, *r, e)
 		return
 	}
-	_, err = h.uc.UpdateUser(r.Context(), user)
+
+	var role api.SetRole
+	err = json.NewDecoder(r.Body).Decode(&role)
+	if err != nil {
+		e := utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Request body not expected",
+			"Read our documentation for more details")
+
+		responseWithErrors(w, *r, e)
+		return
+	}
+
+	err = h.uc.SetUserRole(r.Context(), userId, role.Role)
 	if err != nil {
 		responseWithErrors(w, *r, err)
 		return
 	}
 
-	respondWithJson(w, http.StatusOK, map[string]string{"Success": "Updated user"})
+	respondWithJson(w, http.StatusOK, map[string]string{"message": "User role updated with Success"})
+}
+
+// validateUserName trims the name of a user, which has between 1 and 50 characters.
+func validateUserName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 50 {
+		return "", invalidProfileError("User name should have between 1 and 50 characters", "Use a valid user name")
+	}
+
+	return name, nil
+}
+
+// validateUserEmail trims and lowercases the email of a user. An empty email is accepted, users are not
+// required to have one.
+func validateUserEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return "", nil
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email || len(email) > 254 {
+		return "", invalidProfileError("User email is not valid", "Use a valid email address, e.g. member@studio.com")
+	}
+
+	return email, nil
+}
+
+// validatePhone trims a phone number made of digits, spaces and the characters +-(), with at least 6 digits.
+// An empty phone number is accepted.
+func validatePhone(field string, phone string) (string, error) {
+	phone = strings.TrimSpace(phone)
+	if phone == "" {
+		return "", nil
+	}
+
+	invalid := invalidProfileError(field+" is not a valid phone number",
+		"Use up to 30 digits, spaces and the characters +-(), e.g. +351 912 345 678")
+
+	digits := 0
+	for _, c := range phone {
+		if c >= '0' && c <= '9' {
+			digits++
+			continue
+		}
+
+		if !strings.ContainsRune(" +-()", c) {
+			return "", invalid
+		}
+	}
+
+	if digits < 6 || len(phone) > 30 {
+		return "", invalid
+	}
+
+	return phone, nil
+}
+
+// validateBirthDate checks a birth date in the format YYYY-MM-DD, between 1900 and today.
+// An empty birth date is accepted.
+func validateBirthDate(date string, today time.Time) (string, error) {
+	date = strings.TrimSpace(date)
+	if date == "" {
+		return "", nil
+	}
+
+	birthDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", buildFormatParameterError(err, "birth_date")
+	}
+
+	if birthDate.Year() < 1900 || birthDate.After(today) {
+		return "", invalidProfileError("Birth date should be between 1900-01-01 and today", "Use a valid birth date")
+	}
+
+	return date, nil
+}
+
+// validateEmergencyContact checks that an emergency contact has a name and a valid phone number.
+// A contact without name and phone is no contact, nil is returned.
+func validateEmergencyContact(contact *api.EmergencyContact) (*api.EmergencyContact, error) {
+	if contact == nil {
+		return nil, nil
+	}
+
+	name := strings.TrimSpace(contact.Name)
+	phone, err := validatePhone("Emergency contact phone", contact.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" && phone == "" {
+		return nil, nil
+	}
+
+	if name == "" || phone == "" || utf8.RuneCountInString(name) > 50 {
+		return nil, invalidProfileError("Emergency contact should have a name of up to 50 characters and a phone number",
+			"Send both the name and the phone of the emergency contact")
+	}
+
+	return &api.EmergencyContact{Name: name, Phone: phone}, nil
+}
+
+func invalidProfileError(details string, suggestion string) error {
+	return utils.E(http.StatusBadRequest,
+		nil,
+		map[string]string{"message": "BadRequest"},
+		details,
+		suggestion)
 }
@@ -0,0 +1,136 @@
+//go:build unittests
+// +build unittests
+
+package handlers
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateUserProfile(t *testing.T) {
+	today := time.Date(2030, time.March, 17, 10, 0, 0, 0, time.UTC)
+
+	t.Run("email", func(t *testing.T) {
+		testCases := []struct {
+			email    string
+			expected string
+			valid    bool
+		}{
+			{email: "", expected: "", valid: true},
+			{email: " Ana@Studio.com ", expected: "ana@studio.com", valid: true},
+			{email: "ana", valid: false},
+			{email: "Ana <ana@studio.com>", valid: false},
+		}
+
+		for _, tc := range testCases {
+			// Act
+			email, err := validateUserEmail(tc.email)
+
+			// Assert
+			assert.Equal(t, tc.valid, err == nil, tc.email)
+			assert.Equal(t, tc.expected, email, tc.email)
+		}
+	})
+
+	t.Run("phone", func(t *testing.T) {
+		testCases := []struct {
+			phone string
+			valid bool
+		}{
+			{phone: "", valid: true},
+			{phone: "+351 (912) 345-678", valid: true},
+			{phone: "12345", valid: false},
+			{phone: "912 345 678 ext", valid: false},
+			{phone: "+351 912 345 678 912 345 678 912", valid: false},
+		}
+
+		for _, tc := range testCases {
+			// Act
+			_, err := validatePhone("Phone", tc.phone)
+
+			// Assert
+			assert.Equal(t, tc.valid, err == nil, tc.phone)
+		}
+	})
+
+	t.Run("birth date", func(t *testing.T) {
+		testCases := []struct {
+			date  string
+			valid bool
+		}{
+			{date: "", valid: true},
+			{date: "1990-05-17", valid: true},
+			{date: "2030-03-17", valid: true},
+			{date: "2030-03-18", valid: false},
+			{date: "1899-12-31", valid: false},
+			{date: "17/05/1990", valid: false},
+		}
+
+		for _, tc := range testCases {
+			// Act
+			_, err := validateBirthDate(tc.date, today)
+
+			// Assert
+			assert.Equal(t, tc.valid, err == nil, tc.date)
+		}
+	})
+
+	t.Run("emergency contact", func(t *testing.T) {
+		testCases := []struct {
+			contact  *api.EmergencyContact
+			expected *api.EmergencyContact
+			valid    bool
+		}{
+			{contact: nil, expected: nil, valid: true},
+			{contact: &api.EmergencyContact{Name: " ", Phone: ""}, expected: nil, valid: true},
+			{contact: &api.EmergencyContact{Name: " Rita ", Phone: "912 345 678"}, expected: &api.EmergencyContact{Name: "Rita", Phone: "912 345 678"}, valid: true},
+			{contact: &api.EmergencyContact{Name: "Rita"}, valid: false},
+			{contact: &api.EmergencyContact{Phone: "912 345 678"}, valid: false},
+			{contact: &api.EmergencyContact{Name: "Rita", Phone: "call me"}, valid: false},
+		}
+
+		for _, tc := range testCases {
+			// Act
+			contact, err := validateEmergencyContact(tc.contact)
+
+			// Assert
+			assert.Equal(t, tc.valid, err == nil)
+			assert.Equal(t, tc.expected, contact)
+		}
+	})
+}
+
+func TestBuildUserFilters(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected api.UserFilters
+		valid    bool
+	}{
+		{query: "", expected: api.UserFilters{}, valid: true},
+		{query: "q=+ana+&role=member&sort=-create_date&page=2&pageSize=20",
+			expected: api.UserFilters{Search: "ana", Role: api.RoleMember, SortBy: "create_date", Descending: true, Page: 2, PageSize: 20}, valid: true},
+		{query: "name=ana&email=studio&sort=name", expected: api.UserFilters{Name: "ana", Email: "studio", SortBy: "name"}, valid: true},
+		{query: "role=admin", valid: false},
+		{query: "sort=password", valid: false},
+		{query: "page=0", valid: false},
+		{query: "pageSize=201", valid: false},
+		{query: "pageSize=ten", valid: false},
+	}
+
+	for _, tc := range testCases {
+		// Arrange
+		values, _ := url.ParseQuery(tc.query)
+
+		// Act
+		filters, err := buildUserFilters(values)
+
+		// Assert
+		assert.Equal(t, tc.valid, err == nil, tc.query)
+		assert.Equal(t, tc.expected, filters, tc.query)
+	}
+}
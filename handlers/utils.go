@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/recurrence"
 	"github.com/Flgado/fitnessStudioApp/utils"
 )
 
@@ -47,9 +49,37 @@ func buildClassFilters(urlValues url.Values) (api.ClasseFilters, error) {
 		if err != nil {
 			return api.ClasseFilters{}, buildFormatParameterError(err, "endDate")
 		}
+		// Include the classes running during the end date
+		endDate = endDate.AddDate(0, 0, 1).Add(-time.Microsecond)
 		filters.EndDateLe = &endDate
 	}
 
+	// Parse room
+	if roomIdStr := urlValues.Get("roomId"); roomIdStr != "" {
+		roomId, err := strconv.Atoi(roomIdStr)
+		if err != nil {
+			return api.ClasseFilters{}, buildFormatParameterError(err, "roomId")
+		}
+		filters.RoomId = &roomId
+	}
+
+	// Parse instructor
+	if instructorIdStr := urlValues.Get("instructorId"); instructorIdStr != "" {
+		instructorId, err := strconv.Atoi(instructorIdStr)
+		if err != nil {
+			return api.ClasseFilters{}, buildFormatParameterError(err, "instructorId")
+		}
+		filters.InstructorId = &instructorId
+	}
+
+	// Parse status
+	if status := urlValues.Get("status"); status != "" {
+		if status != api.ClassStatusScheduled && status != api.ClassStatusCancelled {
+			return api.ClasseFilters{}, buildFormatParameterError(fmt.Errorf("unknown class status %q", status), "status")
+		}
+		filters.Status = status
+	}
+
 	// Parse capacity greater than or equal to
 	if capacityGteStr := urlValues.Get("capacityGte"); capacityGteStr != "" {
 		capacityGte, err := strconv.Atoi(capacityGteStr)
@@ -89,10 +119,63 @@ func buildClassFilters(urlValues url.Values) (api.ClasseFilters, error) {
 	return filters, nil
 }
 
-func BuildUpdateClass(date *string, name *string, capacity *int) (api.UpdateClass, error) {
-	layout := "2006-01-02"
+// buildUserFilters parses the URL query parameters to build the search of the users.
+//
+// param urlValues url.Values - URL query parameters.
+//
+// return api.UserFilters - Search, sort and page of the users, zero values for the defaults.
+// return error - Error if any parameter fails to parse.
+func buildUserFilters(urlValues url.Values) (api.UserFilters, error) {
+	filters := api.UserFilters{
+		Search: strings.TrimSpace(urlValues.Get("q")),
+		Name:   strings.TrimSpace(urlValues.Get("name")),
+		Email:  strings.TrimSpace(urlValues.Get("email")),
+	}
+
+	// Parse role
+	if role := urlValues.Get("role"); role != "" {
+		if !api.ValidRole(role) {
+			return api.UserFilters{}, buildFormatParameterError(fmt.Errorf("unknown role %q", role), "role")
+		}
+		filters.Role = role
+	}
+
+	// Parse sort, descending when prefixed with -
+	if sort := urlValues.Get("sort"); sort != "" {
+		filters.SortBy, filters.Descending = strings.CutPrefix(sort, "-")
+		if !slices.Contains(api.UserSortFields, filters.SortBy) {
+			return api.UserFilters{}, buildFormatParameterError(fmt.Errorf("unknown sort field %q", filters.SortBy), "sort")
+		}
+	}
+
+	// Parse page
+	if pageStr := urlValues.Get("page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil || page < 1 {
+			return api.UserFilters{}, buildFormatParameterError(err, "page")
+		}
+		filters.Page = page
+	}
+
+	// Parse page size
+	if pageSizeStr := urlValues.Get("pageSize"); pageSizeStr != "" {
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil || pageSize < 1 || pageSize > api.UsersMaxPageSize {
+			return api.UserFilters{}, buildFormatParameterError(err, "pageSize")
+		}
+		filters.PageSize = pageSize
+	}
+
+	return filters, nil
+}
+
+func BuildUpdateClass(date *string, name *string, duration *int, capacity *int, roomId *int, instructorId *int) (api.UpdateClass, error) {
+	if err := validateRoomId(roomId); err != nil {
+		return api.UpdateClass{}, err
+	}
+
 	if date != nil {
-		newDate, err := time.Parse(layout, *date)
+		newDate, err := parseClassDate(*date)
 		if err != nil {
 			return api.UpdateClass{}, utils.E(http.StatusBadRequest,
 				err,
@@ -102,19 +185,121 @@ func BuildUpdateClass(date *string, name *string, capacity *int) (api.UpdateClas
 		}
 
 		return api.UpdateClass{
-			Name:     name,
-			Date:     &newDate,
-			Capacity: capacity,
+			Name:         name,
+			Date:         &newDate,
+			Duration:     duration,
+			Capacity:     capacity,
+			RoomId:       roomId,
+			InstructorId: instructorId,
 		}, nil
 	}
 
 	return api.UpdateClass{
-		Name:     name,
-		Date:     nil,
-		Capacity: capacity,
+		Name:         name,
+		Date:         nil,
+		Duration:     duration,
+		Capacity:     capacity,
+		RoomId:       roomId,
+		InstructorId: instructorId,
 	}, nil
 }
 
+// validateRoomId checks the room of a class update, a class always takes place in a room.
+//
+// param roomId *int - Room to move the classes to, nil to keep their room.
+//
+// return error - HTTP 400 error if the room id is not positive.
+func validateRoomId(roomId *int) error {
+	if roomId != nil && *roomId <= 0 {
+		return utils.E(http.StatusBadRequest,
+			nil,
+			map[string]string{"message": "BadRequest"},
+			"room_id is required",
+			"Select the room where the classes take place")
+	}
+
+	return nil
+}
+
+// parseClassDate parses a class date with an optional time of day.
+//
+// It accepts RFC3339 timestamps, dates with hours and minutes (2006-01-02T15:04)
+// and plain dates (2006-01-02), which start at midnight.
+//
+// param s string - Date to parse.
+//
+// return time.Time - Parsed date.
+// return error - Error if the date matches none of the accepted layouts.
+func parseClassDate(s string) (time.Time, error) {
+	var err error
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"} {
+		var date time.Time
+		date, err = time.Parse(layout, s)
+		if err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, err
+}
+
+// parseStartTime parses the time of day (15:04) at which a class starts.
+//
+// It returns the offset from midnight, or zero when no start time is given.
+//
+// param s string - Start time to parse.
+//
+// return time.Duration - Offset of the start time from midnight.
+// return error - Error if the start time is not in the HH:MM format.
+func parseStartTime(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, buildFormatParameterError(err, "start_time")
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// parseRecurrence parses the recurrence rule and the excluded days of a class scheduler.
+//
+// An empty rule returns a nil recurrence, the classes are then scheduled every day.
+//
+// param rrule string - iCalendar recurrence rule (e.g. FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10).
+// param exDates []string - Days without class in the format YYYY-MM-DD.
+//
+// return *recurrence.Rule - Parsed rule or nil.
+// return []time.Time - Parsed excluded days.
+// return error - Error if the rule or any of the days fails to parse.
+func parseRecurrence(rrule string, exDates []string) (*recurrence.Rule, []time.Time, error) {
+	var rule *recurrence.Rule
+	if strings.TrimSpace(rrule) != "" {
+		parsed, err := recurrence.Parse(rrule)
+		if err != nil {
+			return nil, nil, utils.E(http.StatusBadRequest,
+				err,
+				map[string]string{"message": "BadRequest"},
+				fmt.Sprintf("Invalid rrule: %s", err.Error()),
+				"Use an iCalendar rule such as FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10")
+		}
+		rule = &parsed
+	}
+
+	days := make([]time.Time, 0, len(exDates))
+	for _, exDate := range exDates {
+		day, err := time.Parse("2006-01-02", exDate)
+		if err != nil {
+			return nil, nil, buildFormatParameterError(err, "exdates")
+		}
+		days = append(days, day)
+	}
+
+	return rule, days, nil
+}
+
 // buildFormatParameterError constructs a formatted error for wrong parameter format.
 //
 // This function takes an error describing the failure to parse a parameter,
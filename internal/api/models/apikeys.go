@@ -0,0 +1,32 @@
+package api
+
+import "time"
+
+// @Description API key of a machine client, e.g. the front-desk kiosk. Scopes are <resource>:<read|write>,
+// @Description e.g. bookings:write. The key acts on behalf of the owner who created it, and loses the routes
+// @Description of the owners when that user is no longer an owner.
+type APIKey struct {
+	Id        int      `json:"id"`
+	Name      string   `json:"name"`
+	Prefix    string   `json:"prefix"`
+	Scopes    []string `json:"scopes"`
+	CreatedBy int      `json:"created_by"`
+	// Current role of the creator, loaded when the key is used
+	CreatorRole  string     `json:"-" swaggerignore:"true"`
+	CreateDate   time.Time  `json:"create_date"`
+	LastUsedDate *time.Time `json:"last_used_date,omitempty"`
+	RevokedDate  *time.Time `json:"revoked_date,omitempty"`
+} //@name APIKey
+
+// @Description CreateAPIKey
+type CreateAPIKey struct {
+	Name   string   `json:"name" validate:"required,max=50" example:"Front desk kiosk"`
+	Scopes []string `json:"scopes" validate:"required" example:"bookings:write,classes:read"`
+} //@name CreateAPIKey
+
+// @Description NewAPIKey is returned once when the key is created, only its hash is stored.
+// @Description Send the key in the X-API-Key header.
+type NewAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+} //@name NewAPIKey
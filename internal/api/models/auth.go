@@ -0,0 +1,21 @@
+package api
+
+// @Description Register creates a user with the credentials to sign in.
+type Register struct {
+	Name     string `json:"name" validate:"required,len=1,max=50"`
+	Email    string `json:"email" validate:"required" example:"member@studio.com"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+} //@name Register
+
+// @Description Login credentials.
+type Login struct {
+	Email    string `json:"email" validate:"required" example:"member@studio.com"`
+	Password string `json:"password" validate:"required"`
+} //@name Login
+
+// @Description Access token to send in the Authorization header as "Bearer <access_token>". ExpiresIn is in seconds.
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+} //@name Token
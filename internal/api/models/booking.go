@@ -2,11 +2,28 @@ package api
 
 import "time"
 
+const (
+	BookingStatusBooked     = "booked"
+	BookingStatusWaitlisted = "waitlisted"
+	// BookingStatusPendingPayment is a drop-in holding its seat until the payment succeeds
+	BookingStatusPendingPayment = "pending_payment"
+)
+
+const (
+	AttendancePending  = "pending"
+	AttendanceAttended = "attended"
+	AttendanceNoShow   = "no_show"
+)
+
 type ClassBooked struct {
-	Id           int       `json:"class_id,omitempty"`
-	Name         string    `json:"class_name,omitempty"`
-	Date         time.Time `json:"class_date,omitempty"`
-	ReservedDate time.Time `json:"reserved_date,omitempty"`
+	Id               int        `json:"class_id,omitempty"`
+	Name             string     `json:"class_name,omitempty"`
+	Date             time.Time  `json:"class_date,omitempty"`
+	ReservedDate     time.Time  `json:"reserved_date,omitempty"`
+	Status           string     `json:"status,omitempty"`
+	WaitlistPosition int        `json:"waitlist_position,omitempty"`
+	AttendanceStatus string     `json:"attendance_status,omitempty"`
+	CheckedInAt      *time.Time `json:"checked_in_at,omitempty"`
 } // @name ClassBooked
 
 type UsersBooked struct {
@@ -15,7 +32,23 @@ type UsersBooked struct {
 	UserName string `json:"user_name,omitempty"`
 } // @ UsersBooked
 
+// MakeBooking books a class for the authenticated user.
+// UserId is only used by owners and API keys, e.g. the front-desk kiosk, to book for a member.
 type MakeBooking struct {
 	ClassId int `json:"class_id,omitempty"`
 	UserId  int `json:"user_id,omitempty"`
 } // @name MakeBooking
+
+type BookingResult struct {
+	ClassId          int    `json:"class_id,omitempty"`
+	UserId           int    `json:"user_id,omitempty"`
+	Status           string `json:"status,omitempty"`
+	WaitlistPosition int    `json:"waitlist_position,omitempty"`
+} // @name BookingResult
+
+// CancellationResult Late reports a cancellation within the late cancellation window of the class policy.
+type CancellationResult struct {
+	ClassId int  `json:"class_id,omitempty"`
+	UserId  int  `json:"user_id,omitempty"`
+	Late    bool `json:"late"`
+} // @name CancellationResult
@@ -1,50 +1,139 @@
 package api
 
-import "time"
+import (
+	"time"
 
+	"github.com/Flgado/fitnessStudioApp/internal/recurrence"
+)
+
+// ClassSchedulerReceiver describes the classes to schedule. Without RRule a class is
+// created every day between StartDate and EndDate. RRule is an iCalendar recurrence rule
+// (e.g. "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10"); with COUNT or UNTIL the EndDate is optional.
+// ExDates lists the days (YYYY-MM-DD) without class. Type selects the booking policy of the classes.
 type ClassSchedulerReceiver struct {
-	Name      string `json:"name" validate:"required,len=1,max=50"`
-	StartDate string `json:"start_date" validate:"required"`
-	EndDate   string `json:"end_date" validate:"required,gtefield=StartDate"`
-	Capacity  int    `json:"capacity"`
+	Name         string   `json:"name" validate:"required,len=1,max=50"`
+	Type         string   `json:"class_type,omitempty" example:"yoga"`
+	StartDate    string   `json:"start_date" validate:"required"`
+	EndDate      string   `json:"end_date" validate:"required,gtefield=StartDate"`
+	StartTime    string   `json:"start_time"`
+	Duration     int      `json:"duration"`
+	Capacity     int      `json:"capacity"`
+	RoomId       int      `json:"room_id" validate:"required"`
+	InstructorId int      `json:"instructor_id,omitempty"`
+	RRule        string   `json:"rrule,omitempty" example:"FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10"`
+	ExDates      []string `json:"exdates,omitempty"`
 } //@name ClassSchedulerReceiver
 
 type ClassScheduler struct {
-	Name      string    `json:"name" validate:"required,len=1,max=50"`
-	StartDate time.Time `json:"start_date" validate:"required"`
-	EndDate   time.Time `json:"end_date" validate:"required,gtefield=StartDate"`
-	Capacity  int       `json:"capacity"`
+	Name         string    `json:"name" validate:"required,len=1,max=50"`
+	Type         string    `json:"class_type,omitempty"`
+	StartDate    time.Time `json:"start_date" validate:"required"`
+	EndDate      time.Time `json:"end_date" validate:"required,gtefield=StartDate"`
+	Duration     int       `json:"duration"`
+	Capacity     int       `json:"capacity"`
+	RoomId       int       `json:"room_id"`
+	InstructorId int       `json:"instructor_id"`
+	// Recurrence defaults to one class per day when nil, RRule keeps its text for the series
+	Recurrence *recurrence.Rule `json:"-" swaggerignore:"true"`
+	RRule      string           `json:"-" swaggerignore:"true"`
+	ExDates    []time.Time      `json:"-" swaggerignore:"true"`
 } //@name ClassScheduler
 
+const (
+	ClassStatusScheduled = "scheduled"
+	ClassStatusCancelled = "cancelled"
+)
+
+// NotificationClassCancelled is the outbox event of a member whose booked class was cancelled.
+const NotificationClassCancelled = "class_cancelled"
+
 type ReadClass struct {
 	Id int `json:"id,omitempty"`
 	Class
-	NumRegistrations int `json:"num_registrations,omitempty"`
+	Status           string `json:"status,omitempty"`
+	NumRegistrations int    `json:"num_registrations,omitempty"`
 } // @name ReadClass
 
+// Class Date holds the start of the class and Duration its length in minutes.
 type Class struct {
-	Name     string    `json:"name"`
-	Date     time.Time `json:"date"`
-	Capacity int       `json:"capacity"`
+	Name         string    `json:"name"`
+	Type         string    `json:"class_type,omitempty"`
+	Date         time.Time `json:"date"`
+	Duration     int       `json:"duration"`
+	Capacity     int       `json:"capacity"`
+	RoomId       int       `json:"room_id,omitempty"`
+	InstructorId int       `json:"instructor_id,omitempty"`
+	// SeriesId groups the classes created by the same scheduler request
+	SeriesId int `json:"series_id,omitempty"`
 } // @name Class
 
+// End returns the time at which the class finishes.
+func (c Class) End() time.Time {
+	return c.Date.Add(time.Duration(c.Duration) * time.Minute)
+}
+
 type PatchClass struct {
-	Id       int     `json:"id,omitempty"`
-	Name     *string `json:"name,omitempty" validate:"len=1,max=50"`
-	Date     *string `json:"date,omitempty"`
-	Capacity *int    `json:"capacity,omitempty"`
+	Id           int     `json:"id,omitempty"`
+	Name         *string `json:"name,omitempty" validate:"len=1,max=50"`
+	Date         *string `json:"date,omitempty"`
+	Duration     *int    `json:"duration,omitempty"`
+	Capacity     *int    `json:"capacity,omitempty"`
+	RoomId       *int    `json:"room_id,omitempty"`
+	InstructorId *int    `json:"instructor_id,omitempty"`
 } // @name PatchClass
 
 type UpdateClass struct {
-	Name     *string
-	Date     *time.Time
-	Capacity *int
+	Name         *string
+	Date         *time.Time
+	Duration     *int
+	Capacity     *int
+	RoomId       *int
+	InstructorId *int
 } // @name UpdateClass
 
+// PatchSeries updates a class and all the following occurrences of its series.
+// StartTime (HH:MM) moves every occurrence to that time of its own day.
+type PatchSeries struct {
+	Name         *string `json:"name,omitempty" validate:"len=1,max=50"`
+	StartTime    *string `json:"start_time,omitempty"`
+	Duration     *int    `json:"duration,omitempty"`
+	Capacity     *int    `json:"capacity,omitempty"`
+	RoomId       *int    `json:"room_id,omitempty"`
+	InstructorId *int    `json:"instructor_id,omitempty"`
+} // @name PatchSeries
+
+// UpdateSeries StartTime is the offset of the new start from midnight.
+type UpdateSeries struct {
+	Name         *string
+	StartTime    *time.Duration
+	Duration     *int
+	Capacity     *int
+	RoomId       *int
+	InstructorId *int
+} // @name UpdateSeries
+
+// OccurrenceUpdate is the update of one class of a series.
+type OccurrenceUpdate struct {
+	ClassId int
+	Update  UpdateClass
+}
+
+// ScheduleFilter selects the scheduled classes of a month sharing the time slots of a room
+// (RoomId 0 for the classes without room) or, when InstructorId is set, of an instructor.
+type ScheduleFilter struct {
+	RoomId       int
+	InstructorId int
+	Year         int
+	Month        time.Month
+}
+
 type ClasseFilters struct {
 	Name                string
 	StartDateGte        *time.Time
 	EndDateLe           *time.Time
+	RoomId              *int
+	InstructorId        *int
+	Status              string
 	CapacityGte         *int
 	CapacityLe          *int
 	NumRegistrationsGte *int
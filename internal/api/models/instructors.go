@@ -0,0 +1,30 @@
+package api
+
+// @Description Instructor teaching the classes of the studio. UserId is the account the instructor signs in with.
+type Instructor struct {
+	Id     int    `json:"id,omitempty"`
+	UserId int    `json:"user_id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Email  string `json:"email,omitempty"`
+	Phone  string `json:"phone,omitempty"`
+	Bio    string `json:"bio,omitempty"`
+} //@name Instructor
+
+// @Description CreateInstructor
+type CreateInstructor struct {
+	Name   string `json:"name" validate:"required,len=1,max=50"`
+	UserId int    `json:"user_id,omitempty"`
+	Email  string `json:"email,omitempty" validate:"max=100"`
+	Phone  string `json:"phone,omitempty" validate:"max=30"`
+	Bio    string `json:"bio,omitempty"`
+} //@name CreateInstructor
+
+// @Description PatchInstructor. A user_id of 0 unlinks the account of the instructor.
+type PatchInstructor struct {
+	Id     int     `json:"id,omitempty"`
+	UserId *int    `json:"user_id,omitempty"`
+	Name   *string `json:"name,omitempty" validate:"len=1,max=50"`
+	Email  *string `json:"email,omitempty" validate:"max=100"`
+	Phone  *string `json:"phone,omitempty" validate:"max=30"`
+	Bio    *string `json:"bio,omitempty"`
+} //@name PatchInstructor
@@ -0,0 +1,46 @@
+package api
+
+import "time"
+
+const (
+	PlanTypeUnlimited = "unlimited"
+	PlanTypeCredits   = "credits"
+)
+
+// @Description Membership plan sold by the studio. Credit packs include Credits classes,
+// @Description unlimited plans any number of classes during ValidityDays. Price is in cents.
+type Plan struct {
+	Id           int    `json:"id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Type         string `json:"plan_type,omitempty"`
+	Credits      int    `json:"credits,omitempty"`
+	ValidityDays int    `json:"validity_days,omitempty"`
+	Price        int    `json:"price_cents,omitempty"`
+} //@name Plan
+
+// @Description CreatePlan
+type CreatePlan struct {
+	Name         string `json:"name" validate:"required,len=1,max=50"`
+	Type         string `json:"plan_type" validate:"required" example:"credits"`
+	Credits      int    `json:"credits,omitempty" example:"10"`
+	ValidityDays int    `json:"validity_days" validate:"required" example:"90"`
+	Price        int    `json:"price_cents,omitempty" example:"12000"`
+} //@name CreatePlan
+
+// @Description AssignMembership gives a plan to a user. ValidFrom (YYYY-MM-DD) defaults to today.
+type AssignMembership struct {
+	PlanId    int    `json:"plan_id" validate:"required"`
+	ValidFrom string `json:"valid_from,omitempty" example:"2024-03-01"`
+} //@name AssignMembership
+
+// @Description Membership of a user. RemainingCredits is only set for credit packs.
+type Membership struct {
+	Id               int       `json:"id,omitempty"`
+	UserId           int       `json:"user_id,omitempty"`
+	PlanId           int       `json:"plan_id,omitempty"`
+	PlanName         string    `json:"plan_name,omitempty"`
+	Type             string    `json:"plan_type,omitempty"`
+	ValidFrom        time.Time `json:"valid_from"`
+	ValidUntil       time.Time `json:"valid_until"`
+	RemainingCredits *int      `json:"remaining_credits,omitempty"`
+} //@name Membership
@@ -0,0 +1,47 @@
+package api
+
+import "time"
+
+const (
+	PaymentPurposeMembership = "membership"
+	PaymentPurposeDropIn     = "drop_in"
+)
+
+const (
+	PaymentStatusPending  = "pending"
+	PaymentStatusCaptured = "captured"
+	PaymentStatusFailed   = "failed"
+	PaymentStatusRefunded = "refunded"
+)
+
+// @Description Payment of a membership plan or of a drop-in class. Amount is in cents.
+// @Description ClientSecret is only returned when the payment is created, to complete it with the provider.
+type Payment struct {
+	Id           int       `json:"id,omitempty"`
+	UserId       int       `json:"user_id,omitempty"`
+	Purpose      string    `json:"purpose,omitempty"`
+	PlanId       int       `json:"plan_id,omitempty"`
+	ClassId      int       `json:"class_id,omitempty"`
+	MembershipId int       `json:"membership_id,omitempty"`
+	Amount       int       `json:"amount_cents"`
+	Currency     string    `json:"currency,omitempty"`
+	Status       string    `json:"status,omitempty"`
+	Provider     string    `json:"provider,omitempty"`
+	ProviderRef  string    `json:"provider_ref,omitempty"`
+	ClientSecret string    `json:"client_secret,omitempty"`
+	CreateDate   time.Time `json:"create_date"`
+} //@name Payment
+
+// @Description PayMembership buys a membership plan for the authenticated user.
+// @Description user_id is only used by owners and API keys, to pay for a member.
+type PayMembership struct {
+	PlanId int `json:"plan_id" validate:"required"`
+	UserId int `json:"user_id,omitempty"`
+} //@name PayMembership
+
+// @Description PayDropIn buys a single class for the authenticated user, holding the seat until the payment succeeds.
+// @Description user_id is only used by owners and API keys, to pay for a member.
+type PayDropIn struct {
+	ClassId int `json:"class_id" validate:"required"`
+	UserId  int `json:"user_id,omitempty"`
+} //@name PayDropIn
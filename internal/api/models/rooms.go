@@ -0,0 +1,21 @@
+package api
+
+// @Description Room where the classes take place
+type Room struct {
+	Id          int    `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	MaxCapacity int    `json:"max_capacity,omitempty"`
+} //@name Room
+
+// @Description CreateRoom
+type CreateRoom struct {
+	Name        string `json:"name" validate:"required,len=1,max=50"`
+	MaxCapacity int    `json:"max_capacity" validate:"required"`
+} //@name CreateRoom
+
+// @Description PatchRoom
+type PatchRoom struct {
+	Id          int     `json:"id,omitempty"`
+	Name        *string `json:"name,omitempty" validate:"len=1,max=50"`
+	MaxCapacity *int    `json:"max_capacity,omitempty"`
+} //@name PatchRoom
@@ -1,21 +1,86 @@
 package api
 
+const (
+	RoleOwner      = "owner"
+	RoleInstructor = "instructor"
+	RoleMember     = "member"
+)
+
+// ValidRole reports whether a role is one of the roles of the studio.
+func ValidRole(role string) bool {
+	return role == RoleOwner || role == RoleInstructor || role == RoleMember
+}
+
 type UsersList struct {
 	Users []User `json:"users,omitempty"`
 }
 
-// @Description UserModel
+const (
+	UsersDefaultPageSize = 50
+	UsersMaxPageSize     = 200
+)
+
+// UserSortFields are the fields the users can be sorted by.
+var UserSortFields = []string{"id", "name", "email", "create_date"}
+
+// UserFilters search the users. Search, Name and Email match part of the value, case insensitive,
+// and Search matches the name or the email. Pages start at 1.
+type UserFilters struct {
+	Search     string
+	Name       string
+	Email      string
+	Role       string
+	SortBy     string
+	Descending bool
+	Page       int
+	PageSize   int
+} // @name UserFilters
+
+// @Description UserModel. Role is owner, instructor or member, and is only changed through the role route.
+// @Description The birth date is in the format YYYY-MM-DD.
 type User struct {
-	Id   int    `json:"id,omitempty"`
-	Name string `json:"name,omitempty" validate:"required,len=1,max=50"`
+	Id               int               `json:"id,omitempty"`
+	Name             string            `json:"name,omitempty" validate:"required,len=1,max=50"`
+	Email            string            `json:"email,omitempty"`
+	Phone            string            `json:"phone,omitempty"`
+	BirthDate        string            `json:"birth_date,omitempty" example:"1990-05-17"`
+	EmergencyContact *EmergencyContact `json:"emergency_contact,omitempty"`
+	Role             string            `json:"role,omitempty"`
 } //@name User
 
+// @Description Person to call in case of emergency
+type EmergencyContact struct {
+	Name  string `json:"name" validate:"max=50"`
+	Phone string `json:"phone" validate:"max=30"`
+} //@name EmergencyContact
+
 // @Description UpdateUser Information
 type UpdateUser struct {
 	Name string `json:"name" validate:"required,len=1,max=50"`
 } //@name Update User
 
-// @Description CreateUser
+// @Description CreateUser. Only the name is required, the email is unique and the birth date is in the format YYYY-MM-DD.
 type CreateUser struct {
-	Name string `json:"name" validate:"required,len=1,max=50"`
+	Name             string            `json:"name" validate:"required,len=1,max=50"`
+	Email            string            `json:"email,omitempty" validate:"max=254" example:"member@studio.com"`
+	Phone            string            `json:"phone,omitempty" validate:"max=30" example:"+351 912 345 678"`
+	BirthDate        string            `json:"birth_date,omitempty" example:"1990-05-17"`
+	EmergencyContact *EmergencyContact `json:"emergency_contact,omitempty"`
 } //@name CreateUser
+
+// @Description PatchUser changes the fields sent. An empty email, phone or birth date
+// @Description and an emergency contact without name and phone clear them.
+// @Description The email is also the sign in email of the users with credentials, who cannot clear it.
+type PatchUser struct {
+	Id               int               `json:"id,omitempty"`
+	Name             *string           `json:"name,omitempty" validate:"len=1,max=50"`
+	Email            *string           `json:"email,omitempty" validate:"max=254"`
+	Phone            *string           `json:"phone,omitempty" validate:"max=30"`
+	BirthDate        *string           `json:"birth_date,omitempty" example:"1990-05-17"`
+	EmergencyContact *EmergencyContact `json:"emergency_contact,omitempty"`
+} //@name PatchUser
+
+// @Description SetRole changes the role of a user: owner, instructor or member
+type SetRole struct {
+	Role string `json:"role" validate:"required" example:"instructor"`
+} //@name SetRole
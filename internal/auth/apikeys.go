@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"strings"
+)
+
+const (
+	// apiKeyPrefix marks the keys of the studio, e.g. in secret scanners
+	apiKeyPrefix = "fsk_"
+	// apiKeyDisplayLength is the length of the start of a key kept to identify it
+	apiKeyDisplayLength = 12
+
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// apiPath is the path of the routes API keys can use.
+const apiPath = "/v1/fitnessstudio/"
+
+// ScopeResources are the resources API keys can be scoped to, <resource>:read or <resource>:write.
+var ScopeResources = []string{"bookings", "classes", "instructors", "memberships", "payments", "rooms", "users"}
+
+// GenerateAPIKey creates a random API key.
+//
+// @return string - Key to hand to the client, never stored.
+// @return string - Start of the key, to identify it in listings.
+// @return string - Hash of the key to store.
+// @return error - Error if the random source fails.
+func GenerateAPIKey() (string, string, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:apiKeyDisplayLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex SHA-256 of an API key. Keys are random, so a fast hash is enough.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidScope reports whether a scope is <resource>:read or <resource>:write of a known resource.
+func ValidScope(scope string) bool {
+	resource, access, found := strings.Cut(scope, ":")
+	return found && slices.Contains(ScopeResources, resource) && (access == ScopeRead || access == ScopeWrite)
+}
+
+// ScopeFor returns the scope an API key needs for a request: read for GET and HEAD, write otherwise.
+//
+// param: method string - HTTP method of the request.
+// param: path string - Path of the request.
+//
+// @return string - Scope needed, e.g. bookings:write.
+// @return bool - False if API keys cannot use the path, e.g. the sign in or the API key management.
+func ScopeFor(method string, path string) (string, bool) {
+	rest, found := strings.CutPrefix(path, apiPath)
+	if !found {
+		return "", false
+	}
+
+	resource, _, _ := strings.Cut(rest, "/")
+	if !slices.Contains(ScopeResources, resource) {
+		return "", false
+	}
+
+	access := ScopeWrite
+	if method == "GET" || method == "HEAD" {
+		access = ScopeRead
+	}
+
+	return resource + ":" + access, true
+}
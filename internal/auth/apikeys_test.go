@@ -0,0 +1,58 @@
+//go:build unittests
+// +build unittests
+
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, hash, err := GenerateAPIKey()
+	assert.NoError(t, err)
+
+	other, _, otherHash, err := GenerateAPIKey()
+	assert.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key, "fsk_"))
+	assert.True(t, strings.HasPrefix(key, prefix))
+	assert.Len(t, prefix, apiKeyDisplayLength)
+	assert.Equal(t, HashAPIKey(key), hash)
+	assert.Len(t, hash, 64)
+	assert.NotEqual(t, key, other)
+	assert.NotEqual(t, hash, otherHash)
+}
+
+func TestValidScope(t *testing.T) {
+	assert.True(t, ValidScope("bookings:write"))
+	assert.True(t, ValidScope("classes:read"))
+	assert.False(t, ValidScope("classes"))
+	assert.False(t, ValidScope("classes:delete"))
+	assert.False(t, ValidScope("api-keys:read"))
+}
+
+func TestScopeFor(t *testing.T) {
+	testCases := []struct {
+		method   string
+		path     string
+		expected string
+		ok       bool
+	}{
+		{"GET", "/v1/fitnessstudio/classes", "classes:read", true},
+		{"GET", "/v1/fitnessstudio/bookings/classes/1/users", "bookings:read", true},
+		{"POST", "/v1/fitnessstudio/bookings", "bookings:write", true},
+		{"DELETE", "/v1/fitnessstudio/bookings/users/1/classes/2", "bookings:write", true},
+		{"POST", "/v1/fitnessstudio/auth/login", "", false},
+		{"GET", "/v1/fitnessstudio/api-keys", "", false},
+		{"GET", "/swagger/index.html", "", false},
+	}
+
+	for _, tc := range testCases {
+		scope, ok := ScopeFor(tc.method, tc.path)
+		assert.Equal(t, tc.ok, ok, tc.path)
+		assert.Equal(t, tc.expected, scope, tc.path)
+	}
+}
@@ -0,0 +1,16 @@
+package auth
+
+import "context"
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of the context carrying the authenticated caller.
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFrom returns the authenticated caller of the context, false if the request is anonymous.
+func IdentityFrom(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password length limits, bcrypt ignores the bytes after the 72nd.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// HashPassword hashes a password with bcrypt for the credentials of a user.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether a password matches the hash of the credentials of a user.
+func CheckPassword(hash string, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// dummyHash is checked when the user is unknown, so a failed login takes the same time
+// whether the email exists or not.
+var dummyHash = sync.OnceValue(func() string {
+	hash, _ := HashPassword("fitnessstudio-unknown-user")
+	return hash
+})
+
+// CheckNoPassword spends the time of a password check for a login of an unknown user, and always fails.
+func CheckNoPassword(password string) bool {
+	CheckPassword(dummyHash(), password)
+	return false
+}
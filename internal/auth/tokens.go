@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Flgado/fitnessStudioApp/config"
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// issuer identifies the tokens signed by the studio
+	issuer = "fitnessstudio"
+	// minSecretLength is the minimum length of the token secret, 256 bits for HS256
+	minSecretLength = 32
+	// defaultTokenTTL is used when the configuration has no TokenTTL
+	defaultTokenTTL = time.Hour
+)
+
+// ErrInvalidToken is returned when an access token is malformed, expired or not signed by the studio.
+var ErrInvalidToken = errors.New("invalid access token")
+
+// Identity is the authenticated caller of a request.
+//
+// For an API key, UserId is the owner who created the key, and the scopes of the key apply instead of the role.
+type Identity struct {
+	UserId   int
+	Role     string
+	APIKeyId int
+	Scopes   []string
+}
+
+// IsAPIKey reports whether the caller is a machine client signed with an API key.
+func (i Identity) IsAPIKey() bool {
+	return i.APIKeyId != 0
+}
+
+// claims of the access tokens. The role is the one of the user when the token was issued,
+// so a role change applies from the next sign in.
+type claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// Tokens issues and verifies the access tokens of the users, JWTs signed with HS256.
+type Tokens struct {
+	secret []byte
+	ttl    time.Duration
+	clock  utils.Clock
+}
+
+// NewTokens builds the token issuer from the studio configuration.
+//
+// param: cfg config.Auth - Authentication configuration.
+// param: clock utils.Clock - Clock giving the issue time and checking the expiry of the tokens.
+//
+// @return *Tokens - Token issuer.
+// @return error - Error if the token secret is too short or the TTL is negative.
+func NewTokens(cfg config.Auth, clock utils.Clock) (*Tokens, error) {
+	if len(cfg.TokenSecret) < minSecretLength {
+		return nil, fmt.Errorf("auth: the token secret must have at least %d characters", minSecretLength)
+	}
+	if cfg.TokenTTL < 0 {
+		return nil, errors.New("auth: the token TTL cannot be negative")
+	}
+
+	ttl := cfg.TokenTTL
+	if ttl == 0 {
+		ttl = defaultTokenTTL
+	}
+
+	return &Tokens{secret: []byte(cfg.TokenSecret), ttl: ttl, clock: clock}, nil
+}
+
+// Issue signs an access token for a user.
+//
+// param: identity Identity - User the token authenticates.
+//
+// @return api.Token - Access token, with its lifetime.
+// @return error - Error if the token cannot be signed.
+func (t *Tokens) Issue(identity Identity) (api.Token, error) {
+	now := t.clock.Now()
+	c := claims{
+		Role: identity.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   strconv.Itoa(identity.UserId),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(t.ttl)),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString(t.secret)
+	if err != nil {
+		return api.Token{}, err
+	}
+
+	return api.Token{
+		AccessToken: signed,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(t.ttl.Seconds()),
+	}, nil
+}
+
+// Verify checks the signature, issuer, expiry and role of an access token.
+//
+// param: token string - Access token, without the "Bearer " prefix.
+//
+// @return Identity - User the token authenticates.
+// @return error - ErrInvalidToken if the token is not valid.
+func (t *Tokens) Verify(token string) (Identity, error) {
+	c := claims{}
+	_, err := jwt.ParseWithClaims(token, &c,
+		func(*jwt.Token) (interface{}, error) { return t.secret, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(t.clock.Now),
+	)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	userId, err := strconv.Atoi(c.Subject)
+	if err != nil || userId <= 0 {
+		return Identity{}, fmt.Errorf("%w: invalid subject %q", ErrInvalidToken, c.Subject)
+	}
+
+	if !api.ValidRole(c.Role) {
+		return Identity{}, fmt.Errorf("%w: invalid role %q", ErrInvalidToken, c.Role)
+	}
+
+	return Identity{UserId: userId, Role: c.Role}, nil
+}
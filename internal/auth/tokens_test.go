@@ -0,0 +1,107 @@
+//go:build unittests
+// +build unittests
+
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Flgado/fitnessStudioApp/config"
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "test-token-secret-with-32-characters!"
+
+func testTokens(t *testing.T, now *time.Time) *Tokens {
+	tokens, err := NewTokens(config.Auth{TokenSecret: testSecret, TokenTTL: time.Hour},
+		utils.ClockFunc(func() time.Time { return *now }))
+	assert.NoError(t, err)
+	return tokens
+}
+
+func TestNewTokens(t *testing.T) {
+	tokens, err := NewTokens(config.Auth{TokenSecret: testSecret}, utils.SystemClock)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultTokenTTL, tokens.ttl)
+
+	_, err = NewTokens(config.Auth{TokenSecret: "short"}, utils.SystemClock)
+	assert.Error(t, err)
+
+	_, err = NewTokens(config.Auth{TokenSecret: testSecret, TokenTTL: -time.Minute}, utils.SystemClock)
+	assert.Error(t, err)
+}
+
+func TestTokens_IssueAndVerify(t *testing.T) {
+	// Arrange
+	now := time.Date(2030, 3, 17, 10, 0, 0, 0, time.UTC)
+	tokens := testTokens(t, &now)
+
+	// Act
+	token, err := tokens.Issue(Identity{UserId: 7, Role: api.RoleMember})
+	assert.NoError(t, err)
+	identity, err := tokens.Verify(token.AccessToken)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, Identity{UserId: 7, Role: api.RoleMember}, identity)
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.Equal(t, 3600, token.ExpiresIn)
+}
+
+func TestTokens_VerifyRejectsInvalidTokens(t *testing.T) {
+	now := time.Date(2030, 3, 17, 10, 0, 0, 0, time.UTC)
+	tokens := testTokens(t, &now)
+	token, err := tokens.Issue(Identity{UserId: 7, Role: api.RoleMember})
+	assert.NoError(t, err)
+
+	otherSecret, err := NewTokens(config.Auth{TokenSecret: testSecret + "other"}, utils.ClockFunc(func() time.Time { return now }))
+	assert.NoError(t, err)
+	forged, err := otherSecret.Issue(Identity{UserId: 7, Role: api.RoleMember})
+	assert.NoError(t, err)
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims{
+		Role: api.RoleOwner,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   "7",
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.NoError(t, err)
+
+	unknownRole, err := tokens.Issue(Identity{UserId: 7, Role: "admin"})
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		testName string
+		token    string
+	}{
+		{"malformed", "not-a-token"},
+		{"signed with another secret", forged.AccessToken},
+		{"not signed", unsigned},
+		{"unknown role", unknownRole.AccessToken},
+	}
+
+	for _, tc := range testCases {
+		_, err := tokens.Verify(tc.token)
+		assert.ErrorIs(t, err, ErrInvalidToken, tc.testName)
+	}
+
+	// expired
+	now = now.Add(time.Hour + time.Second)
+	_, err = tokens.Verify(token.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestPasswords(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	assert.NoError(t, err)
+
+	assert.True(t, CheckPassword(hash, "correct horse"))
+	assert.False(t, CheckPassword(hash, "wrong horse"))
+	assert.False(t, CheckNoPassword("correct horse"))
+}
@@ -0,0 +1,21 @@
+package apikeys
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type APIKeyRow struct {
+	Id           int            `db:"id"`
+	Name         string         `db:"key_name"`
+	Prefix       string         `db:"key_prefix"`
+	Hash         string         `db:"key_hash"`
+	Scopes       pq.StringArray `db:"scopes"`
+	CreatedBy    int            `db:"created_by"`
+	CreatorRole  string         `db:"creator_role"`
+	CreateDate   time.Time      `db:"create_date"`
+	LastUsedDate sql.NullTime   `db:"last_used_date"`
+	RevokedDate  sql.NullTime   `db:"revoked_date"`
+}
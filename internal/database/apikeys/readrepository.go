@@ -0,0 +1,65 @@
+package apikeys
+
+import (
+	"context"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type ReadRepository interface {
+	List(ctx context.Context) ([]api.APIKey, error)
+}
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewReadRepository(db *sqlx.DB) ReadRepository {
+	return &repository{db: db}
+}
+
+// List retrieves all the API keys, revoked ones included.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+//
+// @return []api.APIKey - Slice of APIKey structs, without the keys themselves.
+// @return error - Error if there is an issue retrieving the keys from the database.
+func (r *repository) List(ctx context.Context) ([]api.APIKey, error) {
+	rows := []APIKeyRow{}
+
+	err := r.db.SelectContext(ctx, &rows, findAPIKeys)
+	if err != nil {
+		return nil, errors.Wrap(err, "apiKeysRepo.List.SelectContext")
+	}
+
+	keys := make([]api.APIKey, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, toAPIKey(row))
+	}
+
+	return keys, nil
+}
+
+func toAPIKey(row APIKeyRow) api.APIKey {
+	key := api.APIKey{
+		Id:          row.Id,
+		Name:        row.Name,
+		Prefix:      row.Prefix,
+		Scopes:      []string(row.Scopes),
+		CreatedBy:   row.CreatedBy,
+		CreatorRole: row.CreatorRole,
+		CreateDate:  row.CreateDate,
+	}
+
+	if row.LastUsedDate.Valid {
+		key.LastUsedDate = &row.LastUsedDate.Time
+	}
+
+	if row.RevokedDate.Valid {
+		key.RevokedDate = &row.RevokedDate.Time
+	}
+
+	return key
+}
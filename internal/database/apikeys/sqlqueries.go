@@ -0,0 +1,32 @@
+package apikeys
+
+const (
+	findAPIKeys = `SELECT id, key_name, key_prefix, key_hash, scopes, created_by, create_date, last_used_date, revoked_date
+					FROM api_keys
+					ORDER BY id`
+
+	addAPIKey = `INSERT INTO api_keys (key_name, key_prefix, key_hash, scopes, created_by)
+					VALUES ($1, $2, $3, $4, $5)
+					RETURNING id, create_date`
+
+	revokeAPIKey = `UPDATE api_keys SET revoked_date = $2 WHERE id = $1 AND revoked_date IS NULL`
+
+	// useAPIKey finds an active key with the current role of its creator and records its use in the same round trip.
+	// The use is only written when the recorded one is older than a minute, so a busy client does not update the row on every request
+	useAPIKey = `WITH active AS (
+					SELECT k.id, k.key_name, k.key_prefix, k.key_hash, k.scopes, k.created_by, u.user_role AS creator_role,
+						k.create_date, k.last_used_date, k.revoked_date
+					FROM api_keys k
+					INNER JOIN users u ON u.id = k.created_by
+					WHERE k.key_hash = $1 AND k.revoked_date IS NULL
+				), used AS (
+					UPDATE api_keys k SET last_used_date = $2::timestamptz
+					FROM active a
+					WHERE k.id = a.id
+						AND (a.last_used_date IS NULL OR a.last_used_date < $2::timestamptz - interval '1 minute')
+					RETURNING k.last_used_date
+				)
+				SELECT id, key_name, key_prefix, key_hash, scopes, created_by, creator_role, create_date,
+					COALESCE((SELECT last_used_date FROM used), last_used_date) AS last_used_date, revoked_date
+				FROM active`
+)
@@ -0,0 +1,87 @@
+package apikeys
+
+import (
+	"context"
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type WriteRepository interface {
+	Add(ctx context.Context, createdBy int, key api.CreateAPIKey, prefix string, hash string) (api.APIKey, error)
+	Revoke(ctx context.Context, keyId int, at time.Time) (int64, error)
+	Use(ctx context.Context, hash string, at time.Time) (api.APIKey, error)
+}
+
+func NewWriteRepository(db *sqlx.DB) WriteRepository {
+	return &repository{db: db}
+}
+
+// Add stores a new API key by its hash.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: createdBy int - ID of the owner creating the key.
+// param: key api.CreateAPIKey - Name and scopes of the key.
+// param: prefix string - Start of the key, to identify it in listings.
+// param: hash string - Hash of the key.
+//
+// @return api.APIKey - The stored key.
+// @return error - Error if there is an issue inserting the key into the database.
+func (r *repository) Add(ctx context.Context, createdBy int, key api.CreateAPIKey, prefix string, hash string) (api.APIKey, error) {
+	row := APIKeyRow{
+		Name:      key.Name,
+		Prefix:    prefix,
+		Hash:      hash,
+		Scopes:    pq.StringArray(key.Scopes),
+		CreatedBy: createdBy,
+	}
+
+	err := r.db.QueryRowContext(ctx, addAPIKey, row.Name, row.Prefix, row.Hash, row.Scopes, row.CreatedBy).
+		Scan(&row.Id, &row.CreateDate)
+	if err != nil {
+		return api.APIKey{}, err
+	}
+
+	return toAPIKey(row), nil
+}
+
+// Revoke stops an API key from being accepted. Revoking a key twice keeps the first revocation date.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: keyId int - ID of the key.
+// param: at time.Time - Revocation time.
+//
+// @return int64 - Number of keys revoked, 0 if the key does not exist or is already revoked.
+// @return error - Error if there is an issue updating the key in the database.
+func (r *repository) Revoke(ctx context.Context, keyId int, at time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, revokeAPIKey, keyId, at)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// Use finds an active API key by its hash, with the current role of its creator, and records it as last used now.
+//
+// The last use is only written when the recorded one is more than a minute old, so the date is coarse
+// and the row is not updated on every request of a busy client. It returns sql.ErrNoRows if no active key has the hash.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: hash string - Hash of the key sent by the client.
+// param: at time.Time - Time of the request.
+//
+// @return api.APIKey - The key used.
+// @return error - Error if the key is not found or there is an issue updating it in the database.
+func (r *repository) Use(ctx context.Context, hash string, at time.Time) (api.APIKey, error) {
+	row := APIKeyRow{}
+
+	err := r.db.GetContext(ctx, &row, useAPIKey, hash, at)
+	if err != nil {
+		return api.APIKey{}, err
+	}
+
+	return toAPIKey(row), nil
+}
@@ -9,14 +9,18 @@ type BokingRow struct {
 }
 
 type ClassBookedRow struct {
-	Id               int       `db:"id"`
-	Name             string    `db:"class_name"`
-	Date             time.Time `db:"class_date"`
-	Capacity         int       `db:"class_capacity"`
-	NumRegistrations int       `db:"num_registrations"`
-	CreateDate       time.Time `db:"create_date"`
-	LastUpdateDate   time.Time `db:"last_update_date"`
-	ReservedDate     time.Time `db:"reserved_date"`
+	Id               int        `db:"id"`
+	Name             string     `db:"class_name"`
+	Date             time.Time  `db:"class_date"`
+	Capacity         int        `db:"class_capacity"`
+	NumRegistrations int        `db:"num_registrations"`
+	CreateDate       time.Time  `db:"create_date"`
+	LastUpdateDate   time.Time  `db:"last_update_date"`
+	ReservedDate     time.Time  `db:"reserved_date"`
+	Status           string     `db:"status"`
+	WaitlistPosition int        `db:"waitlist_position"`
+	AttendanceStatus string     `db:"attendance_status"`
+	CheckedInAt      *time.Time `db:"checked_in_at"`
 }
 
 type UserBookedRow struct {
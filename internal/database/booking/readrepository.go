@@ -34,16 +34,20 @@ func (r *repository) GetUserBookings(ctx context.Context, userId int) ([]api.Cla
 
 	for rows.Next() {
 		var classRow ClassBookedRow
-		if err = rows.Scan(&classRow.Id, &classRow.Name, &classRow.Date, &classRow.Capacity, &classRow.NumRegistrations, &classRow.ReservedDate); err != nil {
+		if err = rows.Scan(&classRow.Id, &classRow.Name, &classRow.Date, &classRow.Capacity, &classRow.NumRegistrations, &classRow.ReservedDate, &classRow.Status, &classRow.WaitlistPosition, &classRow.AttendanceStatus, &classRow.CheckedInAt); err != nil {
 			return nil, err
 		}
 
 		// Convert ClassRow to ReadClass
 		readClass := api.ClassBooked{
-			Id:           classRow.Id,
-			Name:         classRow.Name,
-			Date:         classRow.Date,
-			ReservedDate: classRow.ReservedDate,
+			Id:               classRow.Id,
+			Name:             classRow.Name,
+			Date:             classRow.Date,
+			ReservedDate:     classRow.ReservedDate,
+			Status:           classRow.Status,
+			WaitlistPosition: classRow.WaitlistPosition,
+			AttendanceStatus: classRow.AttendanceStatus,
+			CheckedInAt:      classRow.CheckedInAt,
 		}
 
 		bc = append(bc, readClass)
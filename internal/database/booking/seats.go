@@ -0,0 +1,217 @@
+package booking
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/database/waitlist"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+// Drop-in bookings are paid per class instead of with a membership credit. The seat is held
+// as pending_payment while the payment is processed, so these helpers run inside the
+// transaction changing the payment.
+
+// HoldSeat books a seat of a class for a user waiting for the payment of a drop-in.
+//
+// The class row is locked until the transaction ends, and the class must not have started at the given time
+// and must pass the check of the caller. A drop-in never joins the waitlist. It returns a HTTP 404 error if the user or the class does not exist,
+// a HTTP 409 error if the user already booked the class or the class is full and a HTTP 422 error
+// if the class is cancelled, in progress or finished.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: tx *sqlx.Tx - Transaction of the payment.
+// param: userId int - ID of the user.
+// param: classId int - ID of the class.
+// param: now time.Time - Time of the booking.
+// param: check BookingCheck - Additional check of the locked class, nil for none.
+//
+// @return error - Error if the seat cannot be held.
+func HoldSeat(ctx context.Context, tx *sqlx.Tx, userId int, classId int, now time.Time, check BookingCheck) error {
+	_, numRegistrations, classCapacity, err := lockClassToBook(ctx, tx, userId, classId, now, check)
+	if err != nil {
+		return err
+	}
+
+	var booked int
+	err = tx.QueryRowContext(ctx, countUserBookings, userId, classId).Scan(&booked)
+	if err != nil {
+		return err
+	}
+
+	if booked > 0 {
+		return utils.E(http.StatusConflict,
+			nil,
+			map[string]string{"message": "Conflict Status"},
+			fmt.Sprintf("Class with Id: %d is already reserved by User with id %d", classId, userId),
+			"Validate user reserved classes")
+	}
+
+	if numRegistrations >= classCapacity {
+		return utils.E(http.StatusConflict,
+			nil,
+			map[string]string{"message": "Class Full"},
+			"The specified class has no free seat for a drop-in.",
+			"Please book the class with a membership to join its waitlist.")
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE classes SET num_registrations = num_registrations + 1 WHERE id = $1", classId)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, addPendingBooking, userId, classId)
+	return err
+}
+
+// ErrSeatReleased is returned by ConfirmSeat when the seat held by a drop-in was released before its payment
+// succeeded, e.g. cancelled by the member. The payment must not be captured.
+var ErrSeatReleased = errors.New("the seat held by the drop-in was released")
+
+// ConfirmSeat confirms the seat held by a drop-in once its payment succeeds.
+//
+// A booking cancelled while its payment was processed is not booked again, ErrSeatReleased is returned instead.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: tx *sqlx.Tx - Transaction of the payment.
+// param: paymentId int - ID of the payment of the drop-in.
+//
+// @return error - ErrSeatReleased if there is no held seat, or an error if there is an issue confirming the booking.
+func ConfirmSeat(ctx context.Context, tx *sqlx.Tx, paymentId int) error {
+	result, err := tx.ExecContext(ctx, confirmPendingBooking, paymentId)
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if confirmed == 0 {
+		return ErrSeatReleased
+	}
+
+	return nil
+}
+
+// ReleaseSeat cancels the booking held by the payment of a drop-in that failed or was refunded.
+//
+// The released seat goes to the first user in the waitlist. A booking already cancelled is ignored, and so is
+// a booking of the class made with a membership after the drop-in was cancelled. A class already started
+// keeps its bookings, like a booking cancelled by the user, so nobody is promoted into it.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: tx *sqlx.Tx - Transaction of the payment.
+// param: paymentId int - ID of the payment of the drop-in.
+// param: classId int - ID of the class.
+// param: now time.Time - Time of the release.
+//
+// @return error - Error if there is an issue releasing the seat.
+func ReleaseSeat(ctx context.Context, tx *sqlx.Tx, paymentId int, classId int, now time.Time) error {
+	// Lock the row for the specific class being released
+	var classStatus string
+	var classDate time.Time
+	var classDuration int
+	err := tx.QueryRowContext(ctx, lockClassToCancel, classId).Scan(&classStatus, &classDate, &classDuration)
+	if err != nil {
+		return err
+	}
+
+	if CheckNotStarted(classDate, classDuration, now) != nil {
+		return nil
+	}
+
+	result, err := tx.ExecContext(ctx, removeDropInBooking, paymentId)
+	if err != nil {
+		return err
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil || removed == 0 {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE classes SET num_registrations = num_registrations - 1 WHERE id = $1", classId)
+	if err != nil {
+		return err
+	}
+
+	_, err = waitlist.Promote(ctx, tx, classId)
+	return err
+}
+
+// lockClassToBook locks the row of a class and checks that the user can book it at the given time.
+//
+// The check of the caller runs on the locked row, so the class cannot be moved in between.
+//
+// @return time.Time - Start of the class.
+// @return int - Number of registrations of the class.
+// @return int - Capacity of the class.
+// @return error - Error if the user or the class does not exist, or the class cannot be booked.
+func lockClassToBook(ctx context.Context, tx *sqlx.Tx, userId int, classId int, now time.Time, check BookingCheck) (time.Time, int, int, error) {
+	// Lock the row for the specific class being booked
+	_, err := tx.ExecContext(ctx, "SELECT * FROM classes WHERE id = $1 FOR UPDATE", classId)
+	if err != nil {
+		return time.Time{}, 0, 0, err
+	}
+
+	var userIdValidation int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM users WHERE id = $1", userId).Scan(&userIdValidation)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, 0, 0, utils.E(http.StatusNotFound,
+				nil,
+				map[string]string{"message": "User Not Found"},
+				"The specified user does not exist.",
+				"Please provide a valid user ID.")
+		}
+
+		return time.Time{}, 0, 0, err
+	}
+
+	// Check class status, start and capacity
+	var numRegistrations, classCapacity, classDuration int
+	var classStatus, classType string
+	var classDate time.Time
+	err = tx.QueryRowContext(ctx, findClassToBook, classId).Scan(&numRegistrations, &classCapacity, &classStatus, &classType, &classDate, &classDuration)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, 0, 0, utils.E(http.StatusNotFound,
+				nil,
+				map[string]string{"message": "Class Not Found"},
+				"The specified class does not exist.",
+				"Please provide a valid class ID.")
+		}
+
+		return time.Time{}, 0, 0, err
+	}
+
+	if classStatus == api.ClassStatusCancelled {
+		return time.Time{}, 0, 0, utils.E(http.StatusUnprocessableEntity,
+			nil,
+			map[string]string{"message": "Class Cancelled"},
+			"The specified class is cancelled.",
+			"Please select a scheduled class.")
+	}
+
+	err = CheckNotStarted(classDate, classDuration, now)
+	if err != nil {
+		return time.Time{}, 0, 0, err
+	}
+
+	if check != nil {
+		err = check(api.Class{Type: classType, Date: classDate, Duration: classDuration, Capacity: classCapacity}, now)
+		if err != nil {
+			return time.Time{}, 0, 0, err
+		}
+	}
+
+	return classDate, numRegistrations, classCapacity, nil
+}
@@ -4,12 +4,79 @@ const (
 	AddBokking = `INSERT INTO bokking (user_id, class_id) 
 					VALUES($1, $2)`
 
-	GetUserBookings = `SELECT c.id, c.class_name, c.class_date, c.class_capacity, c.num_registrations, b.reserved_date
+	GetUserBookings = `SELECT c.id, c.class_name, c.class_date, c.class_capacity, c.num_registrations, b.reserved_date,
+							CASE WHEN b.booking_status = 'pending_payment' THEN 'pending_payment' ELSE 'booked' END AS status,
+							0 AS waitlist_position, b.attendance_status, b.checked_in_at
 						FROM classes c
 						INNER JOIN booking b ON c.id = b.class_id
-						WHERE b.user_id = $1;
+						WHERE b.user_id = $1
+						UNION ALL
+						SELECT c.id, c.class_name, c.class_date, c.class_capacity, c.num_registrations, w.join_date,
+							'waitlisted' AS status, w.waitlist_position, '' AS attendance_status, NULL AS checked_in_at
+						FROM classes c
+						INNER JOIN (
+							SELECT user_id, class_id, join_date,
+								ROW_NUMBER() OVER (PARTITION BY class_id ORDER BY id) AS waitlist_position
+							FROM waitlist
+						) w ON c.id = w.class_id
+						WHERE w.user_id = $1;
 						`
 
+	findClassToBook = `SELECT num_registrations, class_capacity, class_status, class_type, class_date, class_duration
+						FROM classes
+						WHERE id = $1`
+
+	lockClassToCancel = `SELECT class_status, class_date, class_duration
+							FROM classes
+							WHERE id = $1
+							FOR UPDATE`
+
+	addBooking = `INSERT INTO booking (user_id, class_id, reserved_date, membership_id)
+					VALUES ($1, $2, CURRENT_TIMESTAMP, $3)`
+
+	addPendingBooking = `INSERT INTO booking (user_id, class_id, reserved_date, booking_status)
+							VALUES ($1, $2, CURRENT_TIMESTAMP, 'pending_payment')`
+
+	countUserBookings = `SELECT COUNT(*)
+							FROM booking
+							WHERE user_id = $1 AND class_id = $2`
+
+	confirmPendingBooking = `UPDATE booking SET booking_status = 'confirmed'
+								WHERE payment_id = $1 AND booking_status = 'pending_payment'`
+
+	removeBooking = `DELETE FROM booking
+						WHERE user_id = $1 AND class_id = $2
+						RETURNING membership_id, booking_status, payment_id`
+
+	removeDropInBooking = `DELETE FROM booking
+							WHERE payment_id = $1`
+
+	// The ledger entry matches the failures recorded by the payments repository
+	failPendingDropInPayment = `WITH failed AS (
+									UPDATE payments SET payment_status = 'failed', last_update_date = CURRENT_TIMESTAMP
+									WHERE id = $1 AND payment_status = 'pending'
+									RETURNING id
+								)
+								INSERT INTO payment_ledger (payment_id, entry_type, amount_cents)
+								SELECT id, 'failure', 0 FROM failed`
+
+	lockBookingForCheckIn = `SELECT b.attendance_status, b.booking_status, c.class_date, c.class_status
+								FROM booking b
+								INNER JOIN classes c ON c.id = b.class_id
+								WHERE b.user_id = $1 AND b.class_id = $2
+								FOR UPDATE OF b`
+
+	checkInBooking = `UPDATE booking SET attendance_status = 'attended', checked_in_at = $3, checked_in_by = $4
+						WHERE user_id = $1 AND class_id = $2`
+
+	markNoShows = `UPDATE booking b SET attendance_status = 'no_show'
+					FROM classes c
+					WHERE c.id = b.class_id AND b.attendance_status = 'pending' AND b.booking_status = 'confirmed'
+						AND c.class_status = 'scheduled'
+						AND upper(class_time_range(c.class_date, c.class_duration)) <= $1`
+
+	recordLateCancellation = `INSERT INTO late_cancellations (user_id, class_id) VALUES ($1, $2)`
+
 	GetUsersOfBooking = `SELECT u.id, u.user_name
 						FROM users u
 						INNER JOIN booking b ON u.id = b.user_id
@@ -4,24 +4,56 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/database/memberships"
+	"github.com/Flgado/fitnessStudioApp/internal/database/waitlist"
 	"github.com/Flgado/fitnessStudioApp/utils"
 	"github.com/jmoiron/sqlx"
 )
 
 type WriteRepository interface {
-	Add(ctx context.Context, userId int, classId int) error
+	Add(ctx context.Context, userId int, classId int, now time.Time, check BookingCheck) (api.BookingResult, error)
+	Remove(ctx context.Context, userId int, classId int, late bool, now time.Time) error
+	CheckIn(ctx context.Context, userId int, classId int, checkedInBy int, at time.Time) error
+	MarkNoShows(ctx context.Context, now time.Time) (int64, error)
 }
 
+// CheckInWindow is how long before the start of a class its members can be checked in.
+const CheckInWindow = time.Hour
+
+// BookingCheck checks that a class can be booked at a given time, e.g. against the booking policy of the class.
+// The repository runs it on the locked class row.
+type BookingCheck func(class api.Class, now time.Time) error
+
 func NewWriteRepository(db *sqlx.DB) WriteRepository {
 	return &repository{db: db}
 }
 
-func (r *repository) Add(ctx context.Context, userId int, classId int) error {
+// Add books a seat of a class for a user, or adds the user to the class waitlist when the class is full.
+//
+// The class row is locked until the transaction ends, and the class must not have started at the
+// given time and must pass the check of the caller. The booking or waitlist entry consumes a credit
+// of a membership of the user valid for the class.
+// It returns a HTTP 404 error if the user or the class does not exist, a HTTP 402 error if the user
+// has no valid membership and a HTTP 422 error if the class is cancelled, in progress or finished
+// or the credits of the user are used up.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user.
+// param: classId int - ID of the class.
+// param: now time.Time - Time of the booking.
+// param: check BookingCheck - Additional check of the locked class, nil for none.
+//
+// @return api.BookingResult - Booking status, with the waitlist position when the user is waitlisted.
+// @return error - Error if the class cannot be booked.
+func (r *repository) Add(ctx context.Context, userId int, classId int, now time.Time, check BookingCheck) (_ api.BookingResult, err error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return err
+		return api.BookingResult{}, err
 	}
 	defer func() {
 		if err != nil {
@@ -31,60 +63,317 @@ func (r *repository) Add(ctx context.Context, userId int, classId int) error {
 		err = tx.Commit()
 	}()
 
-	// Lock the row for the specific class being booked
-	_, err = tx.ExecContext(ctx, "SELECT * FROM classes WHERE id = $1 FOR UPDATE", classId)
+	classDate, numRegistrations, classCapacity, err := lockClassToBook(ctx, tx, userId, classId, now, check)
+	if err != nil {
+		return api.BookingResult{}, err
+	}
+
+	// The seat or the waitlist entry holds one credit until it is released
+	membershipId, err := memberships.Consume(ctx, tx, userId, classDate)
+	if err != nil {
+		return api.BookingResult{}, err
+	}
+
+	if numRegistrations >= classCapacity {
+		// Class is full, queue the user until a seat is released
+		var position int
+		position, err = waitlist.Join(ctx, tx, userId, classId, membershipId)
+		if err != nil {
+			return api.BookingResult{}, err
+		}
+
+		return api.BookingResult{
+			ClassId:          classId,
+			UserId:           userId,
+			Status:           api.BookingStatusWaitlisted,
+			WaitlistPosition: position,
+		}, nil
+	}
+
+	// Increment num_registrations
+	_, err = tx.ExecContext(ctx, "UPDATE classes SET num_registrations = num_registrations + 1 WHERE id = $1", classId)
+	if err != nil {
+		return api.BookingResult{}, err
+	}
+
+	// Insert booking record
+	_, err = tx.ExecContext(ctx, addBooking, userId, classId, membershipId)
+	if err != nil {
+		return api.BookingResult{}, err
+	}
+
+	return api.BookingResult{
+		ClassId: classId,
+		UserId:  userId,
+		Status:  api.BookingStatusBooked,
+	}, nil
+}
+
+// Remove cancels the booking of a user, or removes the user from the class waitlist.
+//
+// The released seat goes to the first user in the waitlist and the credit held by the booking or
+// waitlist entry is refunded, and cancelling a drop-in waiting for its payment fails the payment.
+// A late cancellation of a booking is recorded in the same transaction, leaving a waitlist entry
+// is never late. The booking of a scheduled class can only be cancelled before
+// the class starts, once started it is attended or a no-show and keeps its credit.
+// It returns a HTTP 404 error if the class does not exist or the user has neither a booking nor a waitlist
+// entry for the class, and a HTTP 422 error if the booked class is in progress or finished.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user.
+// param: classId int - ID of the class.
+// param: late bool - True if the cancellation happens within the late cancellation window of the class.
+// param: now time.Time - Time of the cancellation.
+//
+// @return error - Error if the booking cannot be removed.
+func (r *repository) Remove(ctx context.Context, userId int, classId int, late bool, now time.Time) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
 
-	var userIdValidation int
-	err = tx.QueryRowContext(ctx, "SELECT id FROM users WHERE id = $1", userId).Scan(&userIdValidation)
+	// Lock the row for the specific class being released
+	var classStatus string
+	var classDate time.Time
+	var classDuration int
+	err = tx.QueryRowContext(ctx, lockClassToCancel, classId).Scan(&classStatus, &classDate, &classDuration)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return utils.E(http.StatusNotFound,
 				nil,
-				map[string]string{"message": "User Not Found"},
-				"The specified user does not exist.",
-				"Please provide a valid user ID.")
+				map[string]string{"message": "Class Not Found"},
+				"The specified class does not exist.",
+				"Please provide a valid class ID.")
+		}
+
+		return err
+	}
+
+	if classStatus == api.ClassStatusScheduled {
+		if started := CheckNotStarted(classDate, classDuration, now); started != nil {
+			// A waitlist entry never got a seat and can still be left
+			var booked int
+			err = tx.QueryRowContext(ctx, countUserBookings, userId, classId).Scan(&booked)
+			if err != nil {
+				return err
+			}
+
+			if booked > 0 {
+				return started
+			}
+		}
+	}
+
+	// Delete booking record
+	var membershipId, paymentId *int
+	var bookingStatus string
+	err = tx.QueryRowContext(ctx, removeBooking, userId, classId).Scan(&membershipId, &bookingStatus, &paymentId)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The user may still be waiting for a seat
+		var left bool
+		left, err = waitlist.Leave(ctx, tx, userId, classId)
+		if err != nil {
+			return err
+		}
+
+		if !left {
+			return utils.E(http.StatusNotFound,
+				nil,
+				map[string]string{"message": "Booking Not Found"},
+				"The specified user has no booking for this class.",
+				"Please provide a valid user ID and class ID.")
+		}
+
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Decrement num_registrations
+	_, err = tx.ExecContext(ctx, "UPDATE classes SET num_registrations = num_registrations - 1 WHERE id = $1", classId)
+	if err != nil {
+		return err
+	}
+
+	err = memberships.Refund(ctx, tx, membershipId)
+	if err != nil {
+		return err
+	}
+
+	// The payment of a drop-in still held fails, so it can no longer be captured
+	if bookingStatus == api.BookingStatusPendingPayment && paymentId != nil {
+		_, err = tx.ExecContext(ctx, failPendingDropInPayment, *paymentId)
+		if err != nil {
+			return err
+		}
+	}
+
+	if late {
+		_, err = tx.ExecContext(ctx, recordLateCancellation, userId, classId)
+		if err != nil {
+			return err
 		}
+	}
 
+	// Give the released seat to the first user in the waitlist
+	_, err = waitlist.Promote(ctx, tx, classId)
+	if err != nil {
 		return err
 	}
 
-	// Check class capacity
-	var numRegistrations, classCapacity int
-	err = tx.QueryRowContext(ctx, "SELECT num_registrations, class_capacity FROM classes WHERE id = $1", classId).Scan(&numRegistrations, &classCapacity)
+	return nil
+}
+
+// CheckNotStarted checks that a class can still be booked at a given time.
+//
+// It returns a HTTP 422 error if the class is in progress or already finished.
+//
+// param: classDate time.Time - Start of the class.
+// param: duration int - Length of the class in minutes.
+// param: now time.Time - Time of the booking.
+//
+// @return error - Error if the class has already started.
+func CheckNotStarted(classDate time.Time, duration int, now time.Time) error {
+	if now.Before(classDate) {
+		return nil
+	}
+
+	end := classDate.Add(time.Duration(duration) * time.Minute)
+	if now.Before(end) {
+		return utils.E(http.StatusUnprocessableEntity,
+			nil,
+			map[string]string{"message": "Class In Progress"},
+			fmt.Sprintf("The class started at %s.", classDate.Format(time.RFC3339)),
+			"Please select a class that has not started yet.")
+	}
+
+	return utils.E(http.StatusUnprocessableEntity,
+		nil,
+		map[string]string{"message": "Class Already Finished"},
+		fmt.Sprintf("The class finished at %s.", end.Format(time.RFC3339)),
+		"Please select a class that has not started yet.")
+}
+
+// CheckIn marks the booking of a user as attended.
+//
+// The check-in opens CheckInWindow before the start of the class. A booking marked as a
+// no-show can still be checked in, e.g. when the staff forgot to check the member in.
+// It returns a HTTP 404 error if the booking or the staff user does not exist, a HTTP 402 error if the
+// drop-in is not paid yet, a HTTP 409 error if the member is already checked in and a HTTP 422 error
+// if the class is cancelled or the check-in is not open.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the booked member.
+// param: classId int - ID of the class.
+// param: checkedInBy int - ID of the user checking the member in.
+// param: at time.Time - Check-in time.
+//
+// @return error - Error if the booking cannot be checked in.
+func (r *repository) CheckIn(ctx context.Context, userId int, classId int, checkedInBy int, at time.Time) (err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var attendance, bookingStatus, classStatus string
+	var classDate time.Time
+	err = tx.QueryRowContext(ctx, lockBookingForCheckIn, userId, classId).Scan(&attendance, &bookingStatus, &classDate, &classStatus)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return utils.E(http.StatusNotFound,
 				nil,
-				map[string]string{"message": "Class Not Found"},
-				"The specified class does not exist.",
-				"Please provide a valid class ID.")
+				map[string]string{"message": "Booking Not Found"},
+				"The specified user has no booking for this class.",
+				"Please provide a valid user ID and class ID.")
 		}
 
 		return err
 	}
 
-	if numRegistrations >= classCapacity {
+	if classStatus == api.ClassStatusCancelled {
 		return utils.E(http.StatusUnprocessableEntity,
 			nil,
-			map[string]string{"message": "Class Capacity Reached"},
-			"The class is already full and cannot accept any more registrations.",
-			"Please select another class or try again later.")
+			map[string]string{"message": "Class Cancelled"},
+			"The specified class is cancelled.",
+			"Please select a scheduled class.")
 	}
 
-	// Increment num_registrations
-	_, err = tx.ExecContext(ctx, "UPDATE classes SET num_registrations = num_registrations + 1 WHERE id = $1", classId)
+	if bookingStatus == api.BookingStatusPendingPayment {
+		return utils.E(http.StatusPaymentRequired,
+			nil,
+			map[string]string{"message": "Payment Pending"},
+			"The drop-in of the specified user is not paid yet.",
+			"Please complete the payment of the drop-in before the check-in.")
+	}
+
+	if attendance == api.AttendanceAttended {
+		return utils.E(http.StatusConflict,
+			nil,
+			map[string]string{"message": "Already Checked In"},
+			"The specified user is already checked in for this class.",
+			"Validate the class attendance.")
+	}
+
+	if at.Before(classDate.Add(-CheckInWindow)) {
+		return utils.E(http.StatusUnprocessableEntity,
+			nil,
+			map[string]string{"message": "Check-in Not Open"},
+			fmt.Sprintf("The check-in opens at %s.", classDate.Add(-CheckInWindow).Format(time.RFC3339)),
+			"Please check the member in closer to the start of the class.")
+	}
+
+	var staffId int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM users WHERE id = $1", checkedInBy).Scan(&staffId)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return utils.E(http.StatusNotFound,
+				nil,
+				map[string]string{"message": "User Not Found"},
+				"The user checking the member in does not exist.",
+				"Please provide a valid checked_in_by user ID.")
+		}
+
 		return err
 	}
 
-	// Insert booking record
-	_, err = tx.ExecContext(ctx, "INSERT INTO booking (user_id, class_id, reserved_date) VALUES ($1, $2, CURRENT_TIMESTAMP)", userId, classId)
+	_, err = tx.ExecContext(ctx, checkInBooking, userId, classId, at, checkedInBy)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// MarkNoShows marks the bookings without check-in of the classes already ended as no-shows.
+//
+// Cancelled classes and unpaid drop-ins are left untouched.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: now time.Time - Current time, classes ending at or before it are over.
+//
+// @return int64 - Number of bookings marked as no-shows.
+// @return error - Error if there is an issue updating the bookings.
+func (r *repository) MarkNoShows(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, markNoShows, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -1,13 +1,57 @@
 package classes
 
-import "time"
+import (
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+)
 
 type ClassRow struct {
 	Id               int       `db:"id"`
 	Name             string    `db:"class_name"`
+	Type             string    `db:"class_type"`
 	Date             time.Time `db:"class_date"`
 	Capacity         int       `db:"class_capacity"`
+	Duration         int       `db:"class_duration"`
+	RoomId           *int      `db:"room_id"`
+	InstructorId     *int      `db:"instructor_id"`
+	SeriesId         *int      `db:"series_id"`
+	Status           string    `db:"class_status"`
 	NumRegistrations int       `db:"num_registrations"`
 	CreateDate       time.Time `db:"create_date"`
 	LastUpdateDate   time.Time `db:"last_update_date"`
 }
+
+func toReadClass(cr ClassRow) api.ReadClass {
+	return api.ReadClass{
+		Id: cr.Id,
+		Class: api.Class{
+			Name:         cr.Name,
+			Type:         cr.Type,
+			Date:         cr.Date,
+			Duration:     cr.Duration,
+			Capacity:     cr.Capacity,
+			RoomId:       idOrZero(cr.RoomId),
+			InstructorId: idOrZero(cr.InstructorId),
+			SeriesId:     idOrZero(cr.SeriesId),
+		},
+		Status:           cr.Status,
+		NumRegistrations: cr.NumRegistrations,
+	}
+}
+
+// nullableId maps the zero id of the API models to a NULL column.
+func nullableId(id int) *int {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+// idOrZero maps a NULL column to the zero id of the API models.
+func idOrZero(id *int) int {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
@@ -2,6 +2,7 @@ package classes
 
 import (
 	"context"
+	"time"
 
 	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
 	"github.com/jmoiron/sqlx"
@@ -11,6 +12,9 @@ type ReadRepository interface {
 	List(ctx context.Context, filters api.ClasseFilters) ([]api.ReadClass, error)
 	GetById(ctx context.Context, classId int) (api.ReadClass, error)
 	GetClassReservations(ctx context.Context, classId int) (int, error)
+	ListSeries(ctx context.Context, seriesId int, from time.Time) ([]api.ReadClass, error)
+	ListMonthSchedule(ctx context.Context, filter api.ScheduleFilter) ([]api.Class, error)
+	IsTaughtBy(ctx context.Context, classId int, userId int) (bool, error)
 }
 
 type repository struct {
@@ -50,6 +54,18 @@ func (r *repository) List(ctx context.Context, filters api.ClasseFilters) ([]api
 		query += " AND class_date <= :end_date_le"
 		args["end_date_le"] = *filters.EndDateLe
 	}
+	if filters.RoomId != nil {
+		query += " AND room_id = :room_id"
+		args["room_id"] = *filters.RoomId
+	}
+	if filters.InstructorId != nil {
+		query += " AND instructor_id = :instructor_id"
+		args["instructor_id"] = *filters.InstructorId
+	}
+	if filters.Status != "" {
+		query += " AND class_status = :class_status"
+		args["class_status"] = filters.Status
+	}
 	if filters.CapacityGte != nil {
 		query += " AND class_capacity >= :capacity_gte"
 		args["capacity_gte"] = *filters.CapacityGte
@@ -82,16 +98,7 @@ func (r *repository) List(ctx context.Context, filters api.ClasseFilters) ([]api
 			return nil, err
 		}
 		// Convert ClassRow to ReadClass
-		readClass := api.ReadClass{
-			Id: classRow.Id,
-			Class: api.Class{
-				Name:     classRow.Name,
-				Date:     classRow.Date,
-				Capacity: classRow.Capacity,
-			},
-			NumRegistrations: classRow.NumRegistrations,
-		}
-		classes = append(classes, readClass)
+		classes = append(classes, toReadClass(classRow))
 	}
 
 	if err = rows.Err(); err != nil {
@@ -105,23 +112,86 @@ func (r *repository) GetById(ctx context.Context, classId int) (api.ReadClass, e
 	cr := ClassRow{}
 	row := r.db.QueryRowContext(ctx, findClassById, classId)
 
-	err := row.Scan(&cr.Id, &cr.Name, &cr.Date, &cr.Capacity, &cr.NumRegistrations, &cr.CreateDate, &cr.LastUpdateDate)
+	err := row.Scan(&cr.Id, &cr.Name, &cr.Type, &cr.Date, &cr.Capacity, &cr.Duration, &cr.RoomId, &cr.InstructorId, &cr.SeriesId, &cr.Status, &cr.NumRegistrations, &cr.CreateDate, &cr.LastUpdateDate)
 
 	if err != nil {
 		return api.ReadClass{}, err
 	}
 
-	readClass := api.ReadClass{
-		Id: cr.Id,
-		Class: api.Class{
-			Name:     cr.Name,
-			Date:     cr.Date,
-			Capacity: cr.Capacity,
-		},
-		NumRegistrations: cr.NumRegistrations,
+	return toReadClass(cr), nil
+}
+
+// ListSeries retrieves the scheduled classes of a series starting at or after a date.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: seriesId int - ID of the series.
+// param: from time.Time - Date of the first class to retrieve.
+//
+// @return []api.ReadClass - Classes of the series ordered by date.
+// @return error - Error if there is an issue retrieving the classes from the database.
+func (r *repository) ListSeries(ctx context.Context, seriesId int, from time.Time) ([]api.ReadClass, error) {
+	rows, err := r.db.QueryxContext(ctx, findSeriesClassesFrom, seriesId, from)
+	if err != nil {
+		return nil, err
 	}
 
-	return readClass, nil
+	defer rows.Close()
+
+	classes := []api.ReadClass{}
+
+	for rows.Next() {
+		var classRow ClassRow
+		if err = rows.StructScan(&classRow); err != nil {
+			return nil, err
+		}
+		classes = append(classes, toReadClass(classRow))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return classes, nil
+}
+
+// ListMonthSchedule retrieves the scheduled classes taking the time slots of a room or an instructor in a month.
+//
+// Classes without room are selected with RoomId 0. Cancelled classes are not returned.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: filter api.ScheduleFilter - Room or instructor, year and month of the classes.
+//
+// @return []api.Class - Classes starting in the month.
+// @return error - Error if there is an issue retrieving the classes from the database.
+func (r *repository) ListMonthSchedule(ctx context.Context, filter api.ScheduleFilter) ([]api.Class, error) {
+	query, id := findRoomMonthSchedule, filter.RoomId
+	if filter.InstructorId != 0 {
+		query, id = findInstructorMonthSchedule, filter.InstructorId
+	}
+
+	start := time.Date(filter.Year, filter.Month, 1, 0, 0, 0, 0, time.UTC)
+	rows, err := r.db.QueryxContext(ctx, query, id, start, start.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	classes := []api.Class{}
+
+	for rows.Next() {
+		var classRow ClassRow
+		if err = rows.StructScan(&classRow); err != nil {
+			return nil, err
+		}
+		classes = append(classes, toReadClass(classRow).Class)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return classes, nil
 }
 
 func (r *repository) GetClassReservations(ctx context.Context, classId int) (int, error) {
@@ -137,3 +207,22 @@ func (r *repository) GetClassReservations(ctx context.Context, classId int) (int
 
 	return c, nil
 }
+
+// IsTaughtBy reports whether a class is taught by the instructor signing in as the user.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: classId int - ID of the class.
+// param: userId int - ID of the user of the instructor.
+//
+// @return bool - True if the instructor of the class is linked to the user.
+// @return error - Error if there is an issue querying the database.
+func (r *repository) IsTaughtBy(ctx context.Context, classId int, userId int) (bool, error) {
+	var taught bool
+
+	err := r.db.GetContext(ctx, &taught, classTaughtByUser, classId, userId)
+	if err != nil {
+		return false, err
+	}
+
+	return taught, nil
+}
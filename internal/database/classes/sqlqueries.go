@@ -1,15 +1,68 @@
 package classes
 
 const (
-	findClassById = `SELECT *
+	findClassById = `SELECT id, class_name, class_type, class_date, class_capacity, class_duration, room_id, instructor_id, series_id, class_status, num_registrations, create_date, last_update_date
 						From classes
 						Where id = $1`
 
 	classReservationsById = `SELECT num_registrations
 								From classes
 								Where id = $1`
-	AddClassRow = `INSERT INTO classes (class_name, class_date, class_capacity, num_registrations) 
-					VALUES(:class_name, :class_date, :class_capacity, :num_registrations)`
+	AddClassRow = `INSERT INTO classes (class_name, class_type, class_date, class_capacity, class_duration, room_id, instructor_id, series_id, num_registrations) 
+					VALUES(:class_name, :class_type, :class_date, :class_capacity, :class_duration, :room_id, :instructor_id, :series_id, :num_registrations)`
+
+	savepointAddClass        = `SAVEPOINT add_class`
+	rollbackToAddClass       = `ROLLBACK TO SAVEPOINT add_class`
+	releaseSavepointAddClass = `RELEASE SAVEPOINT add_class`
+
+	AddSeriesRow = `INSERT INTO class_series (recurrence_rule) VALUES($1) RETURNING id`
+
+	deleteSeriesRow = `DELETE FROM class_series WHERE id = $1`
+
+	findSeriesClassesFrom = `SELECT * FROM classes
+								WHERE series_id = $1 AND class_date >= $2 AND class_status = 'scheduled'
+								ORDER BY class_date`
+
+	findRoomMonthSchedule = `SELECT * FROM classes
+								WHERE COALESCE(room_id, 0) = $1 AND class_date >= $2 AND class_date < $3 AND class_status = 'scheduled'`
+
+	findInstructorMonthSchedule = `SELECT * FROM classes
+									WHERE instructor_id = $1 AND class_date >= $2 AND class_date < $3 AND class_status = 'scheduled'`
+
+	lockClasses = `SELECT id FROM classes WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+
+	cancelClasses = `UPDATE classes SET class_status = 'cancelled', num_registrations = 0
+						WHERE id = ANY($1) AND class_status = 'scheduled'`
+
+	notifyBookedMembers = `INSERT INTO notification_outbox (user_id, class_id, event_type)
+							SELECT user_id, class_id, $2 FROM booking WHERE class_id = ANY($1)`
+
+	// The ledger entries match the ones recorded by the payments repository
+	failPendingDropInPayments = `WITH failed AS (
+									UPDATE payments SET payment_status = 'failed', last_update_date = CURRENT_TIMESTAMP
+									WHERE class_id = ANY($1) AND purpose = 'drop_in' AND payment_status = 'pending'
+									RETURNING id
+								)
+								INSERT INTO payment_ledger (payment_id, entry_type, amount_cents)
+								SELECT id, 'failure', 0 FROM failed`
+
+	// Only the drop-ins still booked are refunded, a cancelled booking no longer holds its payment
+	refundCapturedDropInPayments = `WITH refunded AS (
+										UPDATE payments p SET payment_status = 'refunded', last_update_date = CURRENT_TIMESTAMP
+										WHERE p.class_id = ANY($1) AND p.purpose = 'drop_in' AND p.payment_status = 'captured'
+											AND EXISTS (SELECT 1 FROM booking b WHERE b.payment_id = p.id)
+										RETURNING p.id, p.amount_cents
+									)
+									INSERT INTO payment_ledger (payment_id, entry_type, amount_cents)
+									SELECT id, 'refund', -amount_cents FROM refunded`
+
+	releaseBookings = `DELETE FROM booking WHERE class_id = ANY($1)`
+
+	releaseWaitlist = `DELETE FROM waitlist WHERE class_id = ANY($1)`
 
 	UpdateClass = `UPDATE classes SET`
+
+	classTaughtByUser = `SELECT EXISTS (SELECT 1 FROM classes c
+							JOIN instructors i ON i.id = c.instructor_id
+							WHERE c.id = $1 AND i.user_id = $2)`
 )
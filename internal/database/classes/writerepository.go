@@ -2,59 +2,112 @@ package classes
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/database/memberships"
+	"github.com/Flgado/fitnessStudioApp/internal/database/waitlist"
 	"github.com/Flgado/fitnessStudioApp/utils"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// exclusionViolation is the SQLSTATE of a row violating an exclusion constraint.
+const exclusionViolation = "23P01"
+
 type WriteRepository interface {
-	Add(ctx context.Context, user []api.Class) error
+	Add(ctx context.Context, rrule string, classes []api.Class) ([]api.Class, error)
 	Update(ctx context.Context, classId int, classUpdate api.UpdateClass) (int64, error)
+	UpdateMany(ctx context.Context, updates []api.OccurrenceUpdate) (int64, error)
+	Cancel(ctx context.Context, classIds []int) (int64, error)
 }
 
 func NewWriteRepository(db *sqlx.DB) WriteRepository {
 	return &repository{db: db}
 }
 
-// Add inserts classes into the repository.
+// Add inserts the classes of a scheduler request into the repository, grouped in a new series.
 //
-// This method takes a context.Context object for managing the lifecycle of the request
-// and a slice of api.Class structs representing the classes to be inserted.
-// It converts each api.Class to a ClassRow struct and executes a bulk insert operation
-// into the database using the NamedExecContext method.
-// It returns nil if the classes are successfully inserted, otherwise, it returns an error.
+// This method takes a context.Context object for managing the lifecycle of the request,
+// the recurrence rule of the request and a slice of api.Class structs representing the classes to be inserted.
+// The series and its classes are inserted in a single transaction. The database rejects a class overlapping
+// another scheduled class of the same room or instructor, even one created by another process,
+// so each class is inserted under a savepoint and the rejected ones are skipped.
+// When every class is rejected the series is not kept.
+// It returns the rejected classes, otherwise, it returns an error.
 //
 // param: ctx context.Context - Context object for managing the request lifecycle.
+// param: rrule string - Recurrence rule the classes were created from, empty for daily classes.
 // param: classes []api.Class - Slice of api.Class structs representing the classes to be inserted.
 //
+// @return []api.Class - Classes not inserted because their time range is already taken.
 // @return error - Error if there is an issue inserting the classes into the database.
-func (r *repository) Add(ctx context.Context, classes []api.Class) error {
-	// Prepare slice to hold ClassRow values
-	classRows := make([]ClassRow, len(classes))
-
-	// Convert each api.Class to ClassRow
-	for i, class := range classes {
-		classRows[i] = ClassRow{
-			Name:     class.Name,
-			Date:     class.Date,
-			Capacity: class.Capacity,
+func (r *repository) Add(ctx context.Context, rrule string, classes []api.Class) (_ []api.Class, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
 		}
+		err = tx.Commit()
+	}()
+
+	var seriesId int
+	if err = tx.QueryRowContext(ctx, AddSeriesRow, rrule).Scan(&seriesId); err != nil {
+		return nil, internalError(err)
 	}
 
-	// Execute bulk insert
-	_, err := r.db.NamedExecContext(ctx, AddClassRow, classRows)
-	if err != nil {
-		return utils.E(http.StatusInternalServerError,
-			err,
-			map[string]string{"message": "Internal Server Error"},
-			"Something went wrong",
-			"Please contact support team")
+	var rejected []api.Class
+	for _, class := range classes {
+		class.SeriesId = seriesId
+		classRow := ClassRow{
+			Name:         class.Name,
+			Type:         class.Type,
+			Date:         class.Date,
+			Duration:     class.Duration,
+			Capacity:     class.Capacity,
+			RoomId:       nullableId(class.RoomId),
+			InstructorId: nullableId(class.InstructorId),
+			SeriesId:     nullableId(class.SeriesId),
+		}
+
+		if _, err = tx.ExecContext(ctx, savepointAddClass); err != nil {
+			return nil, internalError(err)
+		}
+
+		_, err = tx.NamedExecContext(ctx, AddClassRow, classRow)
+		if isScheduleConflict(err) {
+			// Undo only this class, the transaction stays usable
+			if _, err = tx.ExecContext(ctx, rollbackToAddClass); err != nil {
+				return nil, internalError(err)
+			}
+			rejected = append(rejected, class)
+			continue
+		}
+		if err != nil {
+			return nil, internalError(err)
+		}
+
+		if _, err = tx.ExecContext(ctx, releaseSavepointAddClass); err != nil {
+			return nil, internalError(err)
+		}
+	}
+
+	if len(rejected) == len(classes) {
+		// A series without classes is not kept
+		if _, err = tx.ExecContext(ctx, deleteSeriesRow, seriesId); err != nil {
+			return nil, internalError(err)
+		}
+		return classes, nil
 	}
 
-	return nil
+	return rejected, nil
 }
 
 // Update modifies an existing class in the repository.
@@ -64,6 +117,7 @@ func (r *repository) Add(ctx context.Context, classes []api.Class) error {
 // containing the fields to be modified.
 // It fetches the existing class data from the database, constructs a SQL UPDATE query based
 // on the provided update fields, and executes it using the NamedExecContext method.
+// When the capacity grows, waitlisted users are promoted into the new seats in the same transaction.
 // It returns the number of rows affected if the update is successful, otherwise, it returns an error.
 //
 // param: ctx context.Context - Context object for managing the request lifecycle.
@@ -72,7 +126,80 @@ func (r *repository) Add(ctx context.Context, classes []api.Class) error {
 //
 // @return int64 - Number of rows affected by the update operation.
 // @return error - Error if there is an issue updating the class in the database.
-func (r *repository) Update(ctx context.Context, classId int, classUpdate api.UpdateClass) (int64, error) {
+func (r *repository) Update(ctx context.Context, classId int, classUpdate api.UpdateClass) (_ int64, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var rows int64
+	rows, err = updateClass(ctx, tx, classId, classUpdate)
+	if err != nil {
+		return 0, err
+	}
+
+	return rows, nil
+}
+
+// UpdateMany modifies several classes, usually the occurrences of a series, in a single transaction.
+//
+// Every class goes through the same checks as Update, so if any of them cannot be updated
+// none of them is.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: updates []api.OccurrenceUpdate - Update of each class.
+//
+// @return int64 - Number of rows affected by the update operation.
+// @return error - Error if there is an issue updating any of the classes.
+func (r *repository) UpdateMany(ctx context.Context, updates []api.OccurrenceUpdate) (_ int64, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var total int64
+	for _, update := range updates {
+		var rows int64
+		rows, err = updateClass(ctx, tx, update.ClassId, update.Update)
+		if err != nil {
+			return 0, err
+		}
+		total += rows
+	}
+
+	return total, nil
+}
+
+// Cancel marks classes as cancelled and releases their bookings and waitlist entries.
+//
+// The classes stay in the repository with the cancelled status. Every booked member
+// gets a class cancelled notification in the outbox, the credits held by the bookings
+// and waitlist entries are refunded, the pending drop-in payments fail and the captured
+// ones of the booked drop-ins are recorded as refunded, in the same transaction.
+// Classes already cancelled are left untouched.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: classIds []int - IDs of the classes to cancel.
+//
+// @return int64 - Number of classes cancelled.
+// @return error - Error if there is an issue cancelling the classes.
+func (r *repository) Cancel(ctx context.Context, classIds []int) (_ int64, err error) {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return 0, err
@@ -86,8 +213,70 @@ func (r *repository) Update(ctx context.Context, classId int, classUpdate api.Up
 		err = tx.Commit()
 	}()
 
+	ids := pq.Array(classIds)
+
+	// Lock the classes so no booking is made while they are cancelled
+	_, err = tx.ExecContext(ctx, lockClasses, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, cancelClasses, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	// Record the notification of every booked member before the bookings are released
+	_, err = tx.ExecContext(ctx, notifyBookedMembers, ids, api.NotificationClassCancelled)
+	if err != nil {
+		return 0, err
+	}
+
+	err = memberships.RefundClasses(ctx, tx, classIds)
+	if err != nil {
+		return 0, err
+	}
+
+	// The drop-ins waiting for their payment can no longer be captured, the paid ones are refunded
+	_, err = tx.ExecContext(ctx, failPendingDropInPayments, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, refundCapturedDropInPayments, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, releaseBookings, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, releaseWaitlist, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// updateClass modifies an existing class inside the transaction of the caller.
+//
+// The class row is locked until the transaction ends. The capacity cannot be lowered below
+// the number of registrations, and a capacity increase promotes waitlisted users.
+// A new time range overlapping another class of the room or instructor returns a HTTP 404 error.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: tx *sqlx.Tx - Transaction of the caller.
+// param: classId int - ID of the class to be updated.
+// param: classUpdate api.UpdateClass - Struct containing the fields to be modified.
+//
+// @return int64 - Number of rows affected by the update operation.
+// @return error - Error if there is an issue updating the class in the database.
+func updateClass(ctx context.Context, tx *sqlx.Tx, classId int, classUpdate api.UpdateClass) (int64, error) {
 	// Lock the row for the specific class being updated
-	_, err = tx.ExecContext(ctx, "SELECT * FROM classes WHERE id = $1 FOR UPDATE", classId)
+	_, err := tx.ExecContext(ctx, "SELECT * FROM classes WHERE id = $1 FOR UPDATE", classId)
 	if err != nil {
 		return 0, err
 	}
@@ -115,6 +304,18 @@ func (r *repository) Update(ctx context.Context, classId int, classUpdate api.Up
 		updateFields = append(updateFields, "class_date=:class_date")
 		args["class_date"] = *classUpdate.Date
 	}
+	if classUpdate.Duration != nil {
+		updateFields = append(updateFields, "class_duration=:class_duration")
+		args["class_duration"] = *classUpdate.Duration
+	}
+	if classUpdate.RoomId != nil {
+		updateFields = append(updateFields, "room_id=:room_id")
+		args["room_id"] = *classUpdate.RoomId
+	}
+	if classUpdate.InstructorId != nil {
+		updateFields = append(updateFields, "instructor_id=:instructor_id")
+		args["instructor_id"] = nullableId(*classUpdate.InstructorId)
+	}
 
 	if classUpdate.Capacity != nil {
 		// not possible to update
@@ -140,10 +341,44 @@ func (r *repository) Update(ctx context.Context, classId int, classUpdate api.Up
 
 	// Execute the update query
 	result, err := tx.NamedExecContext(ctx, query, args)
+	if isScheduleConflict(err) {
+		return 0, dateReservedError()
+	}
 	if err != nil {
 		return 0, err
 	}
 
+	if classUpdate.Capacity != nil {
+		// A capacity increase opens seats for the waitlisted users
+		_, err = waitlist.Promote(ctx, tx, classId)
+		if err != nil {
+			return 0, err
+		}
+	}
+
 	// Return the number of rows affected
 	return result.RowsAffected()
 }
+
+// isScheduleConflict reports whether err is the violation of one of the exclusion
+// constraints preventing overlapping classes in a room or for an instructor.
+func isScheduleConflict(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == exclusionViolation
+}
+
+func dateReservedError() error {
+	return utils.E(http.StatusNotFound,
+		nil,
+		map[string]string{"message": "Date already reserved"},
+		"The selected date is already reserved.",
+		"Please choose a different date or class.")
+}
+
+func internalError(err error) error {
+	return utils.E(http.StatusInternalServerError,
+		err,
+		map[string]string{"message": "Internal Server Error"},
+		"Something went wrong",
+		"Please contact support team")
+}
@@ -36,11 +36,12 @@ func NewDBFactory(c config.Config) DBFactory {
 }
 
 func (df *dBFactoryImpl) GetDbContext() (*sqlx.DB, error) {
-	dataSourceName := fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=disable password=%s",
+	dataSourceName := fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=%s password=%s",
 		df.config.Postgres.PostgresqlHost,
 		df.config.Postgres.PostgresqlPort,
 		df.config.Postgres.PostgresqlUser,
 		df.config.Postgres.PostgresqlDbname,
+		df.config.Postgres.PostgresqlSSLMode,
 		df.config.Postgres.PostgresqlPassword,
 	)
 
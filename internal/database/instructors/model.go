@@ -0,0 +1,17 @@
+package instructors
+
+import (
+	"database/sql"
+	"time"
+)
+
+type InstructorRow struct {
+	Id             int           `db:"id"`
+	UserId         sql.NullInt64 `db:"user_id"`
+	Name           string        `db:"instructor_name"`
+	Email          string        `db:"email"`
+	Phone          string        `db:"phone"`
+	Bio            string        `db:"bio"`
+	CreateDate     time.Time     `db:"create_date"`
+	LastUpdateDate time.Time     `db:"last_update_date"`
+}
@@ -0,0 +1,85 @@
+package instructors
+
+import (
+	"context"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type ReadRepository interface {
+	List(ctx context.Context) ([]api.Instructor, error)
+	GetById(ctx context.Context, instructorId int) (api.Instructor, error)
+}
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewReadRepository(db *sqlx.DB) ReadRepository {
+	return &repository{db: db}
+}
+
+// List retrieves all the instructors of the studio.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+//
+// @return []api.Instructor - Slice of Instructor structs representing the instructors.
+// @return error - Error if there is an issue retrieving the instructors from the database.
+func (r *repository) List(ctx context.Context) ([]api.Instructor, error) {
+	rows, err := r.db.QueryxContext(ctx, findInstructors)
+	if err != nil {
+		return nil, errors.Wrap(err, "instructorsRepo.List.QueryxContext")
+	}
+
+	defer rows.Close()
+
+	instructors := []api.Instructor{}
+
+	for rows.Next() {
+		var instructor InstructorRow
+		if err = rows.StructScan(&instructor); err != nil {
+			return nil, errors.Wrap(err, "instructorsRepo.List.StructScan")
+		}
+
+		instructors = append(instructors, toInstructor(instructor))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "instructorsRepo.List.rows.Err")
+	}
+
+	return instructors, nil
+}
+
+// GetById retrieves an instructor by its unique identifier.
+//
+// It returns sql.ErrNoRows if the instructor does not exist.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: instructorId int - ID of the instructor to retrieve.
+//
+// @return api.Instructor - Instructor struct representing the instructor.
+// @return error - Error if there is an issue retrieving the instructor.
+func (r *repository) GetById(ctx context.Context, instructorId int) (api.Instructor, error) {
+	instructor := InstructorRow{}
+
+	err := r.db.GetContext(ctx, &instructor, findInstructorById, instructorId)
+	if err != nil {
+		return api.Instructor{}, err
+	}
+
+	return toInstructor(instructor), nil
+}
+
+func toInstructor(row InstructorRow) api.Instructor {
+	return api.Instructor{
+		Id:     row.Id,
+		UserId: int(row.UserId.Int64),
+		Name:   row.Name,
+		Email:  row.Email,
+		Phone:  row.Phone,
+		Bio:    row.Bio,
+	}
+}
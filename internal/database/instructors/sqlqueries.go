@@ -0,0 +1,24 @@
+package instructors
+
+const (
+	findInstructors = `SELECT id, user_id, instructor_name, email, phone, bio, create_date, last_update_date
+						FROM instructors
+						ORDER BY id`
+
+	findInstructorById = `SELECT id, user_id, instructor_name, email, phone, bio, create_date, last_update_date
+							FROM instructors
+							WHERE id = $1`
+
+	findInstructorByEmail = `SELECT id
+								FROM instructors
+								WHERE email = $1`
+
+	findInstructorByUserId = `SELECT id
+								FROM instructors
+								WHERE user_id = $1`
+
+	userExists = `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`
+
+	AddInstructorRow = `INSERT INTO instructors (user_id, instructor_name, email, phone, bio)
+							VALUES(:user_id, :instructor_name, :email, :phone, :bio)`
+)
@@ -0,0 +1,190 @@
+package instructors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+type WriteRepository interface {
+	Add(ctx context.Context, instructor api.CreateInstructor) error
+	Update(ctx context.Context, instructor api.PatchInstructor) (int64, error)
+}
+
+func NewWriteRepository(db *sqlx.DB) WriteRepository {
+	return &repository{db: db}
+}
+
+// Add inserts a new instructor into the repository.
+//
+// Instructor emails are unique, so it returns a HTTP 409 error if another instructor uses the same email
+// or is linked to the same user. It returns a HTTP 404 error if the user does not exist.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: instructor api.CreateInstructor - Struct containing the instructor to be inserted.
+//
+// @return error - Error if there is an issue inserting the instructor into the database.
+func (r *repository) Add(ctx context.Context, instructor api.CreateInstructor) error {
+	if instructor.Email != "" {
+		var existingId int
+		err := r.db.QueryRowContext(ctx, findInstructorByEmail, instructor.Email).Scan(&existingId)
+		if err == nil {
+			return emailConflictError(instructor.Email)
+		}
+
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+
+	if instructor.UserId != 0 {
+		if err := r.checkUserLink(ctx, 0, instructor.UserId); err != nil {
+			return err
+		}
+	}
+
+	ir := InstructorRow{
+		UserId: sql.NullInt64{Int64: int64(instructor.UserId), Valid: instructor.UserId != 0},
+		Name:   instructor.Name,
+		Email:  instructor.Email,
+		Phone:  instructor.Phone,
+		Bio:    instructor.Bio,
+	}
+
+	_, err := r.db.NamedExecContext(ctx, AddInstructorRow, ir)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Update modifies an existing instructor in the repository.
+//
+// It returns sql.ErrNoRows if the instructor does not exist, a HTTP 409 error if the new email
+// or user is used by another instructor, and a HTTP 404 error if the user does not exist.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: instructor api.PatchInstructor - Struct containing the fields to be modified.
+//
+// @return int64 - Number of rows affected by the update operation.
+// @return error - Error if there is an issue updating the instructor in the database.
+func (r *repository) Update(ctx context.Context, instructor api.PatchInstructor) (int64, error) {
+	existing := InstructorRow{}
+	err := r.db.GetContext(ctx, &existing, findInstructorById, instructor.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	query := "UPDATE instructors SET "
+	args := map[string]interface{}{
+		"id": instructor.Id,
+	}
+	var updateFields []string
+
+	if instructor.Name != nil {
+		updateFields = append(updateFields, "instructor_name=:instructor_name")
+		args["instructor_name"] = *instructor.Name
+	}
+
+	if instructor.Email != nil {
+		if *instructor.Email != "" {
+			var existingId int
+			err = r.db.QueryRowContext(ctx, findInstructorByEmail, *instructor.Email).Scan(&existingId)
+			if err == nil && existingId != instructor.Id {
+				return 0, emailConflictError(*instructor.Email)
+			}
+
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return 0, err
+			}
+		}
+
+		updateFields = append(updateFields, "email=:email")
+		args["email"] = *instructor.Email
+	}
+
+	if instructor.UserId != nil {
+		if *instructor.UserId != 0 {
+			if err = r.checkUserLink(ctx, instructor.Id, *instructor.UserId); err != nil {
+				return 0, err
+			}
+		}
+
+		updateFields = append(updateFields, "user_id=:user_id")
+		args["user_id"] = sql.NullInt64{Int64: int64(*instructor.UserId), Valid: *instructor.UserId != 0}
+	}
+
+	if instructor.Phone != nil {
+		updateFields = append(updateFields, "phone=:phone")
+		args["phone"] = *instructor.Phone
+	}
+
+	if instructor.Bio != nil {
+		updateFields = append(updateFields, "bio=:bio")
+		args["bio"] = *instructor.Bio
+	}
+
+	// Check if any fields are to be updated
+	if len(updateFields) == 0 {
+		return 0, nil // No fields to update
+	}
+
+	query += strings.Join(updateFields, ", ")
+	query += " WHERE id=:id"
+
+	result, err := r.db.NamedExecContext(ctx, query, args)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// checkUserLink checks that a user exists and is not the account of another instructor than instructorId.
+func (r *repository) checkUserLink(ctx context.Context, instructorId int, userId int) error {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, userExists, userId)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return utils.E(http.StatusNotFound,
+			nil,
+			map[string]string{"message": "User Not Found"},
+			fmt.Sprintf("User %d does not exist", userId),
+			"Please provide a valid user ID.")
+	}
+
+	var existingId int
+	err = r.db.QueryRowContext(ctx, findInstructorByUserId, userId).Scan(&existingId)
+	if err == nil && existingId != instructorId {
+		return utils.E(http.StatusConflict,
+			nil,
+			map[string]string{"message": "Conflict Status"},
+			fmt.Sprintf("User %d is already the account of instructor %d", userId, existingId),
+			"Please unlink the other instructor first.")
+	}
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return nil
+}
+
+func emailConflictError(email string) error {
+	return utils.E(http.StatusConflict,
+		nil,
+		map[string]string{"message": "Conflict Status"},
+		fmt.Sprintf("Instructor with email %s already exists", email),
+		"Please use a different email.")
+}
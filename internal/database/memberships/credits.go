@@ -0,0 +1,109 @@
+package memberships
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// The credits are always changed together with the booking and waitlist tables, so
+// these helpers run inside the caller's transaction. Each booking and waitlist entry
+// keeps the membership holding its credit, so the credit goes back to that membership.
+
+// Consume takes one credit of a membership of the user valid at the start of the class.
+//
+// Unlimited memberships are used first and take no credit, then the credit pack expiring first.
+// The membership row is locked until the transaction ends. It returns a HTTP 402 error if the
+// user has no membership valid for the class, or a HTTP 422 error if the valid credit packs are used up.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: tx *sqlx.Tx - Transaction of the booking.
+// param: userId int - ID of the user booking the class.
+// param: classDate time.Time - Start of the class.
+//
+// @return int - ID of the membership holding the credit.
+// @return error - Error if the user has no usable membership.
+func Consume(ctx context.Context, tx *sqlx.Tx, userId int, classDate time.Time) (int, error) {
+	var membershipId int
+	var remainingCredits *int
+	err := tx.QueryRowContext(ctx, lockUsableMembership, userId, classDate).Scan(&membershipId, &remainingCredits)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, noUsableMembershipError(ctx, tx, userId, classDate)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if remainingCredits == nil {
+		// Unlimited membership
+		return membershipId, nil
+	}
+
+	_, err = tx.ExecContext(ctx, consumeCredit, membershipId)
+	if err != nil {
+		return 0, err
+	}
+
+	return membershipId, nil
+}
+
+// Refund gives back the credit held by a booking or waitlist entry.
+//
+// Unlimited memberships, and the entries made before memberships existed (nil membership), are left untouched.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: tx *sqlx.Tx - Transaction releasing the booking or waitlist entry.
+// param: membershipId *int - Membership holding the credit.
+//
+// @return error - Error if there is an issue refunding the credit.
+func Refund(ctx context.Context, tx *sqlx.Tx, membershipId *int) error {
+	if membershipId == nil {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, refundCredit, *membershipId)
+	return err
+}
+
+// RefundClasses gives back the credits held by all the bookings and waitlist entries of classes.
+//
+// It must run before the bookings and waitlist entries are deleted.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: tx *sqlx.Tx - Transaction releasing the classes.
+// param: classIds []int - IDs of the released classes.
+//
+// @return error - Error if there is an issue refunding the credits.
+func RefundClasses(ctx context.Context, tx *sqlx.Tx, classIds []int) error {
+	_, err := tx.ExecContext(ctx, refundClassesCredits, pq.Array(classIds))
+	return err
+}
+
+func noUsableMembershipError(ctx context.Context, tx *sqlx.Tx, userId int, classDate time.Time) error {
+	var valid int
+	err := tx.QueryRowContext(ctx, countValidMemberships, userId, classDate).Scan(&valid)
+	if err != nil {
+		return err
+	}
+
+	if valid > 0 {
+		return utils.E(http.StatusUnprocessableEntity,
+			nil,
+			map[string]string{"message": "No Credits Left"},
+			"All the credits of the memberships valid for this class are used.",
+			"Please buy a new class pack or an unlimited membership.")
+	}
+
+	return utils.E(http.StatusPaymentRequired,
+		nil,
+		map[string]string{"message": "No Valid Membership"},
+		fmt.Sprintf("The user has no membership valid on %s.", classDate.Format("2006-01-02")),
+		"Please buy a membership or a class pack covering the class date.")
+}
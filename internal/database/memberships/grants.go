@@ -0,0 +1,83 @@
+package memberships
+
+import (
+	"context"
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// Memberships are also granted and ended by the payments of the plans, so these helpers
+// take either the database or the transaction of the payment.
+
+// PlanById retrieves a membership plan by its unique identifier.
+//
+// It returns sql.ErrNoRows if the plan does not exist.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: q sqlx.QueryerContext - Database or transaction to read from.
+// param: planId int - ID of the plan to retrieve.
+//
+// @return api.Plan - Plan struct representing the plan.
+// @return error - Error if there is an issue retrieving the plan.
+func PlanById(ctx context.Context, q sqlx.QueryerContext, planId int) (api.Plan, error) {
+	plan := PlanRow{}
+
+	err := sqlx.GetContext(ctx, q, &plan, findPlanById, planId)
+	if err != nil {
+		return api.Plan{}, err
+	}
+
+	return toPlan(plan), nil
+}
+
+// Grant inserts a membership of a plan for a user.
+//
+// The membership is valid for the validity days of the plan starting at validFrom,
+// and a credit pack starts with all the credits of the plan. The user must exist.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: q sqlx.QueryerContext - Database or transaction to write to.
+// param: userId int - ID of the user.
+// param: plan api.Plan - Plan of the membership.
+// param: validFrom time.Time - Start of the validity period.
+//
+// @return api.Membership - The new membership.
+// @return error - Error if there is an issue inserting the membership into the database.
+func Grant(ctx context.Context, q sqlx.QueryerContext, userId int, plan api.Plan, validFrom time.Time) (api.Membership, error) {
+	membership := api.Membership{
+		UserId:     userId,
+		PlanId:     plan.Id,
+		PlanName:   plan.Name,
+		Type:       plan.Type,
+		ValidFrom:  validFrom,
+		ValidUntil: validFrom.AddDate(0, 0, plan.ValidityDays),
+	}
+	if plan.Type == api.PlanTypeCredits {
+		credits := plan.Credits
+		membership.RemainingCredits = &credits
+	}
+
+	err := q.QueryRowxContext(ctx, addMembership, userId, plan.Id, membership.ValidFrom, membership.ValidUntil, membership.RemainingCredits).Scan(&membership.Id)
+	if err != nil {
+		return api.Membership{}, err
+	}
+
+	return membership, nil
+}
+
+// End stops the validity of a membership at the given time, e.g. when its payment is refunded.
+//
+// The bookings already made with the membership are kept.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: tx *sqlx.Tx - Transaction of the refund.
+// param: membershipId int - ID of the membership.
+// param: at time.Time - End of the validity period.
+//
+// @return error - Error if there is an issue updating the membership.
+func End(ctx context.Context, tx *sqlx.Tx, membershipId int, at time.Time) error {
+	_, err := tx.ExecContext(ctx, endMembership, membershipId, at)
+	return err
+}
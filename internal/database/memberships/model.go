@@ -0,0 +1,55 @@
+package memberships
+
+import (
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+)
+
+type PlanRow struct {
+	Id           int       `db:"id"`
+	Name         string    `db:"plan_name"`
+	Type         string    `db:"plan_type"`
+	Credits      *int      `db:"credits"`
+	ValidityDays int       `db:"validity_days"`
+	Price        int       `db:"price_cents"`
+	CreateDate   time.Time `db:"create_date"`
+}
+
+type MembershipRow struct {
+	Id               int       `db:"id"`
+	UserId           int       `db:"user_id"`
+	PlanId           int       `db:"plan_id"`
+	PlanName         string    `db:"plan_name"`
+	PlanType         string    `db:"plan_type"`
+	ValidFrom        time.Time `db:"valid_from"`
+	ValidUntil       time.Time `db:"valid_until"`
+	RemainingCredits *int      `db:"remaining_credits"`
+}
+
+func toPlan(pr PlanRow) api.Plan {
+	plan := api.Plan{
+		Id:           pr.Id,
+		Name:         pr.Name,
+		Type:         pr.Type,
+		ValidityDays: pr.ValidityDays,
+		Price:        pr.Price,
+	}
+	if pr.Credits != nil {
+		plan.Credits = *pr.Credits
+	}
+	return plan
+}
+
+func toMembership(mr MembershipRow) api.Membership {
+	return api.Membership{
+		Id:               mr.Id,
+		UserId:           mr.UserId,
+		PlanId:           mr.PlanId,
+		PlanName:         mr.PlanName,
+		Type:             mr.PlanType,
+		ValidFrom:        mr.ValidFrom,
+		ValidUntil:       mr.ValidUntil,
+		RemainingCredits: mr.RemainingCredits,
+	}
+}
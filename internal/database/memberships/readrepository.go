@@ -0,0 +1,101 @@
+package memberships
+
+import (
+	"context"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type ReadRepository interface {
+	ListPlans(ctx context.Context) ([]api.Plan, error)
+	GetPlanById(ctx context.Context, planId int) (api.Plan, error)
+	ListUserMemberships(ctx context.Context, userId int) ([]api.Membership, error)
+}
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewReadRepository(db *sqlx.DB) ReadRepository {
+	return &repository{db: db}
+}
+
+// ListPlans retrieves all the membership plans of the studio.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+//
+// @return []api.Plan - Slice of Plan structs representing the plans.
+// @return error - Error if there is an issue retrieving the plans from the database.
+func (r *repository) ListPlans(ctx context.Context) ([]api.Plan, error) {
+	rows, err := r.db.QueryxContext(ctx, findPlans)
+	if err != nil {
+		return nil, errors.Wrap(err, "membershipsRepo.ListPlans.QueryxContext")
+	}
+
+	defer rows.Close()
+
+	plans := []api.Plan{}
+
+	for rows.Next() {
+		var plan PlanRow
+		if err = rows.StructScan(&plan); err != nil {
+			return nil, errors.Wrap(err, "membershipsRepo.ListPlans.StructScan")
+		}
+
+		plans = append(plans, toPlan(plan))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "membershipsRepo.ListPlans.rows.Err")
+	}
+
+	return plans, nil
+}
+
+// GetPlanById retrieves a membership plan by its unique identifier.
+//
+// It returns sql.ErrNoRows if the plan does not exist.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: planId int - ID of the plan to retrieve.
+//
+// @return api.Plan - Plan struct representing the plan.
+// @return error - Error if there is an issue retrieving the plan.
+func (r *repository) GetPlanById(ctx context.Context, planId int) (api.Plan, error) {
+	return PlanById(ctx, r.db, planId)
+}
+
+// ListUserMemberships retrieves the memberships of a user, the latest to expire first.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user.
+//
+// @return []api.Membership - Slice of Membership structs, including the expired ones.
+// @return error - Error if there is an issue retrieving the memberships from the database.
+func (r *repository) ListUserMemberships(ctx context.Context, userId int) ([]api.Membership, error) {
+	rows, err := r.db.QueryxContext(ctx, findUserMemberships, userId)
+	if err != nil {
+		return nil, errors.Wrap(err, "membershipsRepo.ListUserMemberships.QueryxContext")
+	}
+
+	defer rows.Close()
+
+	memberships := []api.Membership{}
+
+	for rows.Next() {
+		var membership MembershipRow
+		if err = rows.StructScan(&membership); err != nil {
+			return nil, errors.Wrap(err, "membershipsRepo.ListUserMemberships.StructScan")
+		}
+
+		memberships = append(memberships, toMembership(membership))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "membershipsRepo.ListUserMemberships.rows.Err")
+	}
+
+	return memberships, nil
+}
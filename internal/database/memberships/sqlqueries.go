@@ -0,0 +1,67 @@
+package memberships
+
+const (
+	findPlans = `SELECT id, plan_name, plan_type, credits, validity_days, price_cents, create_date
+					FROM membership_plans
+					ORDER BY id`
+
+	findPlanById = `SELECT id, plan_name, plan_type, credits, validity_days, price_cents, create_date
+						FROM membership_plans
+						WHERE id = $1`
+
+	findPlanByName = `SELECT id
+						FROM membership_plans
+						WHERE plan_name = $1`
+
+	findUserMemberships = `SELECT m.id, m.user_id, m.plan_id, p.plan_name, p.plan_type, m.valid_from, m.valid_until, m.remaining_credits
+							FROM user_memberships m
+							INNER JOIN membership_plans p ON p.id = m.plan_id
+							WHERE m.user_id = $1
+							ORDER BY m.valid_until DESC, m.id`
+
+	AddPlanRow = `INSERT INTO membership_plans (plan_name, plan_type, credits, validity_days, price_cents)
+					VALUES(:plan_name, :plan_type, :credits, :validity_days, :price_cents)`
+
+	addMembership = `INSERT INTO user_memberships (user_id, plan_id, valid_from, valid_until, remaining_credits)
+						VALUES($1, $2, $3, $4, $5)
+						RETURNING id`
+
+	endMembership = `UPDATE user_memberships
+						SET valid_until = GREATEST(valid_from, LEAST(valid_until, $2))
+						WHERE id = $1`
+
+	// Unlimited memberships first, then the credit pack expiring first
+	lockUsableMembership = `SELECT id, remaining_credits
+							FROM user_memberships
+							WHERE user_id = $1 AND valid_from <= $2 AND valid_until > $2
+								AND (remaining_credits IS NULL OR remaining_credits > 0)
+							ORDER BY remaining_credits IS NOT NULL, valid_until, id
+							LIMIT 1
+							FOR UPDATE`
+
+	countValidMemberships = `SELECT COUNT(*)
+								FROM user_memberships
+								WHERE user_id = $1 AND valid_from <= $2 AND valid_until > $2`
+
+	consumeCredit = `UPDATE user_memberships
+						SET remaining_credits = remaining_credits - 1
+						WHERE id = $1`
+
+	refundCredit = `UPDATE user_memberships
+						SET remaining_credits = remaining_credits + 1
+						WHERE id = $1 AND remaining_credits IS NOT NULL`
+
+	refundClassesCredits = `UPDATE user_memberships m
+							SET remaining_credits = m.remaining_credits + held.credits
+							FROM (
+								SELECT membership_id, COUNT(*) AS credits
+								FROM (
+									SELECT membership_id FROM booking WHERE class_id = ANY($1)
+									UNION ALL
+									SELECT membership_id FROM waitlist WHERE class_id = ANY($1)
+								) h
+								WHERE membership_id IS NOT NULL
+								GROUP BY membership_id
+							) held
+							WHERE m.id = held.membership_id AND m.remaining_credits IS NOT NULL`
+)
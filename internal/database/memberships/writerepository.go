@@ -0,0 +1,95 @@
+package memberships
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+type WriteRepository interface {
+	AddPlan(ctx context.Context, plan api.CreatePlan) error
+	Assign(ctx context.Context, userId int, plan api.Plan, validFrom time.Time) (api.Membership, error)
+}
+
+func NewWriteRepository(db *sqlx.DB) WriteRepository {
+	return &repository{db: db}
+}
+
+// AddPlan inserts a new membership plan into the repository.
+//
+// Plan names are unique, so it returns a HTTP 409 error if a plan with the same name exists.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: plan api.CreatePlan - Struct containing the plan to be inserted.
+//
+// @return error - Error if there is an issue inserting the plan into the database.
+func (r *repository) AddPlan(ctx context.Context, plan api.CreatePlan) error {
+	var existingId int
+	err := r.db.QueryRowContext(ctx, findPlanByName, plan.Name).Scan(&existingId)
+	if err == nil {
+		return utils.E(http.StatusConflict,
+			nil,
+			map[string]string{"message": "Conflict Status"},
+			fmt.Sprintf("Plan with name %s already exists", plan.Name),
+			"Please choose a different plan name.")
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	pr := PlanRow{
+		Name:         plan.Name,
+		Type:         plan.Type,
+		ValidityDays: plan.ValidityDays,
+		Price:        plan.Price,
+	}
+	if plan.Type == api.PlanTypeCredits {
+		pr.Credits = &plan.Credits
+	}
+
+	_, err = r.db.NamedExecContext(ctx, AddPlanRow, pr)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Assign gives a membership plan to a user.
+//
+// The membership is valid for the validity days of the plan starting at validFrom,
+// and a credit pack starts with all the credits of the plan.
+// It returns a HTTP 404 error if the user does not exist.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user.
+// param: plan api.Plan - Plan of the membership.
+// param: validFrom time.Time - Start of the validity period.
+//
+// @return api.Membership - The new membership.
+// @return error - Error if there is an issue inserting the membership into the database.
+func (r *repository) Assign(ctx context.Context, userId int, plan api.Plan, validFrom time.Time) (api.Membership, error) {
+	var userIdValidation int
+	err := r.db.QueryRowContext(ctx, "SELECT id FROM users WHERE id = $1", userId).Scan(&userIdValidation)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.Membership{}, utils.E(http.StatusNotFound,
+				nil,
+				map[string]string{"message": "User Not Found"},
+				"The specified user does not exist.",
+				"Please provide a valid user ID.")
+		}
+
+		return api.Membership{}, err
+	}
+
+	return Grant(ctx, r.db, userId, plan, validFrom)
+}
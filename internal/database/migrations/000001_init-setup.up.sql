@@ -49,3 +49,4 @@ CREATE TRIGGER users_last_update_trigger
 BEFORE UPDATE ON users
 FOR EACH ROW
 EXECUTE FUNCTION update_users_last_update_date();
+
@@ -0,0 +1,9 @@
+CREATE TABLE waitlist (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    class_id INT NOT NULL REFERENCES classes(id),
+    join_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, class_id)
+);
+
+CREATE INDEX waitlist_class_queue_idx ON waitlist (class_id, id);
@@ -0,0 +1 @@
+ALTER TABLE classes ADD COLUMN class_duration INT NOT NULL DEFAULT 60;
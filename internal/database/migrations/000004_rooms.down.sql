@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS classes_room_date_idx;
+ALTER TABLE classes DROP COLUMN IF EXISTS room_id;
+DROP TABLE IF EXISTS rooms;
+DROP FUNCTION IF EXISTS update_rooms_last_update_date();
@@ -0,0 +1,25 @@
+CREATE TABLE rooms (
+    id SERIAL PRIMARY KEY,
+    room_name VARCHAR(50) NOT NULL UNIQUE,
+    max_capacity INT NOT NULL CHECK (max_capacity > 0),
+    create_date TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_update_date TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE classes ADD COLUMN room_id INT REFERENCES rooms(id);
+
+CREATE INDEX classes_room_date_idx ON classes (room_id, class_date);
+
+-- Triggers --
+CREATE OR REPLACE FUNCTION update_rooms_last_update_date()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.last_update_date = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER rooms_last_update_trigger
+BEFORE UPDATE ON rooms
+FOR EACH ROW
+EXECUTE FUNCTION update_rooms_last_update_date();
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS classes_instructor_date_idx;
+ALTER TABLE classes DROP COLUMN IF EXISTS instructor_id;
+DROP TABLE IF EXISTS instructors;
+DROP FUNCTION IF EXISTS update_instructors_last_update_date();
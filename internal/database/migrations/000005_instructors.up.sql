@@ -0,0 +1,29 @@
+CREATE TABLE instructors (
+    id SERIAL PRIMARY KEY,
+    instructor_name VARCHAR(50) NOT NULL,
+    email VARCHAR(100) NOT NULL DEFAULT '',
+    phone VARCHAR(30) NOT NULL DEFAULT '',
+    bio TEXT NOT NULL DEFAULT '',
+    create_date TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_update_date TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX instructors_email_idx ON instructors (email) WHERE email <> '';
+
+ALTER TABLE classes ADD COLUMN instructor_id INT REFERENCES instructors(id);
+
+CREATE INDEX classes_instructor_date_idx ON classes (instructor_id, class_date);
+
+-- Triggers --
+CREATE OR REPLACE FUNCTION update_instructors_last_update_date()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.last_update_date = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER instructors_last_update_trigger
+BEFORE UPDATE ON instructors
+FOR EACH ROW
+EXECUTE FUNCTION update_instructors_last_update_date();
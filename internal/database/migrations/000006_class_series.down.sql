@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS classes_series_date_idx;
+ALTER TABLE classes DROP COLUMN IF EXISTS class_status;
+ALTER TABLE classes DROP COLUMN IF EXISTS series_id;
+DROP TABLE IF EXISTS class_series;
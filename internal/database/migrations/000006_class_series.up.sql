@@ -0,0 +1,11 @@
+CREATE TABLE class_series (
+    id SERIAL PRIMARY KEY,
+    recurrence_rule VARCHAR(255) NOT NULL DEFAULT '',
+    create_date TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE classes ADD COLUMN series_id INT REFERENCES class_series(id);
+ALTER TABLE classes ADD COLUMN class_status VARCHAR(20) NOT NULL DEFAULT 'scheduled'
+    CHECK (class_status IN ('scheduled', 'cancelled'));
+
+CREATE INDEX classes_series_date_idx ON classes (series_id, class_date);
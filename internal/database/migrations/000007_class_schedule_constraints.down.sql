@@ -0,0 +1,4 @@
+ALTER TABLE classes DROP CONSTRAINT IF EXISTS classes_instructor_no_overlap;
+ALTER TABLE classes DROP CONSTRAINT IF EXISTS classes_room_no_overlap;
+
+DROP FUNCTION IF EXISTS class_time_range(TIMESTAMP WITH TIME ZONE, INT);
@@ -0,0 +1,16 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Time range [start, end) taken by a class
+CREATE OR REPLACE FUNCTION class_time_range(class_date TIMESTAMP WITH TIME ZONE, class_duration INT)
+RETURNS TSTZRANGE AS $$
+    SELECT tstzrange(class_date, class_date + class_duration * INTERVAL '1 minute', '[)');
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- Classes without a room share the same schedule, as they do in the application cache
+ALTER TABLE classes ADD CONSTRAINT classes_room_no_overlap
+    EXCLUDE USING gist (COALESCE(room_id, 0) WITH =, class_time_range(class_date, class_duration) WITH &&)
+    WHERE (class_status = 'scheduled');
+
+ALTER TABLE classes ADD CONSTRAINT classes_instructor_no_overlap
+    EXCLUDE USING gist (instructor_id WITH =, class_time_range(class_date, class_duration) WITH &&)
+    WHERE (class_status = 'scheduled' AND instructor_id IS NOT NULL);
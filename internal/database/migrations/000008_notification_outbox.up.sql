@@ -0,0 +1,11 @@
+CREATE TABLE notification_outbox (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    class_id INT NOT NULL REFERENCES classes(id),
+    event_type VARCHAR(50) NOT NULL,
+    create_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_date TIMESTAMP WITH TIME ZONE
+);
+
+-- Notifications waiting to be delivered
+CREATE INDEX notification_outbox_pending_idx ON notification_outbox (id) WHERE sent_date IS NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS booking_pending_attendance_idx;
+ALTER TABLE booking DROP COLUMN IF EXISTS checked_in_by;
+ALTER TABLE booking DROP COLUMN IF EXISTS checked_in_at;
+ALTER TABLE booking DROP COLUMN IF EXISTS attendance_status;
@@ -0,0 +1,7 @@
+ALTER TABLE booking ADD COLUMN attendance_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+    CHECK (attendance_status IN ('pending', 'attended', 'no_show'));
+ALTER TABLE booking ADD COLUMN checked_in_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE booking ADD COLUMN checked_in_by INT REFERENCES users(id);
+
+-- Bookings still waiting for a check-in
+CREATE INDEX booking_pending_attendance_idx ON booking (class_id) WHERE attendance_status = 'pending';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS late_cancellations_user_idx;
+DROP TABLE IF EXISTS late_cancellations;
+ALTER TABLE classes DROP COLUMN IF EXISTS class_type;
@@ -0,0 +1,12 @@
+-- Type of the class, selecting the booking policy overrides of the studio
+ALTER TABLE classes ADD COLUMN class_type VARCHAR(50) NOT NULL DEFAULT '';
+
+-- Bookings cancelled within the late cancellation window of their class
+CREATE TABLE late_cancellations (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    class_id INT NOT NULL REFERENCES classes(id),
+    cancel_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX late_cancellations_user_idx ON late_cancellations (user_id);
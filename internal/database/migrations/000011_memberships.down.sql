@@ -0,0 +1,5 @@
+ALTER TABLE waitlist DROP COLUMN IF EXISTS membership_id;
+ALTER TABLE booking DROP COLUMN IF EXISTS membership_id;
+DROP INDEX IF EXISTS user_memberships_user_idx;
+DROP TABLE IF EXISTS user_memberships;
+DROP TABLE IF EXISTS membership_plans;
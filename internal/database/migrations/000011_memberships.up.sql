@@ -0,0 +1,28 @@
+CREATE TABLE membership_plans (
+    id SERIAL PRIMARY KEY,
+    plan_name VARCHAR(50) NOT NULL UNIQUE,
+    plan_type VARCHAR(20) NOT NULL CHECK (plan_type IN ('unlimited', 'credits')),
+    -- Classes included in a credit pack, NULL for unlimited plans
+    credits INT CHECK (credits > 0),
+    validity_days INT NOT NULL CHECK (validity_days > 0),
+    create_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((plan_type = 'credits') = (credits IS NOT NULL))
+);
+
+CREATE TABLE user_memberships (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    plan_id INT NOT NULL REFERENCES membership_plans(id),
+    valid_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    valid_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- Credits left of a credit pack, NULL for unlimited plans
+    remaining_credits INT CHECK (remaining_credits >= 0),
+    create_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (valid_until > valid_from)
+);
+
+CREATE INDEX user_memberships_user_idx ON user_memberships (user_id, valid_until);
+
+-- Membership whose credit holds the seat or the waitlist entry
+ALTER TABLE booking ADD COLUMN membership_id INT REFERENCES user_memberships(id);
+ALTER TABLE waitlist ADD COLUMN membership_id INT REFERENCES user_memberships(id);
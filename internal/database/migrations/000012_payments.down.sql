@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS payment_ledger_payment_idx;
+DROP TABLE IF EXISTS payment_ledger;
+DROP INDEX IF EXISTS payments_user_idx;
+DROP TABLE IF EXISTS payments;
+ALTER TABLE booking DROP COLUMN IF EXISTS booking_status;
+ALTER TABLE membership_plans DROP COLUMN IF EXISTS price_cents;
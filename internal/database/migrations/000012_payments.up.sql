@@ -0,0 +1,38 @@
+ALTER TABLE membership_plans ADD COLUMN price_cents INT NOT NULL DEFAULT 0 CHECK (price_cents >= 0);
+
+-- Drop-in bookings hold their seat until the payment succeeds
+ALTER TABLE booking ADD COLUMN booking_status VARCHAR(20) NOT NULL DEFAULT 'confirmed'
+    CHECK (booking_status IN ('confirmed', 'pending_payment'));
+
+CREATE TABLE payments (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    purpose VARCHAR(20) NOT NULL CHECK (purpose IN ('membership', 'drop_in')),
+    plan_id INT REFERENCES membership_plans(id),
+    class_id INT REFERENCES classes(id),
+    -- Membership granted when a membership payment is captured
+    membership_id INT REFERENCES user_memberships(id),
+    amount_cents INT NOT NULL CHECK (amount_cents >= 0),
+    currency VARCHAR(3) NOT NULL,
+    payment_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (payment_status IN ('pending', 'captured', 'failed', 'refunded')),
+    provider VARCHAR(30) NOT NULL,
+    provider_ref VARCHAR(100) UNIQUE,
+    create_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_update_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((purpose = 'membership') = (plan_id IS NOT NULL)),
+    CHECK ((purpose = 'drop_in') = (class_id IS NOT NULL))
+);
+
+CREATE INDEX payments_user_idx ON payments (user_id);
+
+-- Append only record of the money movements of every payment
+CREATE TABLE payment_ledger (
+    id SERIAL PRIMARY KEY,
+    payment_id INT NOT NULL REFERENCES payments(id),
+    entry_type VARCHAR(20) NOT NULL CHECK (entry_type IN ('intent', 'capture', 'refund', 'failure')),
+    amount_cents INT NOT NULL,
+    create_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX payment_ledger_payment_idx ON payment_ledger (payment_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS user_credentials_email_idx;
+DROP TABLE IF EXISTS user_credentials;
@@ -0,0 +1,10 @@
+-- Login of the users, a user without credentials cannot sign in
+CREATE TABLE user_credentials (
+    user_id INT PRIMARY KEY REFERENCES users(id),
+    email VARCHAR(254) NOT NULL,
+    password_hash VARCHAR(100) NOT NULL,
+    create_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_update_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX user_credentials_email_idx ON user_credentials (lower(email));
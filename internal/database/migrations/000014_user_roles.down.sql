@@ -0,0 +1,2 @@
+ALTER TABLE instructors DROP COLUMN IF EXISTS user_id;
+ALTER TABLE users DROP COLUMN IF EXISTS user_role;
@@ -0,0 +1,6 @@
+-- Roles of the users: owners manage the studio, instructors teach classes, members book them
+ALTER TABLE users ADD COLUMN user_role VARCHAR(20) NOT NULL DEFAULT 'member'
+    CHECK (user_role IN ('owner', 'instructor', 'member'));
+
+-- Account of an instructor, to know the classes an instructor user teaches
+ALTER TABLE instructors ADD COLUMN user_id INT UNIQUE REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,13 @@
+-- Keys of the machine clients, e.g. the front-desk kiosk. Only the SHA-256 of a key is stored,
+-- the prefix identifies it in the listings.
+CREATE TABLE api_keys (
+    id SERIAL PRIMARY KEY,
+    key_name VARCHAR(50) NOT NULL,
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    created_by INT NOT NULL REFERENCES users(id),
+    create_date TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_date TIMESTAMP WITH TIME ZONE,
+    revoked_date TIMESTAMP WITH TIME ZONE
+);
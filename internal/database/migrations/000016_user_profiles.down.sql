@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS users_email_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS emergency_contact_phone;
+ALTER TABLE users DROP COLUMN IF EXISTS emergency_contact_name;
+ALTER TABLE users DROP COLUMN IF EXISTS birth_date;
+ALTER TABLE users DROP COLUMN IF EXISTS phone;
+ALTER TABLE users DROP COLUMN IF EXISTS email;
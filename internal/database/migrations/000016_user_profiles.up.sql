@@ -0,0 +1,11 @@
+-- Contact details of the users, an empty value is not set
+ALTER TABLE users ADD COLUMN email VARCHAR(254) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN phone VARCHAR(30) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN birth_date DATE;
+ALTER TABLE users ADD COLUMN emergency_contact_name VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN emergency_contact_phone VARCHAR(30) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX users_email_idx ON users (lower(email)) WHERE email <> '';
+
+-- Users already signing in are contacted at their sign in email
+UPDATE users u SET email = lower(c.email) FROM user_credentials c WHERE c.user_id = u.id;
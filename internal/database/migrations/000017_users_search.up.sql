@@ -0,0 +1,5 @@
+-- Trigram indexes for the partial, case insensitive search of the users by name and email (ILIKE '%...%')
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX users_name_trgm_idx ON users USING gin (user_name gin_trgm_ops);
+CREATE INDEX users_email_trgm_idx ON users USING gin (email gin_trgm_ops);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS booking_payment_idx;
+ALTER TABLE booking DROP COLUMN IF EXISTS payment_id;
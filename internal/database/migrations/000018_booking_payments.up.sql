@@ -0,0 +1,9 @@
+-- Payment of a drop-in booking, the bookings made with a membership have none
+ALTER TABLE booking ADD COLUMN payment_id INT REFERENCES payments(id);
+
+UPDATE booking b SET payment_id = p.id
+FROM payments p
+WHERE p.user_id = b.user_id AND p.class_id = b.class_id AND p.purpose = 'drop_in'
+    AND p.payment_status IN ('pending', 'captured') AND b.membership_id IS NULL;
+
+CREATE UNIQUE INDEX booking_payment_idx ON booking (payment_id);
@@ -0,0 +1,5 @@
+ALTER TABLE user_credentials ADD COLUMN email VARCHAR(254) NOT NULL DEFAULT '';
+UPDATE user_credentials c SET email = u.email FROM users u WHERE u.id = c.user_id;
+ALTER TABLE user_credentials ALTER COLUMN email DROP DEFAULT;
+
+CREATE UNIQUE INDEX user_credentials_email_idx ON user_credentials (lower(email));
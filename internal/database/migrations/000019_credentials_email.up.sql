@@ -0,0 +1,9 @@
+-- The users sign in with their contact email, so the two can no longer differ.
+-- A contact email changed since the registration is the one kept, a cleared one gets the sign in email back.
+UPDATE users u SET email = lower(c.email)
+FROM user_credentials c
+WHERE c.user_id = u.id AND u.email = ''
+    AND NOT EXISTS (SELECT 1 FROM users o WHERE o.email <> '' AND lower(o.email) = lower(c.email));
+
+DROP INDEX IF EXISTS user_credentials_email_idx;
+ALTER TABLE user_credentials DROP COLUMN IF EXISTS email;
@@ -0,0 +1,153 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+// lockKey is the Postgres advisory lock taken by the servers migrating the database on startup.
+const lockKey int64 = 4628170394150227
+
+// ErrSchemaAhead is returned when the database has a migration this binary does not know,
+// e.g. during a rolling deploy when a newer server already migrated the database.
+var ErrSchemaAhead = errors.New("the database schema is ahead of the migrations of this binary")
+
+// Migrate applies the pending migrations to the database, for the servers migrating on startup.
+//
+// Servers starting together wait for each other on a Postgres advisory lock, so one migrates and the others
+// find the database up to date. A database ahead of the binary, or left dirty by a failed migration, is refused.
+//
+// param: ctx context.Context - Context object for managing the lifecycle of the migration.
+// param: db *sql.DB - Database to migrate, left open.
+//
+// @return uint - Version of the database before the migration, 0 if it had none.
+// @return uint - Version of the database after the migration.
+// @return error - ErrSchemaAhead if the database is ahead of the binary, or the error of a failed migration.
+func Migrate(ctx context.Context, db *sql.DB) (uint, uint, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey)
+	if err != nil {
+		return 0, 0, fmt.Errorf("lock the migrations: %w", err)
+	}
+	defer func() {
+		// the connection goes back to the pool, it must not keep the lock
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey); err != nil {
+			log.Printf("Unlock the migrations: %v", err)
+		}
+	}()
+
+	// the driver uses the locked connection, migrate.Close is not called as it would close it
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	src, err := Source()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer src.Close()
+
+	m, err := migrate.NewWithInstance(SourceName, src, "postgres", driver)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	current, dirty, err := m.Version()
+	applied := !errors.Is(err, migrate.ErrNilVersion)
+	if err != nil && applied {
+		return 0, 0, err
+	}
+
+	versions, err := Versions(src)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err = checkVersion(versions, current, applied, dirty); err != nil {
+		return current, current, err
+	}
+
+	if len(versions) == 0 {
+		return current, current, nil
+	}
+
+	err = m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return current, current, err
+	}
+
+	return current, versions[len(versions)-1], nil
+}
+
+// Versions lists the versions of a migration source, in order.
+//
+// param: src source.Driver - Source of the migrations.
+//
+// @return []uint - Versions of the migrations, empty if the source has none.
+// @return error - Error if the source cannot be read.
+func Versions(src source.Driver) ([]uint, error) {
+	version, err := src.First()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []uint{version}
+	for {
+		version, err = src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return versions, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, version)
+	}
+}
+
+// checkVersion refuses a database left dirty by a failed migration or with a version unknown to the binary.
+func checkVersion(versions []uint, current uint, applied bool, dirty bool) error {
+	if !applied {
+		return nil
+	}
+
+	if dirty {
+		return fmt.Errorf("the database is dirty at version %d, fix it and run the force command of the migration tool", current)
+	}
+
+	if len(versions) == 0 || current > versions[len(versions)-1] {
+		return fmt.Errorf("%w: database at version %d, binary up to version %d", ErrSchemaAhead, current, last(versions))
+	}
+
+	if !slices.Contains(versions, current) {
+		return fmt.Errorf("version %d of the database has no migration in this binary", current)
+	}
+
+	return nil
+}
+
+func last(versions []uint) uint {
+	if len(versions) == 0 {
+		return 0
+	}
+
+	return versions[len(versions)-1]
+}
@@ -0,0 +1,56 @@
+//go:build unittests
+// +build unittests
+
+package migrations
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersions(t *testing.T) {
+	// Arrange
+	src, err := Source()
+	assert.NoError(t, err)
+	defer src.Close()
+
+	// Act
+	versions, err := Versions(src)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, versions)
+	assert.Equal(t, uint(1), versions[0])
+	assert.IsIncreasing(t, versions)
+}
+
+func TestCheckVersion(t *testing.T) {
+	versions := []uint{1, 2, 3}
+
+	testCases := []struct {
+		testName string
+		current  uint
+		applied  bool
+		dirty    bool
+		ahead    bool
+		valid    bool
+	}{
+		{"empty database", 0, false, false, false, true},
+		{"behind", 2, true, false, false, true},
+		{"up to date", 3, true, false, false, true},
+		{"ahead", 4, true, false, true, false},
+		{"dirty", 2, true, true, false, false},
+		{"unknown version", 0, true, false, false, false},
+	}
+
+	for _, tc := range testCases {
+		// Act
+		err := checkVersion(versions, tc.current, tc.applied, tc.dirty)
+
+		// Assert
+		assert.Equal(t, tc.valid, err == nil, tc.testName)
+		assert.Equal(t, tc.ahead, errors.Is(err, ErrSchemaAhead), tc.testName)
+	}
+}
@@ -0,0 +1,24 @@
+// Package migrations embeds the SQL migrations of the database in the binaries, so the migration tool,
+// the server and the integration tests run the same migrations from any directory.
+package migrations
+
+import (
+	"embed"
+
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// SourceName is the name of the migration source for migrate.NewWithSourceInstance.
+const SourceName = "iofs"
+
+//go:embed *.sql
+var files embed.FS
+
+// Source returns the embedded migrations as a golang-migrate source.
+//
+// @return source.Driver - Source of the migrations, closed by migrate.Migrate.Close.
+// @return error - Error if the migration files are not valid.
+func Source() (source.Driver, error) {
+	return iofs.New(files, ".")
+}
@@ -0,0 +1,58 @@
+package payments
+
+import (
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+)
+
+// Ledger entry types, one entry per movement of a payment.
+const (
+	entryIntent  = "intent"
+	entryCapture = "capture"
+	entryRefund  = "refund"
+	entryFailure = "failure"
+)
+
+type PaymentRow struct {
+	Id           int       `db:"id"`
+	UserId       int       `db:"user_id"`
+	Purpose      string    `db:"purpose"`
+	PlanId       *int      `db:"plan_id"`
+	ClassId      *int      `db:"class_id"`
+	MembershipId *int      `db:"membership_id"`
+	Amount       int       `db:"amount_cents"`
+	Currency     string    `db:"currency"`
+	Status       string    `db:"payment_status"`
+	Provider     string    `db:"provider"`
+	ProviderRef  *string   `db:"provider_ref"`
+	CreateDate   time.Time `db:"create_date"`
+}
+
+func toPayment(pr PaymentRow) api.Payment {
+	payment := api.Payment{
+		Id:           pr.Id,
+		UserId:       pr.UserId,
+		Purpose:      pr.Purpose,
+		PlanId:       idOrZero(pr.PlanId),
+		ClassId:      idOrZero(pr.ClassId),
+		MembershipId: idOrZero(pr.MembershipId),
+		Amount:       pr.Amount,
+		Currency:     pr.Currency,
+		Status:       pr.Status,
+		Provider:     pr.Provider,
+		CreateDate:   pr.CreateDate,
+	}
+	if pr.ProviderRef != nil {
+		payment.ProviderRef = *pr.ProviderRef
+	}
+	return payment
+}
+
+// idOrZero maps a NULL column to the zero id of the API models.
+func idOrZero(id *int) int {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
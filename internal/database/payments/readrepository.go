@@ -0,0 +1,117 @@
+package payments
+
+import (
+	"context"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type ReadRepository interface {
+	GetById(ctx context.Context, paymentId int) (api.Payment, error)
+	GetByProviderRef(ctx context.Context, provider string, ref string) (api.Payment, error)
+	ListUserPayments(ctx context.Context, userId int) ([]api.Payment, error)
+	IsPaidBy(ctx context.Context, paymentId int, userId int) (bool, error)
+}
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewReadRepository(db *sqlx.DB) ReadRepository {
+	return &repository{db: db}
+}
+
+// GetById retrieves a payment by its unique identifier.
+//
+// It returns sql.ErrNoRows if the payment does not exist.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: paymentId int - ID of the payment to retrieve.
+//
+// @return api.Payment - Payment struct representing the payment.
+// @return error - Error if there is an issue retrieving the payment.
+func (r *repository) GetById(ctx context.Context, paymentId int) (api.Payment, error) {
+	payment := PaymentRow{}
+
+	err := r.db.GetContext(ctx, &payment, findPaymentById, paymentId)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	return toPayment(payment), nil
+}
+
+// GetByProviderRef retrieves a payment by its reference at the payment provider.
+//
+// It returns sql.ErrNoRows if the payment does not exist.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: provider string - Name of the payment provider.
+// param: ref string - Reference of the payment at the provider.
+//
+// @return api.Payment - Payment struct representing the payment.
+// @return error - Error if there is an issue retrieving the payment.
+func (r *repository) GetByProviderRef(ctx context.Context, provider string, ref string) (api.Payment, error) {
+	payment := PaymentRow{}
+
+	err := r.db.GetContext(ctx, &payment, findPaymentByRef, provider, ref)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	return toPayment(payment), nil
+}
+
+// ListUserPayments retrieves the payments of a user, the latest first.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user.
+//
+// @return []api.Payment - Slice of Payment structs representing the payments.
+// @return error - Error if there is an issue retrieving the payments from the database.
+func (r *repository) ListUserPayments(ctx context.Context, userId int) ([]api.Payment, error) {
+	rows, err := r.db.QueryxContext(ctx, findUserPayments, userId)
+	if err != nil {
+		return nil, errors.Wrap(err, "paymentsRepo.ListUserPayments.QueryxContext")
+	}
+
+	defer rows.Close()
+
+	payments := []api.Payment{}
+
+	for rows.Next() {
+		var payment PaymentRow
+		if err = rows.StructScan(&payment); err != nil {
+			return nil, errors.Wrap(err, "paymentsRepo.ListUserPayments.StructScan")
+		}
+
+		payments = append(payments, toPayment(payment))
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "paymentsRepo.ListUserPayments.rows.Err")
+	}
+
+	return payments, nil
+}
+
+// IsPaidBy reports whether a payment is made by a user.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: paymentId int - ID of the payment.
+// param: userId int - ID of the user.
+//
+// @return bool - True if the payment belongs to the user.
+// @return error - Error if there is an issue querying the database.
+func (r *repository) IsPaidBy(ctx context.Context, paymentId int, userId int) (bool, error) {
+	var paid bool
+
+	err := r.db.GetContext(ctx, &paid, paymentOfUser, paymentId, userId)
+	if err != nil {
+		return false, err
+	}
+
+	return paid, nil
+}
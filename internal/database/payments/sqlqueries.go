@@ -0,0 +1,52 @@
+package payments
+
+const (
+	findPaymentById = `SELECT id, user_id, purpose, plan_id, class_id, membership_id, amount_cents, currency,
+							payment_status, provider, provider_ref, create_date
+						FROM payments
+						WHERE id = $1`
+
+	findPaymentByRef = `SELECT id, user_id, purpose, plan_id, class_id, membership_id, amount_cents, currency,
+							payment_status, provider, provider_ref, create_date
+						FROM payments
+						WHERE provider = $1 AND provider_ref = $2`
+
+	findUserPayments = `SELECT id, user_id, purpose, plan_id, class_id, membership_id, amount_cents, currency,
+							payment_status, provider, provider_ref, create_date
+						FROM payments
+						WHERE user_id = $1
+						ORDER BY create_date DESC, id DESC`
+
+	paymentOfUser = `SELECT EXISTS (SELECT 1 FROM payments WHERE id = $1 AND user_id = $2)`
+
+	lockPaymentRow = `SELECT id, user_id, purpose, plan_id, class_id, membership_id, amount_cents, currency,
+						payment_status, provider, provider_ref, create_date
+					FROM payments
+					WHERE id = $1
+					FOR UPDATE`
+
+	lockExpiredDropIns = `SELECT id, user_id, purpose, plan_id, class_id, membership_id, amount_cents, currency,
+							payment_status, provider, provider_ref, create_date
+						FROM payments
+						WHERE purpose = 'drop_in' AND payment_status = 'pending' AND create_date < $1
+						ORDER BY id
+						FOR UPDATE SKIP LOCKED`
+
+	addPayment = `INSERT INTO payments (user_id, purpose, plan_id, class_id, amount_cents, currency, provider)
+					VALUES ($1, $2, $3, $4, $5, $6, $7)
+					RETURNING id, payment_status, create_date`
+
+	// The seat was held by booking.HoldSeat in the same transaction, before the payment existed
+	linkPendingBooking = `UPDATE booking SET payment_id = $1
+							WHERE user_id = $2 AND class_id = $3 AND booking_status = 'pending_payment' AND payment_id IS NULL`
+
+	addLedgerEntry = `INSERT INTO payment_ledger (payment_id, entry_type, amount_cents)
+						VALUES ($1, $2, $3)`
+
+	setProviderRef = `UPDATE payments SET provider_ref = $2, last_update_date = CURRENT_TIMESTAMP
+						WHERE id = $1`
+
+	updatePaymentStatus = `UPDATE payments SET payment_status = $2, membership_id = COALESCE($3, membership_id),
+								last_update_date = CURRENT_TIMESTAMP
+							WHERE id = $1`
+)
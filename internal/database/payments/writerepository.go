@@ -0,0 +1,523 @@
+package payments
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/database/booking"
+	"github.com/Flgado/fitnessStudioApp/internal/database/memberships"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+type WriteRepository interface {
+	AddMembershipPayment(ctx context.Context, userId int, plan api.Plan, currency string, provider string) (api.Payment, error)
+	AddDropInPayment(ctx context.Context, userId int, classId int, amount int, currency string, provider string, now time.Time, check booking.BookingCheck) (api.Payment, error)
+	SetProviderRef(ctx context.Context, paymentId int, ref string) error
+	Capture(ctx context.Context, paymentId int, now time.Time, collect ProviderAction) (api.Payment, error)
+	Fail(ctx context.Context, paymentId int, now time.Time) (api.Payment, error)
+	Refund(ctx context.Context, paymentId int, now time.Time, refund ProviderAction) (api.Payment, error)
+	RefundFailed(ctx context.Context, paymentId int, refund ProviderAction) (api.Payment, error)
+	ExpireDropIns(ctx context.Context, createdBefore time.Time, now time.Time) (int64, error)
+}
+
+// ProviderAction moves the money of a payment with the payment provider. The repository runs it on the
+// locked payment before committing, so the payment only changes when the provider succeeds.
+type ProviderAction func(ctx context.Context, payment api.Payment) error
+
+func NewWriteRepository(db *sqlx.DB) WriteRepository {
+	return &repository{db: db}
+}
+
+// AddMembershipPayment records a pending payment of a membership plan.
+//
+// The membership is granted when the payment is captured.
+// It returns a HTTP 404 error if the user does not exist.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user buying the plan.
+// param: plan api.Plan - Plan bought, charged at its price.
+// param: currency string - Currency of the price.
+// param: provider string - Name of the payment provider.
+//
+// @return api.Payment - The pending payment.
+// @return error - Error if the payment cannot be recorded.
+func (r *repository) AddMembershipPayment(ctx context.Context, userId int, plan api.Plan, currency string, provider string) (_ api.Payment, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return api.Payment{}, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var userIdValidation int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM users WHERE id = $1", userId).Scan(&userIdValidation)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.Payment{}, utils.E(http.StatusNotFound,
+				nil,
+				map[string]string{"message": "User Not Found"},
+				"The specified user does not exist.",
+				"Please provide a valid user ID.")
+		}
+
+		return api.Payment{}, err
+	}
+
+	payment := api.Payment{
+		UserId:   userId,
+		Purpose:  api.PaymentPurposeMembership,
+		PlanId:   plan.Id,
+		Amount:   plan.Price,
+		Currency: currency,
+		Provider: provider,
+	}
+
+	err = addPendingPayment(ctx, tx, &payment)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	return payment, nil
+}
+
+// AddDropInPayment records a pending payment of a single class and holds a seat of the class for the user.
+//
+// The booking is confirmed when the payment is captured and released if the payment fails.
+// It returns the errors of booking.HoldSeat when the class cannot be booked.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user buying the class.
+// param: classId int - ID of the class.
+// param: amount int - Price of the drop-in in cents.
+// param: currency string - Currency of the price.
+// param: provider string - Name of the payment provider.
+// param: now time.Time - Time of the booking.
+// param: check booking.BookingCheck - Additional check of the locked class, nil for none.
+//
+// @return api.Payment - The pending payment.
+// @return error - Error if the seat cannot be held or the payment cannot be recorded.
+func (r *repository) AddDropInPayment(ctx context.Context, userId int, classId int, amount int, currency string, provider string, now time.Time, check booking.BookingCheck) (_ api.Payment, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return api.Payment{}, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	err = booking.HoldSeat(ctx, tx, userId, classId, now, check)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	payment := api.Payment{
+		UserId:   userId,
+		Purpose:  api.PaymentPurposeDropIn,
+		ClassId:  classId,
+		Amount:   amount,
+		Currency: currency,
+		Provider: provider,
+	}
+
+	err = addPendingPayment(ctx, tx, &payment)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, linkPendingBooking, payment.Id, userId, classId)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	return payment, nil
+}
+
+// SetProviderRef stores the reference of a payment at the payment provider.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: paymentId int - ID of the payment.
+// param: ref string - Reference of the payment at the provider.
+//
+// @return error - Error if there is an issue updating the payment.
+func (r *repository) SetProviderRef(ctx context.Context, paymentId int, ref string) error {
+	_, err := r.db.ExecContext(ctx, setProviderRef, paymentId, ref)
+	return err
+}
+
+// Capture records the success of a pending payment and delivers what was bought.
+//
+// A membership payment grants the membership of the plan starting now, a drop-in payment confirms
+// the booking of the class. The money is collected last, once the delivery succeeded.
+// Capturing a captured payment again changes nothing, so the callbacks of
+// the provider can be repeated. It returns a HTTP 404 error if the payment does not exist, a
+// HTTP 409 error if the payment failed or was refunded and booking.ErrSeatReleased if the seat held
+// by a drop-in was released, leaving the payment pending and its money not collected.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: paymentId int - ID of the payment.
+// param: now time.Time - Time of the capture.
+// param: collect ProviderAction - Collects the money with the provider, nil when the provider already did.
+//
+// @return api.Payment - The captured payment.
+// @return error - Error if the payment cannot be captured.
+func (r *repository) Capture(ctx context.Context, paymentId int, now time.Time, collect ProviderAction) (_ api.Payment, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return api.Payment{}, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var payment api.Payment
+	payment, err = lockPendingPayment(ctx, tx, paymentId, api.PaymentStatusCaptured)
+	if err != nil || payment.Status == api.PaymentStatusCaptured {
+		return payment, err
+	}
+
+	switch payment.Purpose {
+	case api.PaymentPurposeMembership:
+		var plan api.Plan
+		plan, err = memberships.PlanById(ctx, tx, payment.PlanId)
+		if err != nil {
+			return api.Payment{}, err
+		}
+
+		var membership api.Membership
+		membership, err = memberships.Grant(ctx, tx, payment.UserId, plan, now)
+		if err != nil {
+			return api.Payment{}, err
+		}
+		payment.MembershipId = membership.Id
+	case api.PaymentPurposeDropIn:
+		err = booking.ConfirmSeat(ctx, tx, payment.Id)
+		if err != nil {
+			return api.Payment{}, err
+		}
+	}
+
+	if collect != nil {
+		err = collect(ctx, payment)
+		if err != nil {
+			return api.Payment{}, err
+		}
+	}
+
+	err = changeStatus(ctx, tx, &payment, api.PaymentStatusCaptured, entryCapture, payment.Amount)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	return payment, nil
+}
+
+// Fail records the failure of a pending payment and releases the seat held by a drop-in.
+//
+// Failing a failed payment again changes nothing. It returns a HTTP 404 error if the payment
+// does not exist and a HTTP 409 error if the payment was captured or refunded.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: paymentId int - ID of the payment.
+// param: now time.Time - Time of the failure.
+//
+// @return api.Payment - The failed payment.
+// @return error - Error if the payment cannot be failed.
+func (r *repository) Fail(ctx context.Context, paymentId int, now time.Time) (_ api.Payment, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return api.Payment{}, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var payment api.Payment
+	payment, err = lockPendingPayment(ctx, tx, paymentId, api.PaymentStatusFailed)
+	if err != nil || payment.Status == api.PaymentStatusFailed {
+		return payment, err
+	}
+
+	if payment.Purpose == api.PaymentPurposeDropIn {
+		err = booking.ReleaseSeat(ctx, tx, payment.Id, payment.ClassId, now)
+		if err != nil {
+			return api.Payment{}, err
+		}
+	}
+
+	err = changeStatus(ctx, tx, &payment, api.PaymentStatusFailed, entryFailure, 0)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	return payment, nil
+}
+
+// Refund records the refund of a captured payment and takes back what was bought.
+//
+// A refunded membership ends now, keeping the bookings already made with it, and a refunded
+// drop-in releases its booking. The money is given back last, once the membership or booking is taken back.
+// It returns a HTTP 404 error if the payment does not exist and a HTTP 409 error if the payment is not captured.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: paymentId int - ID of the payment.
+// param: now time.Time - Time of the refund.
+// param: refund ProviderAction - Gives the money back with the provider.
+//
+// @return api.Payment - The refunded payment.
+// @return error - Error if the payment cannot be refunded.
+func (r *repository) Refund(ctx context.Context, paymentId int, now time.Time, refund ProviderAction) (_ api.Payment, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return api.Payment{}, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var payment api.Payment
+	payment, err = lockPayment(ctx, tx, paymentId)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	if payment.Status != api.PaymentStatusCaptured {
+		err = PaymentStatusError(payment, "Only captured payments can be refunded.")
+		return api.Payment{}, err
+	}
+
+	switch payment.Purpose {
+	case api.PaymentPurposeMembership:
+		err = memberships.End(ctx, tx, payment.MembershipId, now)
+	case api.PaymentPurposeDropIn:
+		err = booking.ReleaseSeat(ctx, tx, payment.Id, payment.ClassId, now)
+	}
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	err = refund(ctx, payment)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	err = changeStatus(ctx, tx, &payment, api.PaymentStatusRefunded, entryRefund, -payment.Amount)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	return payment, nil
+}
+
+// RefundFailed records the refund of a failed payment whose money the provider collected anyway.
+//
+// It happens when the provider completes a drop-in after its booking was cancelled or its hold expired.
+// The money collected and given back are both recorded in the ledger. Refunding a refunded payment again
+// changes nothing, so the callbacks of the provider can be repeated without refunding the user twice.
+// It returns a HTTP 404 error if the payment does not exist and a HTTP 409 error if the payment is not failed.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: paymentId int - ID of the payment.
+// param: refund ProviderAction - Gives the money back with the provider.
+//
+// @return api.Payment - The refunded payment.
+// @return error - Error if the payment cannot be refunded.
+func (r *repository) RefundFailed(ctx context.Context, paymentId int, refund ProviderAction) (_ api.Payment, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return api.Payment{}, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var payment api.Payment
+	payment, err = lockPayment(ctx, tx, paymentId)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	if payment.Status == api.PaymentStatusRefunded {
+		return payment, nil
+	}
+
+	if payment.Status != api.PaymentStatusFailed {
+		err = PaymentStatusError(payment, "Only failed payments collected by the provider can be refunded.")
+		return api.Payment{}, err
+	}
+
+	err = refund(ctx, payment)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, addLedgerEntry, payment.Id, entryCapture, payment.Amount)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	err = changeStatus(ctx, tx, &payment, api.PaymentStatusRefunded, entryRefund, -payment.Amount)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	return payment, nil
+}
+
+// ExpireDropIns fails the pending drop-in payments created before a time and releases the seats they hold.
+//
+// A drop-in whose payment never completes would otherwise keep its seat forever. The payments being
+// captured or failed by another request are skipped, the next run expires them if they are still pending.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: createdBefore time.Time - Payments created before this time are expired.
+// param: now time.Time - Time of the expiration.
+//
+// @return int64 - Number of payments failed.
+// @return error - Error if there is an issue failing the payments.
+func (r *repository) ExpireDropIns(ctx context.Context, createdBefore time.Time, now time.Time) (_ int64, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var rows []PaymentRow
+	err = tx.SelectContext(ctx, &rows, lockExpiredDropIns, createdBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, row := range rows {
+		payment := toPayment(row)
+
+		err = booking.ReleaseSeat(ctx, tx, payment.Id, payment.ClassId, now)
+		if err != nil {
+			return 0, err
+		}
+
+		err = changeStatus(ctx, tx, &payment, api.PaymentStatusFailed, entryFailure, 0)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(rows)), nil
+}
+
+// PaymentStatusError is the HTTP 409 error of a payment whose status does not allow the operation.
+func PaymentStatusError(payment api.Payment, details string) error {
+	return utils.E(http.StatusConflict,
+		nil,
+		map[string]string{"message": "Conflict Status"},
+		fmt.Sprintf("Payment with id %d is %s. %s", payment.Id, payment.Status, details),
+		"Validate the status of the payment.")
+}
+
+func addPendingPayment(ctx context.Context, tx *sqlx.Tx, payment *api.Payment) error {
+	var planId, classId *int
+	if payment.PlanId != 0 {
+		planId = &payment.PlanId
+	}
+	if payment.ClassId != 0 {
+		classId = &payment.ClassId
+	}
+
+	err := tx.QueryRowContext(ctx, addPayment,
+		payment.UserId, payment.Purpose, planId, classId, payment.Amount, payment.Currency, payment.Provider,
+	).Scan(&payment.Id, &payment.Status, &payment.CreateDate)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, addLedgerEntry, payment.Id, entryIntent, payment.Amount)
+	return err
+}
+
+func lockPayment(ctx context.Context, tx *sqlx.Tx, paymentId int) (api.Payment, error) {
+	var row PaymentRow
+	err := tx.GetContext(ctx, &row, lockPaymentRow, paymentId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.Payment{}, utils.E(http.StatusNotFound,
+				nil,
+				map[string]string{"message": "Payment Not Found"},
+				"The specified payment does not exist.",
+				"Please provide a valid payment ID.")
+		}
+
+		return api.Payment{}, err
+	}
+
+	return toPayment(row), nil
+}
+
+// lockPendingPayment locks a payment that must be pending, or already in the target status.
+func lockPendingPayment(ctx context.Context, tx *sqlx.Tx, paymentId int, target string) (api.Payment, error) {
+	payment, err := lockPayment(ctx, tx, paymentId)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	if payment.Status != api.PaymentStatusPending && payment.Status != target {
+		return api.Payment{}, PaymentStatusError(payment, "Only pending payments can be "+target+".")
+	}
+
+	return payment, nil
+}
+
+func changeStatus(ctx context.Context, tx *sqlx.Tx, payment *api.Payment, status string, entryType string, amount int) error {
+	var membershipId *int
+	if payment.MembershipId != 0 {
+		membershipId = &payment.MembershipId
+	}
+
+	_, err := tx.ExecContext(ctx, updatePaymentStatus, payment.Id, status, membershipId)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, addLedgerEntry, payment.Id, entryType, amount)
+	if err != nil {
+		return err
+	}
+
+	payment.Status = status
+	return nil
+}
@@ -0,0 +1,11 @@
+package rooms
+
+import "time"
+
+type RoomRow struct {
+	Id             int       `db:"id"`
+	Name           string    `db:"room_name"`
+	MaxCapacity    int       `db:"max_capacity"`
+	CreateDate     time.Time `db:"create_date"`
+	LastUpdateDate time.Time `db:"last_update_date"`
+}
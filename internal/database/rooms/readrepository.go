@@ -0,0 +1,82 @@
+package rooms
+
+import (
+	"context"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+type ReadRepository interface {
+	List(ctx context.Context) ([]api.Room, error)
+	GetById(ctx context.Context, roomId int) (api.Room, error)
+}
+
+type repository struct {
+	db *sqlx.DB
+}
+
+func NewReadRepository(db *sqlx.DB) ReadRepository {
+	return &repository{db: db}
+}
+
+// List retrieves all the rooms of the studio.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+//
+// @return []api.Room - Slice of Room structs representing the rooms.
+// @return error - Error if there is an issue retrieving the rooms from the database.
+func (r *repository) List(ctx context.Context) ([]api.Room, error) {
+	rows, err := r.db.QueryxContext(ctx, findRooms)
+	if err != nil {
+		return nil, errors.Wrap(err, "roomsRepo.List.QueryxContext")
+	}
+
+	defer rows.Close()
+
+	rooms := []api.Room{}
+
+	for rows.Next() {
+		var room RoomRow
+		if err = rows.StructScan(&room); err != nil {
+			return nil, errors.Wrap(err, "roomsRepo.List.StructScan")
+		}
+
+		rooms = append(rooms, api.Room{
+			Id:          room.Id,
+			Name:        room.Name,
+			MaxCapacity: room.MaxCapacity,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "roomsRepo.List.rows.Err")
+	}
+
+	return rooms, nil
+}
+
+// GetById retrieves a room by its unique identifier.
+//
+// It returns sql.ErrNoRows if the room does not exist.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: roomId int - ID of the room to retrieve.
+//
+// @return api.Room - Room struct representing the room.
+// @return error - Error if there is an issue retrieving the room.
+func (r *repository) GetById(ctx context.Context, roomId int) (api.Room, error) {
+	room := RoomRow{}
+
+	err := r.db.GetContext(ctx, &room, findRoomById, roomId)
+	if err != nil {
+		return api.Room{}, err
+	}
+
+	return api.Room{
+		Id:          room.Id,
+		Name:        room.Name,
+		MaxCapacity: room.MaxCapacity,
+	}, nil
+}
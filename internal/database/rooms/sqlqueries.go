@@ -0,0 +1,21 @@
+package rooms
+
+const (
+	findRooms = `SELECT id, room_name, max_capacity, create_date, last_update_date
+					FROM rooms
+					ORDER BY id`
+
+	findRoomById = `SELECT id, room_name, max_capacity, create_date, last_update_date
+						FROM rooms
+						WHERE id = $1`
+
+	findRoomByName = `SELECT id
+						FROM rooms
+						WHERE room_name = $1`
+
+	largestClassCapacity = `SELECT COALESCE(MAX(class_capacity), 0)
+								FROM classes
+								WHERE room_id = $1 AND class_date >= CURRENT_TIMESTAMP`
+
+	AddRoomRow = `INSERT INTO rooms (room_name, max_capacity) VALUES(:room_name, :max_capacity)`
+)
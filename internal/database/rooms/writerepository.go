@@ -0,0 +1,151 @@
+package rooms
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+type WriteRepository interface {
+	Add(ctx context.Context, room api.CreateRoom) error
+	Update(ctx context.Context, room api.PatchRoom) (int64, error)
+}
+
+func NewWriteRepository(db *sqlx.DB) WriteRepository {
+	return &repository{db: db}
+}
+
+// Add inserts a new room into the repository.
+//
+// Room names are unique, so it returns a HTTP 409 error if a room with the same name exists.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: room api.CreateRoom - Struct containing the room to be inserted.
+//
+// @return error - Error if there is an issue inserting the room into the database.
+func (r *repository) Add(ctx context.Context, room api.CreateRoom) error {
+	var existingId int
+	err := r.db.QueryRowContext(ctx, findRoomByName, room.Name).Scan(&existingId)
+	if err == nil {
+		return utils.E(http.StatusConflict,
+			nil,
+			map[string]string{"message": "Conflict Status"},
+			fmt.Sprintf("Room with name %s already exists", room.Name),
+			"Please choose a different room name.")
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	rr := RoomRow{
+		Name:        room.Name,
+		MaxCapacity: room.MaxCapacity,
+	}
+
+	_, err = r.db.NamedExecContext(ctx, AddRoomRow, rr)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Update modifies an existing room in the repository.
+//
+// The room row is locked while the update runs. The maximum capacity cannot be lowered
+// below the capacity of an upcoming class scheduled in the room.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: room api.PatchRoom - Struct containing the fields to be modified.
+//
+// @return int64 - Number of rows affected by the update operation.
+// @return error - Error if there is an issue updating the room in the database.
+func (r *repository) Update(ctx context.Context, room api.PatchRoom) (_ int64, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	// Lock the row for the specific room being updated
+	existingRoom := RoomRow{}
+	err = tx.GetContext(ctx, &existingRoom, findRoomById+" FOR UPDATE", room.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	query := "UPDATE rooms SET "
+	args := map[string]interface{}{
+		"id": room.Id,
+	}
+	var updateFields []string
+
+	if room.Name != nil {
+		var existingId int
+		err = tx.QueryRowContext(ctx, findRoomByName, *room.Name).Scan(&existingId)
+		if err == nil && existingId != room.Id {
+			return 0, utils.E(http.StatusConflict,
+				nil,
+				map[string]string{"message": "Conflict Status"},
+				fmt.Sprintf("Room with name %s already exists", *room.Name),
+				"Please choose a different room name.")
+		}
+
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+
+		err = nil
+		updateFields = append(updateFields, "room_name=:room_name")
+		args["room_name"] = *room.Name
+	}
+
+	if room.MaxCapacity != nil {
+		var largestCapacity int
+		err = tx.QueryRowContext(ctx, largestClassCapacity, room.Id).Scan(&largestCapacity)
+		if err != nil {
+			return 0, err
+		}
+
+		// not possible to update
+		if largestCapacity > *room.MaxCapacity {
+			return 0, utils.E(http.StatusUnprocessableEntity,
+				nil,
+				map[string]string{"message": "Cannot update room capacity"},
+				fmt.Sprintf("An upcoming class in the room has capacity for %d people.", largestCapacity),
+				"Please reduce the capacity of the upcoming classes first.")
+		}
+		updateFields = append(updateFields, "max_capacity=:max_capacity")
+		args["max_capacity"] = *room.MaxCapacity
+	}
+
+	// Check if any fields are to be updated
+	if len(updateFields) == 0 {
+		return 0, nil // No fields to update
+	}
+
+	query += strings.Join(updateFields, ", ")
+	query += " WHERE id=:id"
+
+	result, err := tx.NamedExecContext(ctx, query, args)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
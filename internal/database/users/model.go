@@ -1,10 +1,27 @@
 package users
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type UserRow struct {
-	Id             int       `db:"id"`
-	Name           string    `db:"user_name"`
-	CreateDate     time.Time `db:"create_date"`
-	LastUpdateDate time.Time `db:"last_update_date"`
+	Id                    int          `db:"id"`
+	Name                  string       `db:"user_name"`
+	Role                  string       `db:"user_role"`
+	Email                 string       `db:"email"`
+	Phone                 string       `db:"phone"`
+	BirthDate             sql.NullTime `db:"birth_date"`
+	EmergencyContactName  string       `db:"emergency_contact_name"`
+	EmergencyContactPhone string       `db:"emergency_contact_phone"`
+	CreateDate            time.Time    `db:"create_date"`
+	LastUpdateDate        time.Time    `db:"last_update_date"`
+}
+
+// Credentials are the login of a user, the password is only stored hashed. The email is the contact email of the user.
+type Credentials struct {
+	UserId       int    `db:"user_id"`
+	Email        string `db:"email"`
+	PasswordHash string `db:"password_hash"`
+	Role         string `db:"user_role"`
 }
@@ -2,6 +2,8 @@ package users
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
 	"github.com/jmoiron/sqlx"
@@ -14,9 +16,18 @@ type Repository interface {
 }
 
 type ReadRepository interface {
-	List(ctx context.Context) ([]api.User, error)
+	List(ctx context.Context, filters api.UserFilters) ([]api.User, int, error)
 	GetById(ctx context.Context, id int) (api.User, error)
-	GetByName(ctx context.Context, name string) ([]api.User, error)
+	GetByEmail(ctx context.Context, email string) (api.User, error)
+	GetCredentials(ctx context.Context, email string) (Credentials, error)
+}
+
+// sortColumns are the columns of the sort fields of api.UserSortFields.
+var sortColumns = map[string]string{
+	"id":          "id",
+	"name":        "user_name",
+	"email":       "email",
+	"create_date": "create_date",
 }
 
 type repository struct {
@@ -27,74 +38,177 @@ func NewReadRepository(db *sqlx.DB) ReadRepository {
 	return &repository{db: db}
 }
 
-func (r *repository) List(ctx context.Context) ([]api.User, error) {
-	rows, err := r.db.QueryxContext(ctx, findUsers)
+// List searches the users, a page at a time.
+//
+// Search, Name and Email match part of the value, case insensitive, using the trigram indexes of users.
+// The users are sorted by filters.SortBy, then by ID so the pages do not overlap.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: filters api.UserFilters - Search, sort and page of the users, the page and its size are positive.
+//
+// @return []api.User - Users of the page.
+// @return int - Number of users matching the filters, in all the pages.
+// @return error - Error if there is an issue retrieving the users from the database.
+func (r *repository) List(ctx context.Context, filters api.UserFilters) ([]api.User, int, error) {
+	query := "SELECT " + userColumns + ", count(*) OVER() AS total FROM users WHERE 1=1"
+
+	args := make(map[string]interface{})
+	if filters.Search != "" {
+		query += " AND (user_name ILIKE :search OR email ILIKE :search)"
+		args["search"] = containsPattern(filters.Search)
+	}
+	if filters.Name != "" {
+		query += " AND user_name ILIKE :name"
+		args["name"] = containsPattern(filters.Name)
+	}
+	if filters.Email != "" {
+		query += " AND email ILIKE :email"
+		args["email"] = containsPattern(filters.Email)
+	}
+	if filters.Role != "" {
+		query += " AND user_role = :user_role"
+		args["user_role"] = filters.Role
+	}
+
+	direction := "ASC"
+	if filters.Descending {
+		direction = "DESC"
+	}
+
+	sortColumn, ok := sortColumns[filters.SortBy]
+	if !ok {
+		sortColumn = "id"
+	}
+
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT :limit OFFSET :offset", sortColumn, direction, direction)
+	args["limit"] = filters.PageSize
+	args["offset"] = (filters.Page - 1) * filters.PageSize
+
+	rows, err := r.db.NamedQueryContext(ctx, query, args)
 	if err != nil {
-		return nil, errors.Wrap(err, "authRepo.FindByName.QueryxContext")
+		return nil, 0, errors.Wrap(err, "usersRepo.List.NamedQueryContext")
 	}
 
 	defer rows.Close()
 
 	u := []api.User{}
+	total := 0
 
 	for rows.Next() {
-		var user UserRow
+		var user struct {
+			UserRow
+			Total int `db:"total"`
+		}
 		if err = rows.StructScan(&user); err != nil {
-			return nil, errors.Wrap(err, "authRepo.FindByName.StructScan")
+			return nil, 0, errors.Wrap(err, "usersRepo.List.StructScan")
 		}
 
-		readUser := api.User{
-			Id:   user.Id,
-			Name: user.Name,
-		}
-		u = append(u, readUser)
+		total = user.Total
+		u = append(u, toUser(user.UserRow))
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, errors.Wrap(err, "authRepo.FindByName.rows.Err")
+		return nil, 0, errors.Wrap(err, "usersRepo.List.rows.Err")
+	}
+
+	// a page past the last one has no row to count the users
+	if len(u) == 0 && filters.Page > 1 {
+		return r.countPastLastPage(ctx, filters)
+	}
+
+	return u, total, nil
+}
+
+// countPastLastPage counts the users matching the filters when the page is past the last one.
+func (r *repository) countPastLastPage(ctx context.Context, filters api.UserFilters) ([]api.User, int, error) {
+	filters.Page = 1
+	filters.PageSize = 1
+
+	_, total, err := r.List(ctx, filters)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return u, nil
+	return []api.User{}, total, nil
 }
 
 func (r *repository) GetById(ctx context.Context, userId int) (api.User, error) {
 	u := UserRow{}
 
-	row := r.db.QueryRowContext(ctx, findUserById, userId)
-	err := row.Scan(&u.Id, &u.Name, &u.CreateDate, &u.LastUpdateDate)
-
+	err := r.db.GetContext(ctx, &u, findUserById, userId)
 	if err != nil {
 		return api.User{}, err
 	}
 
-	readUser := api.User{
-		Id:   u.Id,
-		Name: u.Name,
+	return toUser(u), nil
+}
+
+// GetByEmail retrieves the user with a contact email, case insensitive.
+//
+// It returns sql.ErrNoRows if no user has the email.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: email string - Email of the user.
+//
+// @return api.User - The user.
+// @return error - Error if there is an issue retrieving the user.
+func (r *repository) GetByEmail(ctx context.Context, email string) (api.User, error) {
+	u := UserRow{}
+
+	err := r.db.GetContext(ctx, &u, findUserByEmail, email)
+	if err != nil {
+		return api.User{}, err
 	}
 
-	return readUser, nil
+	return toUser(u), nil
 }
 
-func (r *repository) GetByName(ctx context.Context, userName string) ([]api.User, error) {
-	rows, err := r.db.QueryxContext(ctx, findUserByName, userName)
-
+// GetCredentials retrieves the credentials of a user by email, case insensitive.
+//
+// It returns sql.ErrNoRows if no user signs in with the email.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: email string - Email of the user.
+//
+// @return Credentials - Credentials of the user.
+// @return error - Error if there is an issue retrieving the credentials.
+func (r *repository) GetCredentials(ctx context.Context, email string) (Credentials, error) {
+	c := Credentials{}
+
+	err := r.db.GetContext(ctx, &c, findCredentialsByEmail, email)
 	if err != nil {
-		return nil, err
+		return Credentials{}, err
 	}
 
-	u := []api.User{}
+	return c, nil
+}
 
-	for rows.Next() {
-		var user api.User
-		if err = rows.StructScan(&user); err != nil {
-			return nil, errors.Wrap(err, "authRepo.FindByName.StructScan")
-		}
-		u = append(u, user)
+func toUser(u UserRow) api.User {
+	user := api.User{
+		Id:    u.Id,
+		Name:  u.Name,
+		Email: u.Email,
+		Phone: u.Phone,
+		Role:  u.Role,
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, errors.Wrap(err, "authRepo.FindByName.rows.Err")
+	if u.BirthDate.Valid {
+		user.BirthDate = u.BirthDate.Time.Format("2006-01-02")
+	}
+
+	if u.EmergencyContactName != "" || u.EmergencyContactPhone != "" {
+		user.EmergencyContact = &api.EmergencyContact{
+			Name:  u.EmergencyContactName,
+			Phone: u.EmergencyContactPhone,
+		}
 	}
 
-	return u, nil
+	return user
 }
+
+// containsPattern is the ILIKE pattern matching the values containing the text, wildcards in the text are literal.
+func containsPattern(text string) string {
+	return "%" + likeEscaper.Replace(text) + "%"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
@@ -1,18 +1,33 @@
 package users
 
 const (
-	findUsers = `SELECT *
-				  FROM users 
-				  `
-	findUserById = `SELECT *
+	userColumns = `id, user_name, user_role, email, phone, birth_date,
+				  emergency_contact_name, emergency_contact_phone, create_date, last_update_date`
+
+	findUserById = `SELECT ` + userColumns + `
 						From users
 						Where id = $1`
 
-	findUserByName = `SELECT *
-						From users
-						Where name = $1`
+	findUserByEmail = `SELECT ` + userColumns + `
+						FROM users
+						WHERE email <> '' AND lower(email) = lower($1)`
+
+	findUserIdByEmail = `SELECT id FROM users WHERE email <> '' AND lower(email) = lower($1)`
+
+	AddUserRow = `INSERT INTO users (user_name, email, phone, birth_date, emergency_contact_name, emergency_contact_phone)
+				  VALUES(:user_name, :email, :phone, :birth_date, :emergency_contact_name, :emergency_contact_phone)`
+
+	// The users sign in with their contact email
+	findCredentialsByEmail = `SELECT c.user_id, u.email, c.password_hash, u.user_role
+								FROM user_credentials c
+								JOIN users u ON u.id = c.user_id
+								WHERE u.email <> '' AND lower(u.email) = lower($1)`
+
+	hasCredentials = `SELECT EXISTS (SELECT 1 FROM user_credentials WHERE user_id = $1)`
+
+	addUserReturningId = `INSERT INTO users (user_name, email) VALUES($1, lower($2)) RETURNING id`
 
-	AddUserRow = `INSERT INTO users (user_name) VALUES(:user_name)`
+	setUserRole = `UPDATE users SET user_role = $2 WHERE id = $1`
 
-	UpdateUser = `UPDATE users SET user_name =:user_name WHERE id =:id`
+	addCredentials = `INSERT INTO user_credentials (user_id, password_hash) VALUES($1, $2)`
 )
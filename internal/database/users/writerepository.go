@@ -2,26 +2,68 @@ package users
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/utils"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// uniqueViolation is the SQLSTATE of a row violating a unique index.
+const uniqueViolation = "23505"
+
 type WriteRepository interface {
-	Add(ctx context.Context, user api.UpdateUser) error
-	Update(ctx context.Context, user api.User) (int64, error)
+	Add(ctx context.Context, user api.CreateUser) error
+	Update(ctx context.Context, user api.PatchUser) (int64, error)
+	AddWithCredentials(ctx context.Context, name string, credentials Credentials) (int, error)
+	SetRole(ctx context.Context, userId int, role string) (int64, error)
 }
 
 func NewWriteRepository(db *sqlx.DB) WriteRepository {
 	return &repository{db: db}
 }
 
-func (r *repository) Add(ctx context.Context, user api.UpdateUser) error {
+// Add inserts a new user into the repository.
+//
+// Contact emails are unique, case insensitive, so it returns a HTTP 409 error if another user has the email,
+// even one added by a concurrent request after the check.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: user api.CreateUser - Struct containing the user to be inserted, the birth date in the format YYYY-MM-DD.
+//
+// @return error - Error if there is an issue inserting the user into the database.
+func (r *repository) Add(ctx context.Context, user api.CreateUser) error {
+	if err := r.checkEmail(ctx, 0, user.Email); err != nil {
+		return err
+	}
+
+	birthDate, err := parseBirthDate(user.BirthDate)
+	if err != nil {
+		return err
+	}
+
 	ur := UserRow{
-		Name: user.Name,
+		Name:      user.Name,
+		Email:     user.Email,
+		Phone:     user.Phone,
+		BirthDate: birthDate,
 	}
 
-	_, err := r.db.NamedExecContext(ctx, AddUserRow, ur)
+	if user.EmergencyContact != nil {
+		ur.EmergencyContactName = user.EmergencyContact.Name
+		ur.EmergencyContactPhone = user.EmergencyContact.Phone
+	}
+
+	_, err = r.db.NamedExecContext(ctx, AddUserRow, ur)
+	if isUniqueViolation(err) {
+		return emailConflictError(user.Email)
+	}
 	if err != nil {
 		return err
 	}
@@ -29,23 +71,240 @@ func (r *repository) Add(ctx context.Context, user api.UpdateUser) error {
 	return nil
 }
 
-func (r *repository) Update(ctx context.Context, user api.User) (int64, error) {
-	u := UserRow{}
+// Update modifies the profile of an existing user, only the fields set in the patch are changed.
+//
+// It returns sql.ErrNoRows if the user does not exist and a HTTP 409 error if another user has the new email.
+// The contact email is also the sign in email of the users with credentials, so it returns a HTTP 400 error
+// when such a user clears it.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: user api.PatchUser - Struct containing the fields to be modified.
+//
+// @return int64 - Number of rows affected by the update operation.
+// @return error - Error if there is an issue updating the user in the database.
+func (r *repository) Update(ctx context.Context, user api.PatchUser) (int64, error) {
+	existing := UserRow{}
+	err := r.db.GetContext(ctx, &existing, findUserById, user.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	query := "UPDATE users SET "
+	args := map[string]interface{}{
+		"id": user.Id,
+	}
+	var updateFields []string
+
+	if user.Name != nil {
+		updateFields = append(updateFields, "user_name=:user_name")
+		args["user_name"] = *user.Name
+	}
+
+	if user.Email != nil {
+		if err = r.checkEmail(ctx, user.Id, *user.Email); err != nil {
+			return 0, err
+		}
+
+		if *user.Email == "" {
+			var signsIn bool
+			err = r.db.QueryRowContext(ctx, hasCredentials, user.Id).Scan(&signsIn)
+			if err != nil {
+				return 0, err
+			}
+
+			if signsIn {
+				return 0, utils.E(http.StatusBadRequest,
+					nil,
+					map[string]string{"message": "BadRequest"},
+					"The email of a user signing in cannot be cleared",
+					"Send the new email of the user instead.")
+			}
+		}
+
+		updateFields = append(updateFields, "email=:email")
+		args["email"] = *user.Email
+	}
+
+	if user.Phone != nil {
+		updateFields = append(updateFields, "phone=:phone")
+		args["phone"] = *user.Phone
+	}
+
+	if user.BirthDate != nil {
+		birthDate, err := parseBirthDate(*user.BirthDate)
+		if err != nil {
+			return 0, err
+		}
+
+		updateFields = append(updateFields, "birth_date=:birth_date")
+		args["birth_date"] = birthDate
+	}
+
+	if user.EmergencyContact != nil {
+		updateFields = append(updateFields, "emergency_contact_name=:emergency_contact_name",
+			"emergency_contact_phone=:emergency_contact_phone")
+		args["emergency_contact_name"] = user.EmergencyContact.Name
+		args["emergency_contact_phone"] = user.EmergencyContact.Phone
+	}
+
+	// Check if any fields are to be updated
+	if len(updateFields) == 0 {
+		return 0, nil // No fields to update
+	}
+
+	query += strings.Join(updateFields, ", ")
+	query += " WHERE id=:id"
+
+	result, err := r.db.NamedExecContext(ctx, query, args)
+	if isUniqueViolation(err) {
+		return 0, emailConflictError(*user.Email)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// AddWithCredentials inserts a new user able to sign in with an email and a password.
+//
+// Emails are unique, case insensitive, so it returns a HTTP 409 error if a user already signs in with the email
+// or has it as contact email. The credentials have no email of their own, the user signs in with the contact email.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: name string - Name of the user.
+// param: credentials Credentials - Email and password hash of the user, the user ID is ignored.
+//
+// @return int - ID of the new user.
+// @return error - Error if there is an issue inserting the user into the database.
+func (r *repository) AddWithCredentials(ctx context.Context, name string, credentials Credentials) (_ int, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var existing Credentials
+	err = tx.GetContext(ctx, &existing, findCredentialsByEmail, credentials.Email)
+	if err == nil {
+		err = credentialsConflictError(credentials.Email)
+		return 0, err
+	}
 
-	row := r.db.QueryRowContext(ctx, findUserById, user.Id)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
 
-	err := row.Scan(&u.Id, &u.Name, &u.CreateDate, &u.LastUpdateDate)
+	var existingId int
+	err = tx.QueryRowContext(ctx, findUserIdByEmail, credentials.Email).Scan(&existingId)
+	if err == nil {
+		err = emailConflictError(credentials.Email)
+		return 0, err
+	}
 
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	// A concurrent request may take the email after the checks, the unique indexes refuse it
+	var userId int
+	err = tx.QueryRowContext(ctx, addUserReturningId, name, credentials.Email).Scan(&userId)
+	if isUniqueViolation(err) {
+		err = emailConflictError(credentials.Email)
+		return 0, err
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, addCredentials, userId, credentials.PasswordHash)
 	if err != nil {
 		return 0, err
 	}
 
-	u.Name = user.Name
+	return userId, nil
+}
 
-	rl, err := r.db.NamedExecContext(ctx, UpdateUser, u)
+// SetRole changes the role of a user.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user.
+// param: role string - New role of the user.
+//
+// @return int64 - Number of rows affected, 0 if the user does not exist.
+// @return error - Error if there is an issue updating the user in the database.
+func (r *repository) SetRole(ctx context.Context, userId int, role string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, setUserRole, userId, role)
 	if err != nil {
 		return 0, err
 	}
 
-	return rl.RowsAffected()
+	return result.RowsAffected()
+}
+
+// checkEmail returns a HTTP 409 error if a user other than userId has the contact email, which is also
+// the sign in email of the users with credentials.
+func (r *repository) checkEmail(ctx context.Context, userId int, email string) error {
+	if email == "" {
+		return nil
+	}
+
+	var existingId int
+	err := r.db.QueryRowContext(ctx, findUserIdByEmail, email).Scan(&existingId)
+	if err == nil && existingId != userId {
+		return emailConflictError(email)
+	}
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return nil
+}
+
+// parseBirthDate converts a birth date in the format YYYY-MM-DD, an empty date is not set.
+func parseBirthDate(date string) (sql.NullTime, error) {
+	if date == "" {
+		return sql.NullTime{}, nil
+	}
+
+	birthDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return sql.NullTime{}, utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Birth date should be in the format YYYY-MM-DD",
+			"Read our documentation for more details")
+	}
+
+	return sql.NullTime{Time: birthDate, Valid: true}, nil
+}
+
+// isUniqueViolation reports whether err is the violation of a unique index, e.g. an email taken
+// by a concurrent request.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+func credentialsConflictError(email string) error {
+	return utils.E(http.StatusConflict,
+		nil,
+		map[string]string{"message": "Conflict Status"},
+		fmt.Sprintf("A user already signs in with %s", email),
+		"Please sign in or use a different email.")
+}
+
+func emailConflictError(email string) error {
+	return utils.E(http.StatusConflict,
+		nil,
+		map[string]string{"message": "Conflict Status"},
+		fmt.Sprintf("A user with email %s already exists", email),
+		"Please use a different email.")
 }
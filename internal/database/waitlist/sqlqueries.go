@@ -0,0 +1,43 @@
+package waitlist
+
+const (
+	countUserEntries = `SELECT COUNT(*)
+						FROM waitlist
+						WHERE user_id = $1 AND class_id = $2`
+
+	addToWaitlist = `INSERT INTO waitlist (user_id, class_id, join_date, membership_id)
+					VALUES ($1, $2, CURRENT_TIMESTAMP, $3)`
+
+	queuePosition = `SELECT COUNT(*)
+						FROM waitlist
+						WHERE class_id = $2
+						AND id <= (SELECT id FROM waitlist WHERE user_id = $1 AND class_id = $2)`
+
+	removeFromWaitlist = `DELETE FROM waitlist
+							WHERE user_id = $1 AND class_id = $2
+							RETURNING membership_id`
+
+	classSeats = `SELECT num_registrations, class_capacity
+					FROM classes
+					WHERE id = $1`
+
+	promoteWaitlisted = `WITH promoted AS (
+							DELETE FROM waitlist
+							WHERE id IN (
+								SELECT id
+								FROM waitlist
+								WHERE class_id = $1
+								ORDER BY id
+								LIMIT $2
+								FOR UPDATE
+							)
+							RETURNING user_id, class_id, membership_id
+						)
+						INSERT INTO booking (user_id, class_id, reserved_date, membership_id)
+						SELECT user_id, class_id, CURRENT_TIMESTAMP, membership_id
+						FROM promoted`
+
+	addRegistrations = `UPDATE classes
+						SET num_registrations = num_registrations + $1
+						WHERE id = $2`
+)
@@ -0,0 +1,137 @@
+package waitlist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Flgado/fitnessStudioApp/internal/database/memberships"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+// The waitlist is always changed together with the booking and classes tables, so
+// these helpers run inside the caller's transaction. The caller must already hold
+// the class row lock (SELECT ... FOR UPDATE) before calling any of them.
+
+// Join adds the user to the end of the class waitlist.
+//
+// The entry keeps the membership holding the credit of the user, the credit moves to the booking
+// on promotion. It returns the queue position of the user (starting at 1), or a HTTP 409 error
+// if the user is already waiting for the class.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: tx *sqlx.Tx - Transaction holding the class row lock.
+// param: userId int - ID of the user joining the waitlist.
+// param: classId int - ID of the full class.
+// param: membershipId int - ID of the membership holding the credit.
+//
+// @return int - Queue position of the user.
+// @return error - Error if the user cannot join the waitlist.
+func Join(ctx context.Context, tx *sqlx.Tx, userId, classId, membershipId int) (int, error) {
+	var entries int
+	err := tx.QueryRowContext(ctx, countUserEntries, userId, classId).Scan(&entries)
+	if err != nil {
+		return 0, err
+	}
+
+	if entries > 0 {
+		return 0, utils.E(http.StatusConflict,
+			nil,
+			map[string]string{"message": "Conflict Status"},
+			fmt.Sprintf("User with Id: %d is already on the waitlist of class with id %d",
+				userId,
+				classId),
+			"Validate user reserved classes")
+	}
+
+	_, err = tx.ExecContext(ctx, addToWaitlist, userId, classId, membershipId)
+	if err != nil {
+		return 0, err
+	}
+
+	var position int
+	err = tx.QueryRowContext(ctx, queuePosition, userId, classId).Scan(&position)
+	if err != nil {
+		return 0, err
+	}
+
+	return position, nil
+}
+
+// Leave removes the user from the class waitlist and refunds the credit held by the entry.
+//
+// It returns true if the user was waiting for the class, false otherwise.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: tx *sqlx.Tx - Transaction holding the class row lock.
+// param: userId int - ID of the user leaving the waitlist.
+// param: classId int - ID of the class.
+//
+// @return bool - True if an entry was removed.
+// @return error - Error if there is an issue removing the entry.
+func Leave(ctx context.Context, tx *sqlx.Tx, userId, classId int) (bool, error) {
+	var membershipId *int
+	err := tx.QueryRowContext(ctx, removeFromWaitlist, userId, classId).Scan(&membershipId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = memberships.Refund(ctx, tx, membershipId)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Promote moves the first users in line into the free seats of the class.
+//
+// The number of free seats is read from the locked class row, so it covers both
+// cancelled bookings and capacity increases. Promoted users are removed from the
+// waitlist, booked and counted in num_registrations.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: tx *sqlx.Tx - Transaction holding the class row lock.
+// param: classId int - ID of the class with free seats.
+//
+// @return int64 - Number of promoted users.
+// @return error - Error if there is an issue promoting the users.
+func Promote(ctx context.Context, tx *sqlx.Tx, classId int) (int64, error) {
+	var numRegistrations, classCapacity int
+	err := tx.QueryRowContext(ctx, classSeats, classId).Scan(&numRegistrations, &classCapacity)
+	if err != nil {
+		return 0, err
+	}
+
+	freeSeats := classCapacity - numRegistrations
+	if freeSeats <= 0 {
+		return 0, nil
+	}
+
+	result, err := tx.ExecContext(ctx, promoteWaitlisted, classId, freeSeats)
+	if err != nil {
+		return 0, err
+	}
+
+	promoted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if promoted == 0 {
+		return 0, nil
+	}
+
+	_, err = tx.ExecContext(ctx, addRegistrations, promoted, classId)
+	if err != nil {
+		return 0, err
+	}
+
+	return promoted, nil
+}
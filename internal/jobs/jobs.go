@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every runs job every interval until ctx is done.
+//
+// A failed run is logged and the job runs again at the next tick.
+//
+// param: ctx context.Context - Context stopping the job when done.
+// param: interval time.Duration - Time between two runs.
+// param: name string - Name of the job used in the logs.
+// param: job func(ctx context.Context) error - Job to run.
+func Every(ctx context.Context, interval time.Duration, name string, job func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				log.Printf("Job %s failed: %v", name, err)
+			}
+		}
+	}
+}
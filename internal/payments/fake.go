@@ -0,0 +1,88 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+)
+
+const fakeRefPrefix = "fake_pi_"
+
+// FakeProvider is an in-process provider accepting every payment, so the studio runs and is tested offline.
+//
+// It is deterministic: the reference of a payment is derived from its ledger ID, and the callbacks
+// are signed with HMAC-SHA256 of the webhook secret, hex encoded.
+type FakeProvider struct {
+	secret []byte
+}
+
+func NewFakeProvider(webhookSecret string) *FakeProvider {
+	return &FakeProvider{secret: []byte(webhookSecret)}
+}
+
+func (p *FakeProvider) Name() string {
+	return ProviderFake
+}
+
+func (p *FakeProvider) CreateIntent(ctx context.Context, payment api.Payment) (Intent, error) {
+	if payment.Id <= 0 {
+		return Intent{}, errors.New("fake provider: the payment has no ID")
+	}
+
+	ref := fmt.Sprintf("%s%d", fakeRefPrefix, payment.Id)
+	return Intent{
+		Ref:          ref,
+		ClientSecret: ref + "_secret_" + p.Sign([]byte(ref))[:16],
+	}, nil
+}
+
+func (p *FakeProvider) Capture(ctx context.Context, ref string) error {
+	return checkFakeRef(ref)
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, ref string, amount int) error {
+	return checkFakeRef(ref)
+}
+
+func (p *FakeProvider) ParseWebhook(payload []byte, signature string) (Event, error) {
+	if !hmac.Equal([]byte(p.Sign(payload)), []byte(strings.ToLower(signature))) {
+		return Event{}, ErrInvalidSignature
+	}
+
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Event{}, fmt.Errorf("fake provider: invalid event: %w", err)
+	}
+
+	return event, nil
+}
+
+// Sign returns the signature of a callback payload.
+func (p *FakeProvider) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignedEvent encodes an event as the provider would send it in a callback.
+//
+// @return []byte - Payload of the callback.
+// @return string - Signature of the payload.
+func (p *FakeProvider) SignedEvent(event Event) ([]byte, string) {
+	payload, _ := json.Marshal(event)
+	return payload, p.Sign(payload)
+}
+
+func checkFakeRef(ref string) error {
+	if !strings.HasPrefix(ref, fakeRefPrefix) {
+		return fmt.Errorf("fake provider: unknown payment %q", ref)
+	}
+	return nil
+}
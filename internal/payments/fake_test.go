@@ -0,0 +1,60 @@
+//go:build unittests
+// +build unittests
+
+package payments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Flgado/fitnessStudioApp/config"
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider(config.Payments{WebhookSecret: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, ProviderFake, provider.Name())
+
+	_, err = NewProvider(config.Payments{Provider: "unknown", WebhookSecret: "secret"})
+	assert.Error(t, err)
+
+	_, err = NewProvider(config.Payments{Provider: ProviderFake})
+	assert.Error(t, err)
+}
+
+func TestFakeProvider_Intent(t *testing.T) {
+	provider := NewFakeProvider("secret")
+
+	intent, err := provider.CreateIntent(context.Background(), api.Payment{Id: 42, Amount: 1500})
+	assert.NoError(t, err)
+	assert.Equal(t, "fake_pi_42", intent.Ref)
+
+	again, err := provider.CreateIntent(context.Background(), api.Payment{Id: 42, Amount: 1500})
+	assert.NoError(t, err)
+	assert.Equal(t, intent, again)
+
+	assert.NoError(t, provider.Capture(context.Background(), intent.Ref))
+	assert.NoError(t, provider.Refund(context.Background(), intent.Ref, 1500))
+	assert.Error(t, provider.Capture(context.Background(), "pi_unknown"))
+
+	_, err = provider.CreateIntent(context.Background(), api.Payment{})
+	assert.Error(t, err)
+}
+
+func TestFakeProvider_ParseWebhook(t *testing.T) {
+	provider := NewFakeProvider("secret")
+	event := Event{Type: EventPaymentSucceeded, Ref: "fake_pi_42"}
+	payload, signature := provider.SignedEvent(event)
+
+	parsed, err := provider.ParseWebhook(payload, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, event, parsed)
+
+	_, err = provider.ParseWebhook(payload, NewFakeProvider("other").Sign(payload))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = provider.ParseWebhook([]byte("not json"), provider.Sign([]byte("not json")))
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Flgado/fitnessStudioApp/config"
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+)
+
+// Events reported by the provider callbacks.
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+)
+
+// ProviderFake is the name of the in-process provider.
+const ProviderFake = "fake"
+
+// ErrInvalidSignature is returned when a callback is not signed by the provider.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// PaymentProvider is the payment service charging the users.
+//
+// The studio keeps its own ledger of the payments, the provider only knows them by the reference
+// it returns when the payment intent is created. The outcome of a payment is reported either by
+// Capture or later by a signed callback of the provider.
+type PaymentProvider interface {
+	// Name identifies the provider in the ledger.
+	Name() string
+	// CreateIntent registers the payment with the provider.
+	CreateIntent(ctx context.Context, payment api.Payment) (Intent, error)
+	// Capture collects the money of an intent.
+	Capture(ctx context.Context, ref string) error
+	// Refund gives the amount of a captured intent back to the user.
+	Refund(ctx context.Context, ref string, amount int) error
+	// ParseWebhook verifies the signature of a callback and decodes its event.
+	ParseWebhook(payload []byte, signature string) (Event, error)
+}
+
+// Intent is a payment registered with the provider.
+type Intent struct {
+	// Ref is the reference of the payment at the provider
+	Ref string
+	// ClientSecret lets the client complete the payment with the provider
+	ClientSecret string
+}
+
+// Event is a callback of the provider about a payment.
+type Event struct {
+	Type string `json:"type"`
+	Ref  string `json:"ref"`
+}
+
+// NewProvider builds the payment provider of the configuration.
+//
+// param: cfg config.Payments - Payments configuration.
+//
+// @return PaymentProvider - Payment provider.
+// @return error - Error if the provider is unknown or has no webhook secret.
+func NewProvider(cfg config.Payments) (PaymentProvider, error) {
+	if cfg.WebhookSecret == "" {
+		return nil, errors.New("payments: the webhook secret is required")
+	}
+
+	switch cfg.Provider {
+	case "", ProviderFake:
+		return NewFakeProvider(cfg.WebhookSecret), nil
+	default:
+		return nil, fmt.Errorf("payments: unknown provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,178 @@
+package policies
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Flgado/fitnessStudioApp/config"
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/utils"
+)
+
+// Rules reported in the "rule" message of the actions blocked by a policy.
+const (
+	RuleBookingWindow    = "booking_window"
+	RuleBookingCutoff    = "booking_cutoff"
+	RuleLateCancellation = "late_cancellation"
+)
+
+// Late cancellation modes.
+const (
+	LateCancelAllow  = "allow"
+	LateCancelReject = "reject"
+)
+
+// defaultPolicyName names the policy of the classes without a class type override in the errors.
+const defaultPolicyName = "default"
+
+// BookingPolicy holds the booking rules applied to a class.
+type BookingPolicy struct {
+	// Name is the class type of the override, or "default"
+	Name             string
+	OpensBefore      time.Duration
+	ClosesBefore     time.Duration
+	LateCancelWithin time.Duration
+	LateCancel       string
+}
+
+// Engine decides whether a class can be booked or cancelled at a given time.
+type Engine struct {
+	defaults   BookingPolicy
+	classTypes map[string]BookingPolicy
+}
+
+// NewEngine builds the policy engine from the studio configuration.
+//
+// The class type overrides are merged with the default policy, a field not set keeps the default value
+// and a duration set to zero removes the limit.
+// Class types are case insensitive.
+//
+// param: cfg config.Policies - Booking policies of the configuration.
+//
+// @return *Engine - Policy engine.
+// @return error - Error if a policy has a negative duration or an unknown late cancellation mode.
+func NewEngine(cfg config.Policies) (*Engine, error) {
+	defaults := BookingPolicy{
+		Name:             defaultPolicyName,
+		OpensBefore:      cfg.Booking.OpensBefore,
+		ClosesBefore:     cfg.Booking.ClosesBefore,
+		LateCancelWithin: cfg.Booking.LateCancelWithin,
+		LateCancel:       cfg.Booking.LateCancel,
+	}
+	if defaults.LateCancel == "" {
+		defaults.LateCancel = LateCancelAllow
+	}
+	if err := validate(defaults); err != nil {
+		return nil, err
+	}
+
+	classTypes := make(map[string]BookingPolicy, len(cfg.ClassTypes))
+	for classType, override := range cfg.ClassTypes {
+		policy := defaults
+		policy.Name = strings.ToLower(classType)
+		if override.OpensBefore != nil {
+			policy.OpensBefore = *override.OpensBefore
+		}
+		if override.ClosesBefore != nil {
+			policy.ClosesBefore = *override.ClosesBefore
+		}
+		if override.LateCancelWithin != nil {
+			policy.LateCancelWithin = *override.LateCancelWithin
+		}
+		if override.LateCancel != "" {
+			policy.LateCancel = override.LateCancel
+		}
+		if err := validate(policy); err != nil {
+			return nil, err
+		}
+		classTypes[policy.Name] = policy
+	}
+
+	return &Engine{defaults: defaults, classTypes: classTypes}, nil
+}
+
+// For returns the policy of a class type, the default policy when the type has no override.
+func (e *Engine) For(classType string) BookingPolicy {
+	if policy, ok := e.classTypes[strings.ToLower(classType)]; ok {
+		return policy
+	}
+	return e.defaults
+}
+
+// CheckBooking checks that a class can be booked at a given time.
+//
+// It returns a HTTP 422 error naming the rule blocking the booking
+// when the bookings of the class are not open yet or already closed.
+//
+// param: class api.Class - Class to book.
+// param: now time.Time - Time of the booking.
+//
+// @return error - Error if the policy of the class does not allow the booking.
+func (e *Engine) CheckBooking(class api.Class, now time.Time) error {
+	policy := e.For(class.Type)
+
+	if policy.OpensBefore > 0 {
+		opensAt := class.Date.Add(-policy.OpensBefore)
+		if now.Before(opensAt) {
+			return violation(policy, RuleBookingWindow, "Booking Not Open",
+				fmt.Sprintf("Bookings for this class open %s before it starts, at %s.", policy.OpensBefore, opensAt.Format(time.RFC3339)),
+				"Please book the class once the bookings are open.")
+		}
+	}
+
+	closesAt := class.Date.Add(-policy.ClosesBefore)
+	if !now.Before(closesAt) {
+		return violation(policy, RuleBookingCutoff, "Booking Closed",
+			fmt.Sprintf("Bookings for this class closed %s before it started, at %s.", policy.ClosesBefore, closesAt.Format(time.RFC3339)),
+			"Please select a later class.")
+	}
+
+	return nil
+}
+
+// CheckCancel checks whether the booking of a class can be cancelled at a given time.
+//
+// A cancellation within the late cancellation window of the policy is late. Late cancellations
+// are accepted and reported, unless the policy rejects them with a HTTP 422 error.
+//
+// param: class api.Class - Class of the booking.
+// param: now time.Time - Time of the cancellation.
+//
+// @return bool - True if the cancellation is late.
+// @return error - Error if the policy of the class does not allow the cancellation.
+func (e *Engine) CheckCancel(class api.Class, now time.Time) (bool, error) {
+	policy := e.For(class.Type)
+
+	if policy.LateCancelWithin <= 0 || now.Before(class.Date.Add(-policy.LateCancelWithin)) {
+		return false, nil
+	}
+
+	if policy.LateCancel == LateCancelReject {
+		return true, violation(policy, RuleLateCancellation, "Late Cancellation Not Allowed",
+			fmt.Sprintf("Bookings cannot be cancelled within %s of the start of the class.", policy.LateCancelWithin),
+			"Please contact the studio to release your spot.")
+	}
+
+	return true, nil
+}
+
+func validate(policy BookingPolicy) error {
+	if policy.OpensBefore < 0 || policy.ClosesBefore < 0 || policy.LateCancelWithin < 0 {
+		return fmt.Errorf("policy %s: durations cannot be negative", policy.Name)
+	}
+	if policy.LateCancel != LateCancelAllow && policy.LateCancel != LateCancelReject {
+		return fmt.Errorf("policy %s: unknown late cancellation mode %q, use %q or %q",
+			policy.Name, policy.LateCancel, LateCancelAllow, LateCancelReject)
+	}
+	return nil
+}
+
+func violation(policy BookingPolicy, rule string, message string, details string, suggestions string) error {
+	return utils.E(http.StatusUnprocessableEntity,
+		nil,
+		map[string]string{"message": message, "rule": rule, "policy": policy.Name},
+		details,
+		suggestions)
+}
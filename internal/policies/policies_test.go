@@ -0,0 +1,142 @@
+//go:build unittests
+// +build unittests
+
+package policies
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Flgado/fitnessStudioApp/config"
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEngine(t *testing.T) {
+	engine, err := NewEngine(config.Policies{
+		Booking: config.BookingPolicy{
+			OpensBefore:      14 * 24 * time.Hour,
+			ClosesBefore:     15 * time.Minute,
+			LateCancelWithin: 12 * time.Hour,
+		},
+		ClassTypes: map[string]config.PolicyOverride{
+			"spinning": {ClosesBefore: duration(time.Hour), LateCancel: LateCancelReject},
+			"open-gym": {OpensBefore: duration(0), ClosesBefore: duration(0)},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, BookingPolicy{
+		Name:             "default",
+		OpensBefore:      14 * 24 * time.Hour,
+		ClosesBefore:     15 * time.Minute,
+		LateCancelWithin: 12 * time.Hour,
+		LateCancel:       LateCancelAllow,
+	}, engine.For("yoga"))
+	assert.Equal(t, BookingPolicy{
+		Name:             "spinning",
+		OpensBefore:      14 * 24 * time.Hour,
+		ClosesBefore:     time.Hour,
+		LateCancelWithin: 12 * time.Hour,
+		LateCancel:       LateCancelReject,
+	}, engine.For("Spinning"))
+	// an override set to zero removes the limit instead of keeping the default
+	assert.Equal(t, BookingPolicy{
+		Name:             "open-gym",
+		LateCancelWithin: 12 * time.Hour,
+		LateCancel:       LateCancelAllow,
+	}, engine.For("open-gym"))
+}
+
+func TestNewEngine_InvalidPolicies(t *testing.T) {
+	testCases := []struct {
+		testName string
+		policies config.Policies
+	}{
+		{"unknown late cancellation mode", config.Policies{Booking: config.BookingPolicy{LateCancel: "refund"}}},
+		{"negative cutoff", config.Policies{Booking: config.BookingPolicy{ClosesBefore: -time.Minute}}},
+		{"unknown class type mode", config.Policies{ClassTypes: map[string]config.PolicyOverride{"yoga": {LateCancel: "never"}}}},
+		{"negative class type window", config.Policies{ClassTypes: map[string]config.PolicyOverride{"yoga": {OpensBefore: duration(-time.Hour)}}}},
+	}
+
+	for _, tc := range testCases {
+		_, err := NewEngine(tc.policies)
+		assert.Error(t, err, tc.testName)
+	}
+}
+
+func TestCheckBooking(t *testing.T) {
+	engine, err := NewEngine(config.Policies{
+		Booking: config.BookingPolicy{OpensBefore: 7 * 24 * time.Hour, ClosesBefore: 15 * time.Minute},
+	})
+	assert.NoError(t, err)
+
+	start := time.Date(2024, time.March, 17, 12, 0, 0, 0, time.UTC)
+	class := api.Class{Name: "Test", Date: start, Duration: 60}
+
+	testCases := []struct {
+		testName string
+		now      time.Time
+		rule     string
+	}{
+		{"before the window opens", start.Add(-8 * 24 * time.Hour), RuleBookingWindow},
+		{"window open", start.Add(-7 * 24 * time.Hour), ""},
+		{"just before the cutoff", start.Add(-16 * time.Minute), ""},
+		{"at the cutoff", start.Add(-15 * time.Minute), RuleBookingCutoff},
+		{"class started", start.Add(time.Minute), RuleBookingCutoff},
+	}
+
+	for _, tc := range testCases {
+		err := engine.CheckBooking(class, tc.now)
+
+		if tc.rule == "" {
+			assert.NoError(t, err, tc.testName)
+			continue
+		}
+
+		e, ok := err.(utils.Error)
+		assert.True(t, ok, tc.testName)
+		assert.Equal(t, tc.rule, e.Message()["rule"], tc.testName)
+	}
+}
+
+func TestCheckCancel(t *testing.T) {
+	engine, err := NewEngine(config.Policies{
+		Booking: config.BookingPolicy{LateCancelWithin: 12 * time.Hour},
+		ClassTypes: map[string]config.PolicyOverride{
+			"spinning": {LateCancelWithin: duration(24 * time.Hour), LateCancel: LateCancelReject},
+		},
+	})
+	assert.NoError(t, err)
+
+	start := time.Date(2024, time.March, 17, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		testName  string
+		classType string
+		now       time.Time
+		late      bool
+		hasError  bool
+	}{
+		{"in time", "", start.Add(-13 * time.Hour), false, false},
+		{"late and allowed", "", start.Add(-2 * time.Hour), true, false},
+		{"in time for the default policy but late for spinning", "spinning", start.Add(-13 * time.Hour), true, true},
+		{"in time for spinning", "spinning", start.Add(-25 * time.Hour), false, false},
+	}
+
+	for _, tc := range testCases {
+		late, err := engine.CheckCancel(api.Class{Type: tc.classType, Date: start}, tc.now)
+
+		assert.Equal(t, tc.late, late, tc.testName)
+		if tc.hasError {
+			assert.Error(t, err, tc.testName)
+			continue
+		}
+		assert.NoError(t, err, tc.testName)
+	}
+}
+
+func duration(d time.Duration) *time.Duration {
+	return &d
+}
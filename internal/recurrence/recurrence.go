@@ -0,0 +1,244 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily  Frequency = "DAILY"
+	Weekly Frequency = "WEEKLY"
+)
+
+const (
+	// MaxOccurrences bounds the expansion of a rule, so a single request cannot
+	// schedule an unbounded number of classes.
+	MaxOccurrences = 1000
+	maxInterval    = 99
+)
+
+var (
+	ErrUnbounded          = errors.New("recurrence has no end, set COUNT, UNTIL or an end date")
+	ErrTooManyOccurrences = fmt.Errorf("recurrence expands to more than %d occurrences", MaxOccurrences)
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule is the subset of the iCalendar RRULE (RFC 5545) used to schedule classes:
+// FREQ (DAILY or WEEKLY), INTERVAL, BYDAY, COUNT and UNTIL.
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []time.Weekday
+	Count    int
+	Until    time.Time
+}
+
+// Parse parses a RRULE such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10".
+//
+// The "RRULE:" prefix is optional. UNTIL accepts the date (20240331) and
+// date-time (20240331T235959Z) forms and is inclusive.
+//
+// param: s string - Rule to parse.
+//
+// @return Rule - Parsed rule.
+// @return error - Error if the rule is malformed or uses unsupported parts.
+func Parse(s string) (Rule, error) {
+	rule := Rule{Interval: 1}
+
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return Rule{}, errors.New("empty recurrence rule")
+	}
+
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return Rule{}, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(value))
+			if rule.Freq != Daily && rule.Freq != Weekly {
+				return Rule{}, fmt.Errorf("unsupported FREQ %q, use DAILY or WEEKLY", value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > maxInterval {
+				return Rule{}, fmt.Errorf("INTERVAL should be a number between 1 and %d", maxInterval)
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 || count > MaxOccurrences {
+				return Rule{}, fmt.Errorf("COUNT should be a number between 1 and %d", MaxOccurrences)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return Rule{}, err
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return Rule{}, fmt.Errorf("invalid BYDAY value %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "WKST":
+			if strings.ToUpper(value) != "MO" {
+				return Rule{}, errors.New("only WKST=MO is supported")
+			}
+		default:
+			return Rule{}, fmt.Errorf("unsupported recurrence rule part %q", name)
+		}
+	}
+
+	if rule.Freq == "" {
+		return Rule{}, errors.New("FREQ is required")
+	}
+
+	if rule.Count != 0 && !rule.Until.IsZero() {
+		return Rule{}, errors.New("COUNT and UNTIL cannot be used together")
+	}
+
+	return rule, nil
+}
+
+// Expand returns the occurrences of the rule starting at dtStart.
+//
+// Every occurrence keeps the time of day of dtStart. The expansion stops at the UNTIL
+// of the rule or at until, whichever comes first (a zero until is ignored), and after
+// COUNT occurrences. As in RFC 5545, COUNT includes the occurrences removed by exDates,
+// which exclude every occurrence falling on the same calendar day.
+// When BYDAY is set, dtStart is only an occurrence if it matches the rule.
+//
+// param: dtStart time.Time - Start of the first occurrence.
+// param: until time.Time - Optional inclusive end of the expansion.
+// param: exDates []time.Time - Days without occurrences.
+//
+// @return []time.Time - Start of each occurrence, in chronological order.
+// @return error - ErrUnbounded or ErrTooManyOccurrences.
+func (r Rule) Expand(dtStart time.Time, until time.Time, exDates []time.Time) ([]time.Time, error) {
+	end := r.Until
+	if !until.IsZero() && (end.IsZero() || until.Before(end)) {
+		end = until
+	}
+
+	if end.IsZero() && r.Count == 0 {
+		return nil, ErrUnbounded
+	}
+
+	excluded := make(map[string]struct{}, len(exDates))
+	for _, exDate := range exDates {
+		excluded[exDate.Format(time.DateOnly)] = struct{}{}
+	}
+
+	var occurrences []time.Time
+	generated := 0
+	for day := dtStart; end.IsZero() || !day.After(end); day = day.AddDate(0, 0, 1) {
+		if r.Count != 0 && generated == r.Count {
+			break
+		}
+
+		if !r.matches(dtStart, day) {
+			continue
+		}
+		generated++
+
+		if _, skip := excluded[day.Format(time.DateOnly)]; skip {
+			continue
+		}
+
+		if len(occurrences) == MaxOccurrences {
+			return nil, ErrTooManyOccurrences
+		}
+		occurrences = append(occurrences, day)
+	}
+
+	return occurrences, nil
+}
+
+// matches reports whether day is an occurrence of a rule starting at dtStart.
+func (r Rule) matches(dtStart time.Time, day time.Time) bool {
+	interval := r.Interval
+	if interval == 0 {
+		interval = 1
+	}
+
+	byDay := r.ByDay
+	if r.Freq == Weekly && len(byDay) == 0 {
+		byDay = []time.Weekday{dtStart.Weekday()}
+	}
+
+	if len(byDay) != 0 && !containsWeekday(byDay, day.Weekday()) {
+		return false
+	}
+
+	if r.Freq == Weekly {
+		// weeks start on Monday (WKST=MO)
+		return (daysBetween(startOfWeek(dtStart), startOfWeek(day))/7)%interval == 0
+	}
+
+	return daysBetween(dtStart, day)%interval == 0
+}
+
+func containsWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, w := range weekdays {
+		if w == weekday {
+			return true
+		}
+	}
+
+	return false
+}
+
+// daysBetween returns the number of calendar days from a to b, ignoring the time of day.
+func daysBetween(a time.Time, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	from := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	to := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// startOfWeek returns the Monday of the week of t.
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		until, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+
+		if layout == "20060102" {
+			// a date UNTIL includes the whole day
+			until = until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+
+		return until, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q, use YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+}
@@ -0,0 +1,86 @@
+//go:build unittests
+// +build unittests
+
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		testName string
+		rrule    string
+		expected Rule
+		hasError bool
+	}{
+		{"weekly on mondays and wednesdays", "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10", Rule{Freq: Weekly, Interval: 1, ByDay: []time.Weekday{time.Monday, time.Wednesday}, Count: 10}, false},
+		{"with prefix and until date", "RRULE:FREQ=DAILY;INTERVAL=2;UNTIL=20240331", Rule{Freq: Daily, Interval: 2, Until: time.Date(2024, time.March, 31, 23, 59, 59, 999999999, time.UTC)}, false},
+		{"until date-time", "FREQ=WEEKLY;UNTIL=20240331T180000Z", Rule{Freq: Weekly, Interval: 1, Until: time.Date(2024, time.March, 31, 18, 0, 0, 0, time.UTC)}, false},
+		{"missing frequency", "BYDAY=MO", Rule{}, true},
+		{"unsupported frequency", "FREQ=MONTHLY;COUNT=2", Rule{}, true},
+		{"invalid day", "FREQ=WEEKLY;BYDAY=XX", Rule{}, true},
+		{"count and until", "FREQ=DAILY;COUNT=2;UNTIL=20240331", Rule{}, true},
+		{"unsupported part", "FREQ=DAILY;BYHOUR=10", Rule{}, true},
+		{"empty", "", Rule{}, true},
+	}
+
+	for _, tc := range testCases {
+		rule, err := Parse(tc.rrule)
+
+		if tc.hasError {
+			assert.Error(t, err, tc.testName)
+			continue
+		}
+
+		assert.NoError(t, err, tc.testName)
+		assert.Equal(t, tc.expected, rule, tc.testName)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	// Monday
+	start := time.Date(2024, time.March, 4, 18, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time {
+		return time.Date(2024, time.March, d, 18, 0, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		testName string
+		rrule    string
+		until    time.Time
+		exDates  []time.Time
+		expected []time.Time
+	}{
+		{"mondays and wednesdays", "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4", time.Time{}, nil, []time.Time{day(4), day(6), day(11), day(13)}},
+		{"every other week until", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;UNTIL=20240325", time.Time{}, nil, []time.Time{day(4), day(8), day(18), day(22)}},
+		{"weekly defaults to the start weekday", "FREQ=WEEKLY;COUNT=3", time.Time{}, nil, []time.Time{day(4), day(11), day(18)}},
+		{"daily bounded by the end date", "FREQ=DAILY;INTERVAL=3", day(12), nil, []time.Time{day(4), day(7), day(10)}},
+		{"end date before until", "FREQ=DAILY;UNTIL=20240331", day(5), nil, []time.Time{day(4), day(5)}},
+		{"excluded days count", "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4", time.Time{}, []time.Time{time.Date(2024, time.March, 6, 0, 0, 0, 0, time.UTC)}, []time.Time{day(4), day(11), day(13)}},
+		{"start not matching the rule", "FREQ=WEEKLY;BYDAY=TU;COUNT=2", time.Time{}, nil, []time.Time{day(5), day(12)}},
+	}
+
+	for _, tc := range testCases {
+		rule, err := Parse(tc.rrule)
+		assert.NoError(t, err, tc.testName)
+
+		occurrences, err := rule.Expand(start, tc.until, tc.exDates)
+
+		assert.NoError(t, err, tc.testName)
+		assert.Equal(t, tc.expected, occurrences, tc.testName)
+	}
+}
+
+func TestExpand_Limits(t *testing.T) {
+	start := time.Date(2024, time.March, 4, 18, 0, 0, 0, time.UTC)
+
+	_, err := Rule{Freq: Daily, Interval: 1}.Expand(start, time.Time{}, nil)
+	assert.Equal(t, ErrUnbounded, err)
+
+	_, err = Rule{Freq: Daily, Interval: 1}.Expand(start, start.AddDate(5, 0, 0), nil)
+	assert.Equal(t, ErrTooManyOccurrences, err)
+}
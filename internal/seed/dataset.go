@@ -0,0 +1,154 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+)
+
+// classType is a kind of class of the studio schedule.
+type classType struct {
+	Name     string
+	Type     string
+	Duration int
+	Capacity int
+}
+
+var classTypes = []classType{
+	{Name: "Crossfit", Type: "crossfit", Duration: 60, Capacity: 20},
+	{Name: "Spinning", Type: "spinning", Duration: 45, Capacity: 15},
+	{Name: "Yoga", Type: "yoga", Duration: 60, Capacity: 20},
+	{Name: "Pilates", Type: "pilates", Duration: 50, Capacity: 12},
+	{Name: "HIIT", Type: "hiit", Duration: 30, Capacity: 20},
+	{Name: "Triathlon", Type: "triathlon", Duration: 90, Capacity: 10},
+}
+
+// weekdaySlots and saturdaySlots are the start times of the classes, far enough apart for the longest class.
+// There are no classes on Sundays.
+var (
+	weekdaySlots  = []time.Duration{7 * time.Hour, 9 * time.Hour, 12*time.Hour + 30*time.Minute, 17*time.Hour + 30*time.Minute, 19*time.Hour + 30*time.Minute}
+	saturdaySlots = []time.Duration{9 * time.Hour, 11 * time.Hour}
+)
+
+var rooms = []api.CreateRoom{
+	{Name: "Studio A", MaxCapacity: 20},
+	{Name: "Spin Room", MaxCapacity: 15},
+	{Name: "Studio B", MaxCapacity: 12},
+}
+
+var instructors = []api.CreateInstructor{
+	{Name: "Marta Lopes", Email: "marta.lopes@studio.example.com", Phone: "+351 910 000 001", Bio: "Crossfit and HIIT coach"},
+	{Name: "Rui Pereira", Email: "rui.pereira@studio.example.com", Phone: "+351 910 000 002", Bio: "Former triathlete"},
+	{Name: "Sofia Martins", Email: "sofia.martins@studio.example.com", Phone: "+351 910 000 003", Bio: "Yoga and pilates teacher"},
+	{Name: "Tiago Ferreira", Email: "tiago.ferreira@studio.example.com", Phone: "+351 910 000 004", Bio: "Spinning instructor"},
+	{Name: "Ines Rodrigues", Email: "ines.rodrigues@studio.example.com", Phone: "+351 910 000 005", Bio: "Personal trainer"},
+}
+
+var (
+	firstNames = []string{"Ana", "Joao", "Maria", "Pedro", "Beatriz", "Tiago", "Carolina", "Miguel", "Ines", "Rui",
+		"Sofia", "Diogo", "Mariana", "Andre", "Rita", "Goncalo", "Catarina", "Bruno", "Leonor", "Sergio"}
+	lastNames = []string{"Silva", "Santos", "Ferreira", "Pereira", "Oliveira", "Costa", "Rodrigues", "Martins", "Jesus", "Sousa",
+		"Fernandes", "Goncalves", "Gomes", "Lopes", "Marques", "Alves", "Almeida", "Ribeiro", "Pinto", "Folgado"}
+)
+
+// seat is a seat of a class to book for a member.
+type seat struct {
+	UserId  int
+	ClassId int
+}
+
+// members generates n members with a unique email, a phone, a birth date and an emergency contact.
+func members(r *rand.Rand, n int) []api.CreateUser {
+	users := make([]api.CreateUser, 0, n)
+	for i := 1; i <= n; i++ {
+		first, last := pick(r, firstNames), pick(r, lastNames)
+		birthDate := time.Date(1960, time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, r.IntN(45*365))
+
+		users = append(users, api.CreateUser{
+			Name:      first + " " + last,
+			Email:     strings.ToLower(fmt.Sprintf("%s.%s.%d@example.com", first, last, i)),
+			Phone:     phone(r),
+			BirthDate: birthDate.Format("2006-01-02"),
+			EmergencyContact: &api.EmergencyContact{
+				Name:  pick(r, firstNames) + " " + last,
+				Phone: phone(r),
+			},
+		})
+	}
+
+	return users
+}
+
+// schedule generates the classes of a month in the rooms. The instructors take turns so that none of them
+// teaches two classes at the same time, and a class never holds more people than its room.
+func schedule(r *rand.Rand, month time.Time, rooms []api.Room, instructors []api.Instructor) []api.Class {
+	var classes []api.Class
+	for day := month; day.Month() == month.Month(); day = day.AddDate(0, 0, 1) {
+		slots := weekdaySlots
+		switch day.Weekday() {
+		case time.Sunday:
+			continue
+		case time.Saturday:
+			slots = saturdaySlots
+		}
+
+		for s, slot := range slots {
+			for i, room := range rooms {
+				class := pick(r, classTypes)
+				c := api.Class{
+					Name:     class.Name,
+					Type:     class.Type,
+					Date:     day.Add(slot),
+					Duration: class.Duration,
+					Capacity: min(class.Capacity, room.MaxCapacity),
+					RoomId:   room.Id,
+				}
+
+				if len(instructors) >= len(rooms) {
+					c.InstructorId = instructors[(day.Day()+s*len(rooms)+i)%len(instructors)].Id
+				}
+
+				classes = append(classes, c)
+			}
+		}
+	}
+
+	return classes
+}
+
+// bookings picks about perUser classes for each member among the classes with free seats, never more than
+// the free seats of a class and never two classes starting at the same time for a member.
+func bookings(r *rand.Rand, userIds []int, classes []api.ReadClass, perUser int) []seat {
+	free := make(map[int]int, len(classes))
+	for _, class := range classes {
+		free[class.Id] = class.Capacity - class.NumRegistrations
+	}
+
+	var picked []seat
+	for _, userId := range userIds {
+		busy := make(map[time.Time]bool)
+		for attempt := 0; attempt < 2*perUser && len(busy) < perUser && len(classes) > 0; attempt++ {
+			class := classes[r.IntN(len(classes))]
+			if free[class.Id] <= 0 || busy[class.Date] {
+				continue
+			}
+
+			free[class.Id]--
+			busy[class.Date] = true
+			picked = append(picked, seat{UserId: userId, ClassId: class.Id})
+		}
+	}
+
+	return picked
+}
+
+func pick[T any](r *rand.Rand, values []T) T {
+	return values[r.IntN(len(values))]
+}
+
+func phone(r *rand.Rand) string {
+	return fmt.Sprintf("+351 9%d%d %03d %03d", r.IntN(4)+1, r.IntN(10), r.IntN(1000), r.IntN(1000))
+}
@@ -0,0 +1,101 @@
+//go:build unittests
+// +build unittests
+
+package seed
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testMonth       = time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC)
+	testRooms       = []api.Room{{Id: 1, Name: "Studio A", MaxCapacity: 20}, {Id: 2, Name: "Spin Room", MaxCapacity: 15}, {Id: 3, Name: "Studio B", MaxCapacity: 12}}
+	testInstructors = []api.Instructor{{Id: 1}, {Id: 2}, {Id: 3}, {Id: 4}, {Id: 5}}
+)
+
+func TestDatasetIsDeterministic(t *testing.T) {
+	// Arrange
+	r1 := rand.New(rand.NewPCG(42, 42))
+	r2 := rand.New(rand.NewPCG(42, 42))
+
+	// Act & Assert
+	assert.Equal(t, members(r1, 30), members(r2, 30))
+	assert.Equal(t, schedule(r1, testMonth, testRooms, testInstructors), schedule(r2, testMonth, testRooms, testInstructors))
+}
+
+func TestMembers_UniqueEmails(t *testing.T) {
+	// Act
+	users := members(rand.New(rand.NewPCG(1, 1)), 500)
+
+	// Assert
+	emails := make(map[string]bool)
+	for _, user := range users {
+		assert.False(t, emails[user.Email], user.Email)
+		emails[user.Email] = true
+	}
+	assert.Len(t, emails, 500)
+}
+
+func TestSchedule_NoOverlap(t *testing.T) {
+	// Act
+	classes := schedule(rand.New(rand.NewPCG(1, 1)), testMonth, testRooms, testInstructors)
+
+	// Assert
+	assert.NotEmpty(t, classes)
+	for i, a := range classes {
+		assert.Equal(t, time.March, a.Date.Month())
+		assert.NotEqual(t, time.Sunday, a.Date.Weekday())
+		assert.LessOrEqual(t, a.Capacity, testRooms[a.RoomId-1].MaxCapacity)
+
+		for _, b := range classes[i+1:] {
+			overlap := a.Date.Before(b.Date.Add(time.Duration(b.Duration)*time.Minute)) &&
+				b.Date.Before(a.Date.Add(time.Duration(a.Duration)*time.Minute))
+			if overlap {
+				assert.NotEqual(t, a.RoomId, b.RoomId, "room of %v and %v", a.Date, b.Date)
+				assert.NotEqual(t, a.InstructorId, b.InstructorId, "instructor of %v and %v", a.Date, b.Date)
+			}
+		}
+	}
+}
+
+func TestBookings_RespectFreeSeats(t *testing.T) {
+	// Arrange
+	day := testMonth.Add(9 * time.Hour)
+	classes := []api.ReadClass{
+		{Id: 1, Class: api.Class{Date: day, Capacity: 3}},
+		{Id: 2, Class: api.Class{Date: day, Capacity: 5}, NumRegistrations: 4},
+		{Id: 3, Class: api.Class{Date: day.Add(3 * time.Hour), Capacity: 2}, NumRegistrations: 2},
+		{Id: 4, Class: api.Class{Date: day.Add(24 * time.Hour), Capacity: 10}},
+	}
+	userIds := make([]int, 20)
+	for i := range userIds {
+		userIds[i] = i + 1
+	}
+
+	// Act
+	seats := bookings(rand.New(rand.NewPCG(1, 1)), userIds, classes, 3)
+
+	// Assert
+	booked := make(map[int]int)
+	busy := make(map[int]map[time.Time]bool)
+	for _, s := range seats {
+		booked[s.ClassId]++
+
+		date := classes[s.ClassId-1].Date
+		if busy[s.UserId] == nil {
+			busy[s.UserId] = make(map[time.Time]bool)
+		}
+		assert.False(t, busy[s.UserId][date], "user %d books two classes at %v", s.UserId, date)
+		busy[s.UserId][date] = true
+	}
+
+	for _, class := range classes {
+		assert.LessOrEqual(t, booked[class.Id], class.Capacity-class.NumRegistrations, "class %d", class.Id)
+	}
+	assert.NotEmpty(t, seats)
+}
@@ -0,0 +1,258 @@
+// Package seed fills a database with a generated dataset for local demos and load tests: an owner, members
+// with memberships, rooms, instructors, a month of classes and bookings. The data goes through the
+// repositories of the server, and the same options give the same dataset on an empty database.
+package seed
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
+	"github.com/Flgado/fitnessStudioApp/internal/database/booking"
+	"github.com/Flgado/fitnessStudioApp/internal/database/classes"
+	instructorsdb "github.com/Flgado/fitnessStudioApp/internal/database/instructors"
+	"github.com/Flgado/fitnessStudioApp/internal/database/memberships"
+	roomsdb "github.com/Flgado/fitnessStudioApp/internal/database/rooms"
+	"github.com/Flgado/fitnessStudioApp/internal/database/users"
+	"github.com/jmoiron/sqlx"
+)
+
+// OwnerEmail signs in the owner of the seeded studio.
+const OwnerEmail = "owner@example.com"
+
+// planName names the membership plan of the seeded members.
+const planName = "Seed monthly unlimited"
+
+// Options of a dataset.
+type Options struct {
+	// Users is the number of members
+	Users int
+	// Month is the first day of the month of the classes, at midnight UTC
+	Month time.Time
+	// BookingsPerUser is the number of classes each member tries to book
+	BookingsPerUser int
+	// Seed makes the dataset deterministic
+	Seed uint64
+	// Password signs in the owner and all the members
+	Password string
+}
+
+// Summary counts the rows of a dataset.
+type Summary struct {
+	Users       int
+	Rooms       int
+	Instructors int
+	Classes     int
+	Bookings    int
+}
+
+// Seeder creates the datasets through the repositories.
+type Seeder struct {
+	usersWrite     users.WriteRepository
+	roomsRead      roomsdb.ReadRepository
+	roomsWrite     roomsdb.WriteRepository
+	instructorRead instructorsdb.ReadRepository
+	instructorRep  instructorsdb.WriteRepository
+	classesRead    classes.ReadRepository
+	classesWrite   classes.WriteRepository
+	membershipRead memberships.ReadRepository
+	membershipRep  memberships.WriteRepository
+	bookingWrite   booking.WriteRepository
+}
+
+// NewSeeder creates a Seeder writing to the database.
+func NewSeeder(db *sqlx.DB) *Seeder {
+	return &Seeder{
+		usersWrite:     users.NewWriteRepository(db),
+		roomsRead:      roomsdb.NewReadRepository(db),
+		roomsWrite:     roomsdb.NewWriteRepository(db),
+		instructorRead: instructorsdb.NewReadRepository(db),
+		instructorRep:  instructorsdb.NewWriteRepository(db),
+		classesRead:    classes.NewReadRepository(db),
+		classesWrite:   classes.NewWriteRepository(db),
+		membershipRead: memberships.NewReadRepository(db),
+		membershipRep:  memberships.NewWriteRepository(db),
+		bookingWrite:   booking.NewWriteRepository(db),
+	}
+}
+
+// Run creates the dataset.
+//
+// The members get an unlimited membership for the month, and book free seats of the classes only,
+// so nobody is waitlisted. It is meant for an empty database, the emails of the dataset must be free.
+//
+// param: ctx context.Context - Context object for managing the lifecycle of the seeding.
+// param: opts Options - Size, month and random seed of the dataset.
+//
+// @return Summary - Rows created.
+// @return error - Error if a row cannot be created, the rows already created are kept.
+func (s *Seeder) Run(ctx context.Context, opts Options) (Summary, error) {
+	r := rand.New(rand.NewPCG(opts.Seed, opts.Seed))
+	summary := Summary{}
+
+	passwordHash, err := auth.HashPassword(opts.Password)
+	if err != nil {
+		return summary, err
+	}
+
+	ownerId, err := s.usersWrite.AddWithCredentials(ctx, "Studio Owner", users.Credentials{Email: OwnerEmail, PasswordHash: passwordHash})
+	if err != nil {
+		return summary, fmt.Errorf("owner: %w", err)
+	}
+
+	if _, err = s.usersWrite.SetRole(ctx, ownerId, api.RoleOwner); err != nil {
+		return summary, fmt.Errorf("owner role: %w", err)
+	}
+
+	seededRooms, err := s.addRooms(ctx)
+	if err != nil {
+		return summary, err
+	}
+	summary.Rooms = len(seededRooms)
+
+	seededInstructors, err := s.addInstructors(ctx)
+	if err != nil {
+		return summary, err
+	}
+	summary.Instructors = len(seededInstructors)
+
+	plan, err := s.addPlan(ctx)
+	if err != nil {
+		return summary, err
+	}
+
+	userIds := make([]int, 0, opts.Users)
+	for _, member := range members(r, opts.Users) {
+		userId, err := s.addMember(ctx, member, passwordHash, plan, opts.Month)
+		if err != nil {
+			return summary, fmt.Errorf("member %s: %w", member.Email, err)
+		}
+
+		userIds = append(userIds, userId)
+	}
+	summary.Users = len(userIds)
+
+	rejected, err := s.classesWrite.Add(ctx, "", schedule(r, opts.Month, seededRooms, seededInstructors))
+	if err != nil {
+		return summary, fmt.Errorf("classes: %w", err)
+	}
+
+	if len(rejected) > 0 {
+		return summary, fmt.Errorf("classes: %d classes overlap classes already in the database", len(rejected))
+	}
+
+	monthEnd := opts.Month.AddDate(0, 1, 0).Add(-time.Microsecond)
+	monthClasses, err := s.classesRead.List(ctx, api.ClasseFilters{StartDateGte: &opts.Month, EndDateLe: &monthEnd})
+	if err != nil {
+		return summary, fmt.Errorf("classes: %w", err)
+	}
+
+	// the classes of the dataset are in the seeded rooms, in a stable order for the bookings
+	monthClasses = slices.DeleteFunc(monthClasses, func(c api.ReadClass) bool {
+		return !slices.ContainsFunc(seededRooms, func(room api.Room) bool { return room.Id == c.RoomId })
+	})
+	slices.SortFunc(monthClasses, func(a, b api.ReadClass) int { return a.Id - b.Id })
+	summary.Classes = len(monthClasses)
+
+	// booked before the month starts, when every class is still open
+	bookedAt := opts.Month.Add(-time.Hour)
+	for _, b := range bookings(r, userIds, monthClasses, opts.BookingsPerUser) {
+		result, err := s.bookingWrite.Add(ctx, b.UserId, b.ClassId, bookedAt, nil)
+		if err != nil {
+			return summary, fmt.Errorf("booking of class %d by user %d: %w", b.ClassId, b.UserId, err)
+		}
+
+		if result.Status == api.BookingStatusBooked {
+			summary.Bookings++
+		}
+	}
+
+	return summary, nil
+}
+
+// addRooms creates the rooms of the dataset, room names are unique.
+func (s *Seeder) addRooms(ctx context.Context) ([]api.Room, error) {
+	for _, room := range rooms {
+		if err := s.roomsWrite.Add(ctx, room); err != nil {
+			return nil, fmt.Errorf("room %s: %w", room.Name, err)
+		}
+	}
+
+	all, err := s.roomsRead.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(all, func(room api.Room) bool {
+		return !slices.ContainsFunc(rooms, func(r api.CreateRoom) bool { return r.Name == room.Name })
+	}), nil
+}
+
+// addInstructors creates the instructors of the dataset, instructor emails are unique.
+func (s *Seeder) addInstructors(ctx context.Context) ([]api.Instructor, error) {
+	for _, instructor := range instructors {
+		if err := s.instructorRep.Add(ctx, instructor); err != nil {
+			return nil, fmt.Errorf("instructor %s: %w", instructor.Name, err)
+		}
+	}
+
+	all, err := s.instructorRead.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(all, func(instructor api.Instructor) bool {
+		return !slices.ContainsFunc(instructors, func(i api.CreateInstructor) bool { return i.Email == instructor.Email })
+	}), nil
+}
+
+// addPlan creates the unlimited plan of the members, long enough for any month.
+func (s *Seeder) addPlan(ctx context.Context) (api.Plan, error) {
+	err := s.membershipRep.AddPlan(ctx, api.CreatePlan{Name: planName, Type: api.PlanTypeUnlimited, ValidityDays: 31, Price: 4500})
+	if err != nil {
+		return api.Plan{}, fmt.Errorf("plan: %w", err)
+	}
+
+	plans, err := s.membershipRead.ListPlans(ctx)
+	if err != nil {
+		return api.Plan{}, err
+	}
+
+	// the plan just created is the one with the highest ID
+	plan := api.Plan{}
+	for _, p := range plans {
+		if p.Name == planName && p.Id > plan.Id {
+			plan = p
+		}
+	}
+
+	return plan, nil
+}
+
+// addMember creates a member able to sign in, with a profile and a membership valid from the start of the month.
+func (s *Seeder) addMember(ctx context.Context, member api.CreateUser, passwordHash string, plan api.Plan, month time.Time) (int, error) {
+	userId, err := s.usersWrite.AddWithCredentials(ctx, member.Name, users.Credentials{Email: member.Email, PasswordHash: passwordHash})
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = s.usersWrite.Update(ctx, api.PatchUser{
+		Id:               userId,
+		Phone:            &member.Phone,
+		BirthDate:        &member.BirthDate,
+		EmergencyContact: member.EmergencyContact,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = s.membershipRep.Assign(ctx, userId, plan, month); err != nil {
+		return 0, err
+	}
+
+	return userId, nil
+}
@@ -0,0 +1,139 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
+	"github.com/Flgado/fitnessStudioApp/internal/database/apikeys"
+	"github.com/Flgado/fitnessStudioApp/utils"
+)
+
+type APIKeysUseCases interface {
+	CreateAPIKey(ctx context.Context, createdBy int, key api.CreateAPIKey) (api.NewAPIKey, error)
+	GetAPIKeys(ctx context.Context) ([]api.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyId int) error
+	Authenticate(ctx context.Context, key string) (auth.Identity, error)
+}
+
+type apiKeysUseCases struct {
+	readRep  apikeys.ReadRepository
+	writeRep apikeys.WriteRepository
+	clock    utils.Clock
+}
+
+func NewAPIKeysUseCases(readRep apikeys.ReadRepository, writeRep apikeys.WriteRepository, clock utils.Clock) APIKeysUseCases {
+	return &apiKeysUseCases{
+		readRep:  readRep,
+		writeRep: writeRep,
+		clock:    clock,
+	}
+}
+
+// CreateAPIKey creates an API key for a machine client. The key is only returned here, only its hash is stored.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: createdBy int - ID of the owner creating the key, the key acts on behalf of this user.
+// param: key api.CreateAPIKey - Name and scopes of the key.
+//
+// @return api.NewAPIKey - The key and its details.
+// @return error - Error if the name or the scopes are not valid.
+func (u *apiKeysUseCases) CreateAPIKey(ctx context.Context, createdBy int, key api.CreateAPIKey) (api.NewAPIKey, error) {
+	key.Name = strings.TrimSpace(key.Name)
+	if key.Name == "" || len(key.Name) > 50 {
+		return api.NewAPIKey{}, invalidAPIKeyError("The key name should have between 1 and 50 characters")
+	}
+
+	if len(key.Scopes) == 0 {
+		return api.NewAPIKey{}, invalidAPIKeyError("The key should have at least one scope")
+	}
+
+	for _, scope := range key.Scopes {
+		if !auth.ValidScope(scope) {
+			return api.NewAPIKey{}, invalidAPIKeyError(fmt.Sprintf("Scope %q is not valid, use <resource>:read or <resource>:write with a resource in %s",
+				scope, strings.Join(auth.ScopeResources, ", ")))
+		}
+	}
+
+	scopes := slices.Clone(key.Scopes)
+	slices.Sort(scopes)
+	key.Scopes = slices.Compact(scopes)
+
+	secret, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return api.NewAPIKey{}, err
+	}
+
+	stored, err := u.writeRep.Add(ctx, createdBy, key, prefix, hash)
+	if err != nil {
+		return api.NewAPIKey{}, err
+	}
+
+	return api.NewAPIKey{APIKey: stored, Key: secret}, nil
+}
+
+func (u *apiKeysUseCases) GetAPIKeys(ctx context.Context) ([]api.APIKey, error) {
+	return u.readRep.List(ctx)
+}
+
+// RevokeAPIKey stops an API key from being accepted.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: keyId int - ID of the key.
+//
+// @return error - Error if the key does not exist or is already revoked.
+func (u *apiKeysUseCases) RevokeAPIKey(ctx context.Context, keyId int) error {
+	rows, err := u.writeRep.Revoke(ctx, keyId, u.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return utils.E(http.StatusNotFound,
+			nil,
+			map[string]string{"message": "API Key Not Found"},
+			"The specified API key does not exist or is already revoked.",
+			"Please provide the ID of an active API key.")
+	}
+
+	return nil
+}
+
+// Authenticate identifies the machine client of an API key and records the use of the key.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: key string - Key sent by the client.
+//
+// @return auth.Identity - Caller acting on behalf of the owner who created the key, with the current role of
+// that user and the scopes of the key.
+// @return error - HTTP 401 error if the key is unknown or revoked.
+func (u *apiKeysUseCases) Authenticate(ctx context.Context, key string) (auth.Identity, error) {
+	stored, err := u.writeRep.Use(ctx, auth.HashAPIKey(key), u.clock.Now())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return auth.Identity{}, utils.E(http.StatusUnauthorized,
+				nil,
+				map[string]string{"message": "Unauthorized"},
+				"The API key is invalid or revoked.",
+				"Ask an owner of the studio for a new API key.")
+		}
+
+		return auth.Identity{}, err
+	}
+
+	return auth.Identity{UserId: stored.CreatedBy, Role: stored.CreatorRole, APIKeyId: stored.Id, Scopes: stored.Scopes}, nil
+}
+
+func invalidAPIKeyError(details string) error {
+	return utils.E(http.StatusBadRequest,
+		nil,
+		map[string]string{"message": "BadRequest"},
+		details,
+		"Read our documentation for more details")
+}
@@ -0,0 +1,128 @@
+//go:build unittests
+// +build unittests
+
+package usecases_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockAPIKeysReadRepository struct {
+	mock.Mock
+}
+
+func (m *mockAPIKeysReadRepository) List(ctx context.Context) ([]api.APIKey, error) {
+	return nil, nil
+}
+
+type mockAPIKeysWriteRepository struct {
+	mock.Mock
+}
+
+func (m *mockAPIKeysWriteRepository) Add(ctx context.Context, createdBy int, key api.CreateAPIKey, prefix string, hash string) (api.APIKey, error) {
+	args := m.Called(ctx, createdBy, key, prefix, hash)
+	return args.Get(0).(api.APIKey), args.Error(1)
+}
+
+func (m *mockAPIKeysWriteRepository) Revoke(ctx context.Context, keyId int, at time.Time) (int64, error) {
+	args := m.Called(ctx, keyId, at)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockAPIKeysWriteRepository) Use(ctx context.Context, hash string, at time.Time) (api.APIKey, error) {
+	args := m.Called(ctx, hash, at)
+	return args.Get(0).(api.APIKey), args.Error(1)
+}
+
+func TestCreateAPIKey_StoresOnlyTheHash(t *testing.T) {
+	// Arrange
+	mockWriteRepo := new(mockAPIKeysWriteRepository)
+	uc := usecases.NewAPIKeysUseCases(new(mockAPIKeysReadRepository), mockWriteRepo, testClock)
+	expectedKey := api.CreateAPIKey{Name: "Kiosk", Scopes: []string{"bookings:write", "classes:read"}}
+
+	var storedPrefix, storedHash string
+	mockWriteRepo.On("Add", mock.Anything, 1, expectedKey, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			storedPrefix = args.String(3)
+			storedHash = args.String(4)
+		}).
+		Return(api.APIKey{Id: 5, Name: "Kiosk", Scopes: expectedKey.Scopes, CreatedBy: 1}, nil)
+
+	// Act
+	created, err := uc.CreateAPIKey(context.Background(), 1, api.CreateAPIKey{Name: " Kiosk ", Scopes: []string{"classes:read", "bookings:write", "classes:read"}})
+
+	// Assert
+	assert.NoError(t, err)
+	mockWriteRepo.AssertExpectations(t)
+	assert.Equal(t, 5, created.Id)
+	assert.Equal(t, auth.HashAPIKey(created.Key), storedHash)
+	assert.NotEqual(t, created.Key, storedHash)
+	assert.Contains(t, created.Key, storedPrefix)
+}
+
+func TestCreateAPIKey_Validation(t *testing.T) {
+	testCases := []struct {
+		testName string
+		key      api.CreateAPIKey
+	}{
+		{"no name", api.CreateAPIKey{Scopes: []string{"classes:read"}}},
+		{"no scopes", api.CreateAPIKey{Name: "Kiosk"}},
+		{"unknown scope", api.CreateAPIKey{Name: "Kiosk", Scopes: []string{"classes:delete"}}},
+	}
+
+	for _, tc := range testCases {
+		// Arrange
+		mockWriteRepo := new(mockAPIKeysWriteRepository)
+		uc := usecases.NewAPIKeysUseCases(new(mockAPIKeysReadRepository), mockWriteRepo, testClock)
+
+		// Act
+		_, err := uc.CreateAPIKey(context.Background(), 1, tc.key)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, err.(utils.Error).Code, tc.testName)
+		mockWriteRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestRevokeAPIKey_NotFound(t *testing.T) {
+	// Arrange
+	mockWriteRepo := new(mockAPIKeysWriteRepository)
+	uc := usecases.NewAPIKeysUseCases(new(mockAPIKeysReadRepository), mockWriteRepo, testClock)
+	mockWriteRepo.On("Revoke", mock.Anything, 5, testNow).Return(int64(0), nil)
+
+	// Act
+	err := uc.RevokeAPIKey(context.Background(), 5)
+
+	// Assert
+	assert.Equal(t, http.StatusNotFound, err.(utils.Error).Code)
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	// Arrange
+	mockWriteRepo := new(mockAPIKeysWriteRepository)
+	uc := usecases.NewAPIKeysUseCases(new(mockAPIKeysReadRepository), mockWriteRepo, testClock)
+	mockWriteRepo.On("Use", mock.Anything, auth.HashAPIKey("fsk_valid"), testNow).
+		Return(api.APIKey{Id: 5, CreatedBy: 1, CreatorRole: api.RoleOwner, Scopes: []string{"bookings:write"}}, nil)
+	mockWriteRepo.On("Use", mock.Anything, auth.HashAPIKey("fsk_revoked"), testNow).
+		Return(api.APIKey{}, sql.ErrNoRows)
+
+	// Act
+	identity, err1 := uc.Authenticate(context.Background(), "fsk_valid")
+	_, err2 := uc.Authenticate(context.Background(), "fsk_revoked")
+
+	// Assert
+	assert.NoError(t, err1)
+	assert.Equal(t, auth.Identity{UserId: 1, Role: api.RoleOwner, APIKeyId: 5, Scopes: []string{"bookings:write"}}, identity)
+	assert.Equal(t, http.StatusUnauthorized, err2.(utils.Error).Code)
+}
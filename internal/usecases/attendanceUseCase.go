@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/Flgado/fitnessStudioApp/internal/database/booking"
+	"github.com/Flgado/fitnessStudioApp/utils"
+)
+
+type AttendanceUseCase interface {
+	CheckIn(ctx context.Context, userId int, classId int, checkedInBy int) error
+	MarkNoShows(ctx context.Context) (int64, error)
+}
+
+type attendanceUseCase struct {
+	wrRep booking.WriteRepository
+	clock utils.Clock
+}
+
+func NewAttendanceUseCase(wrRep booking.WriteRepository, clock utils.Clock) AttendanceUseCase {
+	return &attendanceUseCase{
+		wrRep: wrRep,
+		clock: clock,
+	}
+}
+
+// CheckIn marks a member as attending a booked class.
+//
+// The check-in time is the current time and checkedInBy records the user checking the member in.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the booked member.
+// param: classId int - ID of the class.
+// param: checkedInBy int - ID of the user checking the member in.
+//
+// @return error - Error if the booking cannot be checked in.
+func (uc *attendanceUseCase) CheckIn(ctx context.Context, userId int, classId int, checkedInBy int) error {
+	return uc.wrRep.CheckIn(ctx, userId, classId, checkedInBy, uc.clock.Now())
+}
+
+// MarkNoShows marks the bookings without check-in of the classes already ended as no-shows.
+//
+// It is meant to run periodically in the background.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+//
+// @return int64 - Number of bookings marked as no-shows.
+// @return error - Error if there is an issue updating the bookings.
+func (uc *attendanceUseCase) MarkNoShows(ctx context.Context) (int64, error) {
+	return uc.wrRep.MarkNoShows(ctx, uc.clock.Now())
+}
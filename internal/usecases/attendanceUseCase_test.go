@@ -0,0 +1,66 @@
+//go:build unittests
+// +build unittests
+
+package usecases_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckIn_Success(t *testing.T) {
+	// Arrange
+	mockWriteRepo := new(mockBookingWriteRepository)
+	uc := usecases.NewAttendanceUseCase(mockWriteRepo, testClock)
+
+	mockWriteRepo.On("CheckIn", mock.Anything, 1, 2, 3, testNow).Return(nil)
+
+	// Act
+	err := uc.CheckIn(context.Background(), 1, 2, 3)
+
+	// Assert
+	assert.NoError(t, err)
+	mockWriteRepo.AssertExpectations(t)
+}
+
+func TestCheckIn_AlreadyCheckedIn(t *testing.T) {
+	// Arrange
+	mockWriteRepo := new(mockBookingWriteRepository)
+	uc := usecases.NewAttendanceUseCase(mockWriteRepo, testClock)
+
+	expectedError := utils.E(http.StatusConflict,
+		nil,
+		map[string]string{"message": "Already Checked In"},
+		"The specified user is already checked in for this class.",
+		"Validate the class attendance.")
+	mockWriteRepo.On("CheckIn", mock.Anything, 1, 2, 3, testNow).Return(expectedError)
+
+	// Act
+	err := uc.CheckIn(context.Background(), 1, 2, 3)
+
+	// Assert
+	assert.Equal(t, expectedError, err)
+	mockWriteRepo.AssertExpectations(t)
+}
+
+func TestMarkNoShows(t *testing.T) {
+	// Arrange
+	mockWriteRepo := new(mockBookingWriteRepository)
+	uc := usecases.NewAttendanceUseCase(mockWriteRepo, testClock)
+
+	mockWriteRepo.On("MarkNoShows", mock.Anything, testNow).Return(int64(2), nil)
+
+	// Act
+	rows, err := uc.MarkNoShows(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), rows)
+	mockWriteRepo.AssertExpectations(t)
+}
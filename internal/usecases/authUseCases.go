@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
+	"github.com/Flgado/fitnessStudioApp/internal/database/users"
+	"github.com/Flgado/fitnessStudioApp/utils"
+)
+
+type AuthUseCases interface {
+	Register(ctx context.Context, register api.Register) (api.Token, error)
+	Login(ctx context.Context, email string, password string) (api.Token, error)
+}
+
+type authUseCases struct {
+	readRep  users.ReadRepository
+	writeRep users.WriteRepository
+	tokens   *auth.Tokens
+}
+
+func NewAuthUseCases(readRep users.ReadRepository, writeRep users.WriteRepository, tokens *auth.Tokens) AuthUseCases {
+	return &authUseCases{
+		readRep:  readRep,
+		writeRep: writeRep,
+		tokens:   tokens,
+	}
+}
+
+// Register creates a member with the credentials to sign in, and signs the user in.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: register api.Register - Name, email and password of the user.
+//
+// @return api.Token - Access token of the new user.
+// @return error - Error if the credentials are invalid or the email is already used.
+func (u *authUseCases) Register(ctx context.Context, register api.Register) (api.Token, error) {
+	name := strings.TrimSpace(register.Name)
+	if name == "" {
+		return api.Token{}, invalidCredentialsFormatError("User name should not be empty")
+	}
+
+	email, err := normalizeEmail(register.Email)
+	if err != nil {
+		return api.Token{}, err
+	}
+
+	if len(register.Password) < auth.MinPasswordLength || len(register.Password) > auth.MaxPasswordLength {
+		return api.Token{}, invalidCredentialsFormatError(fmt.Sprintf("Password should have between %d and %d characters",
+			auth.MinPasswordLength, auth.MaxPasswordLength))
+	}
+
+	hash, err := auth.HashPassword(register.Password)
+	if err != nil {
+		return api.Token{}, err
+	}
+
+	userId, err := u.writeRep.AddWithCredentials(ctx, name, users.Credentials{Email: email, PasswordHash: hash})
+	if err != nil {
+		return api.Token{}, err
+	}
+
+	return u.tokens.Issue(auth.Identity{UserId: userId, Role: api.RoleMember})
+}
+
+// Login checks the credentials of a user and issues an access token.
+//
+// It returns the same HTTP 401 error for an unknown email and a wrong password.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: email string - Email of the user.
+// param: password string - Password of the user.
+//
+// @return api.Token - Access token of the user.
+// @return error - Error if the credentials are not valid.
+func (u *authUseCases) Login(ctx context.Context, email string, password string) (api.Token, error) {
+	credentials, err := u.readRep.GetCredentials(ctx, strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			auth.CheckNoPassword(password)
+			return api.Token{}, invalidCredentialsError()
+		}
+
+		return api.Token{}, err
+	}
+
+	if !auth.CheckPassword(credentials.PasswordHash, password) {
+		return api.Token{}, invalidCredentialsError()
+	}
+
+	return u.tokens.Issue(auth.Identity{UserId: credentials.UserId, Role: credentials.Role})
+}
+
+func normalizeEmail(email string) (string, error) {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || address.Name != "" {
+		return "", invalidCredentialsFormatError("Email should be a valid address, e.g. member@studio.com")
+	}
+
+	return strings.ToLower(address.Address), nil
+}
+
+func invalidCredentialsFormatError(details string) error {
+	return utils.E(http.StatusBadRequest,
+		nil,
+		map[string]string{"message": "BadRequest"},
+		details,
+		"Read our documentation for more details")
+}
+
+func invalidCredentialsError() error {
+	return utils.E(http.StatusUnauthorized,
+		nil,
+		map[string]string{"message": "Invalid Credentials"},
+		"The email or the password is not valid.",
+		"Please check your credentials.")
+}
@@ -0,0 +1,236 @@
+//go:build unittests
+// +build unittests
+
+package usecases_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Flgado/fitnessStudioApp/config"
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
+	"github.com/Flgado/fitnessStudioApp/internal/database/users"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockUsersReadRepository struct {
+	mock.Mock
+}
+
+func (m *mockUsersReadRepository) List(ctx context.Context, filters api.UserFilters) ([]api.User, int, error) {
+	args := m.Called(ctx, filters)
+	return args.Get(0).([]api.User), args.Int(1), args.Error(2)
+}
+
+func (m *mockUsersReadRepository) GetById(ctx context.Context, id int) (api.User, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(api.User), args.Error(1)
+}
+
+func (m *mockUsersReadRepository) GetByEmail(ctx context.Context, email string) (api.User, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(api.User), args.Error(1)
+}
+
+func (m *mockUsersReadRepository) GetCredentials(ctx context.Context, email string) (users.Credentials, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(users.Credentials), args.Error(1)
+}
+
+type mockUsersWriteRepository struct {
+	mock.Mock
+}
+
+func (m *mockUsersWriteRepository) Add(ctx context.Context, user api.CreateUser) error {
+	return nil
+}
+
+func (m *mockUsersWriteRepository) Update(ctx context.Context, user api.PatchUser) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockUsersWriteRepository) SetRole(ctx context.Context, userId int, role string) (int64, error) {
+	args := m.Called(ctx, userId, role)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockUsersWriteRepository) AddWithCredentials(ctx context.Context, name string, credentials users.Credentials) (int, error) {
+	args := m.Called(ctx, name, credentials.Email)
+	return args.Int(0), args.Error(1)
+}
+
+func testTokens(t *testing.T) *auth.Tokens {
+	tokens, err := auth.NewTokens(config.Auth{TokenSecret: "test-token-secret-with-32-characters!", TokenTTL: time.Hour}, testClock)
+	assert.NoError(t, err)
+	return tokens
+}
+
+func TestRegister_IssuesTokenForNewUser(t *testing.T) {
+	// Arrange
+	tokens := testTokens(t)
+	mockWriteRepo := new(mockUsersWriteRepository)
+	uc := usecases.NewAuthUseCases(new(mockUsersReadRepository), mockWriteRepo, tokens)
+	mockWriteRepo.On("AddWithCredentials", mock.Anything, "Ana", "ana@studio.com").Return(12, nil)
+
+	// Act
+	token, err := uc.Register(context.Background(), api.Register{Name: " Ana ", Email: "Ana@Studio.com", Password: "correct horse"})
+
+	// Assert
+	assert.NoError(t, err)
+	mockWriteRepo.AssertExpectations(t)
+	identity, err := tokens.Verify(token.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Identity{UserId: 12, Role: api.RoleMember}, identity)
+}
+
+func TestRegister_Validation(t *testing.T) {
+	testCases := []struct {
+		testName string
+		register api.Register
+	}{
+		{"no name", api.Register{Email: "ana@studio.com", Password: "correct horse"}},
+		{"invalid email", api.Register{Name: "Ana", Email: "ana", Password: "correct horse"}},
+		{"email with display name", api.Register{Name: "Ana", Email: "Ana <ana@studio.com>", Password: "correct horse"}},
+		{"short password", api.Register{Name: "Ana", Email: "ana@studio.com", Password: "short"}},
+	}
+
+	for _, tc := range testCases {
+		// Arrange
+		mockWriteRepo := new(mockUsersWriteRepository)
+		uc := usecases.NewAuthUseCases(new(mockUsersReadRepository), mockWriteRepo, testTokens(t))
+
+		// Act
+		_, err := uc.Register(context.Background(), tc.register)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, err.(utils.Error).Code, tc.testName)
+		mockWriteRepo.AssertNotCalled(t, "AddWithCredentials", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	hash, err := auth.HashPassword("correct horse")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		testName    string
+		credentials users.Credentials
+		repoErr     error
+		password    string
+		expectedErr int
+	}{
+		{"valid credentials", users.Credentials{UserId: 12, Email: "ana@studio.com", PasswordHash: hash, Role: api.RoleInstructor}, nil, "correct horse", 0},
+		{"wrong password", users.Credentials{UserId: 12, Email: "ana@studio.com", PasswordHash: hash, Role: api.RoleInstructor}, nil, "wrong horse", http.StatusUnauthorized},
+		{"unknown email", users.Credentials{}, sql.ErrNoRows, "correct horse", http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		// Arrange
+		tokens := testTokens(t)
+		mockReadRepo := new(mockUsersReadRepository)
+		uc := usecases.NewAuthUseCases(mockReadRepo, new(mockUsersWriteRepository), tokens)
+		mockReadRepo.On("GetCredentials", mock.Anything, "ana@studio.com").Return(tc.credentials, tc.repoErr)
+
+		// Act
+		token, err := uc.Login(context.Background(), " ana@studio.com ", tc.password)
+
+		// Assert
+		if tc.expectedErr != 0 {
+			assert.Equal(t, tc.expectedErr, err.(utils.Error).Code, tc.testName)
+			assert.Empty(t, token.AccessToken, tc.testName)
+			continue
+		}
+
+		assert.NoError(t, err, tc.testName)
+		identity, err := tokens.Verify(token.AccessToken)
+		assert.NoError(t, err, tc.testName)
+		assert.Equal(t, auth.Identity{UserId: 12, Role: api.RoleInstructor}, identity, tc.testName)
+	}
+}
+
+func TestSetUserRole(t *testing.T) {
+	testCases := []struct {
+		testName     string
+		role         string
+		rows         int64
+		expectedCode int
+	}{
+		{"valid role", api.RoleInstructor, 1, 0},
+		{"unknown user", api.RoleOwner, 0, http.StatusNotFound},
+		{"unknown role", "admin", 0, http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		// Arrange
+		mockWriteRepo := new(mockUsersWriteRepository)
+		uc := usecases.NewUserUseCase(new(mockUsersReadRepository), mockWriteRepo)
+		mockWriteRepo.On("SetRole", mock.Anything, 3, tc.role).Return(tc.rows, nil)
+
+		// Act
+		err := uc.SetUserRole(context.Background(), 3, tc.role)
+
+		// Assert
+		if tc.expectedCode == 0 {
+			assert.NoError(t, err, tc.testName)
+			continue
+		}
+
+		assert.Equal(t, tc.expectedCode, err.(utils.Error).Code, tc.testName)
+		if tc.expectedCode == http.StatusBadRequest {
+			mockWriteRepo.AssertNotCalled(t, "SetRole", mock.Anything, mock.Anything, mock.Anything)
+		}
+	}
+}
+
+func TestGetUserByEmail(t *testing.T) {
+	// Arrange
+	mockReadRepo := new(mockUsersReadRepository)
+	uc := usecases.NewUserUseCase(mockReadRepo, new(mockUsersWriteRepository))
+	ana := api.User{Id: 3, Name: "Ana", Email: "ana@studio.com", Role: api.RoleMember}
+	mockReadRepo.On("GetByEmail", mock.Anything, "ana@studio.com").Return(ana, nil)
+	mockReadRepo.On("GetByEmail", mock.Anything, "nobody@studio.com").Return(api.User{}, sql.ErrNoRows)
+
+	// Act
+	user, err := uc.GetUserByEmail(context.Background(), "ana@studio.com")
+	_, errNotFound := uc.GetUserByEmail(context.Background(), "nobody@studio.com")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, ana, user)
+	assert.Equal(t, http.StatusNotFound, errNotFound.(utils.Error).Code)
+}
+
+func TestSearchUsers(t *testing.T) {
+	testCases := []struct {
+		testName string
+		filters  api.UserFilters
+		expected api.UserFilters
+	}{
+		{"defaults", api.UserFilters{Search: "ana"}, api.UserFilters{Search: "ana", SortBy: "id", Page: 1, PageSize: api.UsersDefaultPageSize}},
+		{"page kept", api.UserFilters{SortBy: "name", Descending: true, Page: 3, PageSize: 10}, api.UserFilters{SortBy: "name", Descending: true, Page: 3, PageSize: 10}},
+		{"page size bounded", api.UserFilters{PageSize: 1000}, api.UserFilters{SortBy: "id", Page: 1, PageSize: api.UsersMaxPageSize}},
+	}
+
+	for _, tc := range testCases {
+		// Arrange
+		mockReadRepo := new(mockUsersReadRepository)
+		uc := usecases.NewUserUseCase(mockReadRepo, new(mockUsersWriteRepository))
+		mockReadRepo.On("List", mock.Anything, tc.expected).Return([]api.User{{Id: 1, Name: "Ana"}}, 1, nil)
+
+		// Act
+		users, total, err := uc.SearchUsers(context.Background(), tc.filters)
+
+		// Assert
+		assert.NoError(t, err, tc.testName)
+		assert.Len(t, users, 1, tc.testName)
+		assert.Equal(t, 1, total, tc.testName)
+		mockReadRepo.AssertExpectations(t)
+	}
+}
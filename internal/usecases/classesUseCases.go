@@ -6,17 +6,66 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"sync"
+	"strings"
 	"time"
 
 	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/database/booking"
 	"github.com/Flgado/fitnessStudioApp/internal/database/classes"
+	"github.com/Flgado/fitnessStudioApp/internal/database/instructors"
+	"github.com/Flgado/fitnessStudioApp/internal/database/rooms"
+	"github.com/Flgado/fitnessStudioApp/internal/recurrence"
 	"github.com/Flgado/fitnessStudioApp/utils"
 )
 
-type reservedDaysInfo struct {
-	days []time.Time
-	mu   sync.Mutex
+// defaultClassDuration is the duration, in minutes, used for classes scheduled without one.
+const defaultClassDuration = 60
+
+// classSlot is the time range [start, end) taken by a class.
+type classSlot struct {
+	start time.Time
+	end   time.Time
+}
+
+// overlaps reports whether two slots share any instant.
+func (s classSlot) overlaps(other classSlot) bool {
+	return s.start.Before(other.end) && other.start.Before(s.end)
+}
+
+func slotOf(class api.Class) classSlot {
+	return classSlot{start: class.Date, end: class.End()}
+}
+
+// scheduleBucket identifies the classes sharing the time slots of a room, or of an instructor, in a month.
+type scheduleBucket struct {
+	instructor bool
+	id         int
+	year       int
+	month      time.Month
+}
+
+// filter returns the repository filter of the classes taking a slot in the bucket.
+func (b scheduleBucket) filter() api.ScheduleFilter {
+	filter := api.ScheduleFilter{Year: b.year, Month: b.month}
+	if b.instructor {
+		filter.InstructorId = b.id
+	} else {
+		filter.RoomId = b.id
+	}
+
+	return filter
+}
+
+// scheduleKeys returns the buckets a class takes a slot in: the month of its room and,
+// when it has one, the month of its instructor, who cannot teach two overlapping classes.
+func scheduleKeys(class api.Class) []scheduleBucket {
+	year, month := class.Date.Year(), class.Date.Month()
+	keys := []scheduleBucket{{id: class.RoomId, year: year, month: month}}
+	if class.InstructorId != 0 {
+		keys = append(keys, scheduleBucket{instructor: true, id: class.InstructorId, year: year, month: month})
+	}
+
+	return keys
 }
 
 type ClassesUseCases interface {
@@ -24,18 +73,26 @@ type ClassesUseCases interface {
 	CreateClass(ctx context.Context, class api.ClassScheduler) ([]api.Class, error)
 	UpdateClass(ctx context.Context, updateClass api.UpdateClass, classId int) (int64, error)
 	GetClassById(ctx context.Context, classId int) (api.ReadClass, error)
+	UpdateSeries(ctx context.Context, updateSeries api.UpdateSeries, classId int) (int64, error)
+	CancelSeries(ctx context.Context, classId int) (int64, error)
+	CancelClass(ctx context.Context, classId int) error
 }
 
 type classesUseCases struct {
-	readRep      classes.ReadRepository
-	wrRep        classes.WriteRepository
-	reservedDays sync.Map
+	readRep        classes.ReadRepository
+	wrRep          classes.WriteRepository
+	roomsRep       rooms.ReadRepository
+	instructorsRep instructors.ReadRepository
+	clock          utils.Clock
 }
 
-func NewClassesUseCases(readRepo classes.ReadRepository, wrRepo classes.WriteRepository) ClassesUseCases {
+func NewClassesUseCases(readRepo classes.ReadRepository, wrRepo classes.WriteRepository, roomsRepo rooms.ReadRepository, instructorsRepo instructors.ReadRepository, clock utils.Clock) ClassesUseCases {
 	return &classesUseCases{
-		readRep: readRepo,
-		wrRep:   wrRepo,
+		readRep:        readRepo,
+		wrRep:          wrRepo,
+		roomsRep:       roomsRepo,
+		instructorsRep: instructorsRepo,
+		clock:          clock,
 	}
 }
 
@@ -88,7 +145,9 @@ func (c *classesUseCases) GetClassById(ctx context.Context, classId int) (api.Re
 //
 // This method takes a context.Context object for managing the lifecycle of the request
 // and a api.ClassScheduler struct containing details about the classes to be created.
-// It separates the classes by year and month, checks for availability, and adds them to the repository.
+// It validates the capacity against the maximum capacity of the room, expands the classes of the
+// scheduler and adds them to the repository as a series. The repository rejects the classes overlapping
+// another class scheduled in the same room or taught by the same instructor, even by another process.
 // It returns a slice of api.Class structs representing the classes that could not be scheduled
 // due to unavailability or errors, and nil error if successful.
 //
@@ -99,7 +158,7 @@ func (c *classesUseCases) GetClassById(ctx context.Context, classId int) (api.Re
 // @return error - Error if there is an issue scheduling the classes.
 func (c *classesUseCases) CreateClass(ctx context.Context, classScheduler api.ClassScheduler) ([]api.Class, error) {
 
-	if classScheduler.EndDate.Before(classScheduler.StartDate) {
+	if !classScheduler.EndDate.IsZero() && classScheduler.EndDate.Before(classScheduler.StartDate) {
 		return []api.Class{}, utils.E(http.StatusBadRequest,
 			nil,
 			map[string]string{"message": "BadRequest"},
@@ -107,33 +166,43 @@ func (c *classesUseCases) CreateClass(ctx context.Context, classScheduler api.Cl
 			"Please select the dates accurately.")
 
 	}
-	sc := separateClassByYearMonth(classScheduler)
-	var notPossibleSchedulerReport []api.Class
-	for key, classList := range sc {
 
-		possibleScheduler, impossibleToSheduler, err := c.getAvailableDays(key, classList)
+	if classScheduler.Duration < 0 {
+		return []api.Class{}, utils.E(http.StatusBadRequest,
+			nil,
+			map[string]string{"message": "BadRequest"},
+			"Duration should be a positive number of minutes",
+			"Please select a valid duration.")
+	}
 
-		if len(impossibleToSheduler) != 0 {
-			notPossibleSchedulerReport = append(notPossibleSchedulerReport, impossibleToSheduler...)
-		}
-		if err != nil {
-			// all classes cannot be scheduler
-			return append(possibleScheduler, notPossibleSchedulerReport...), err
-		}
+	if classScheduler.Duration == 0 {
+		classScheduler.Duration = defaultClassDuration
+	}
 
-		if len(possibleScheduler) != 0 {
-			err = c.wrRep.Add(ctx, possibleScheduler)
-		}
+	if err := c.validateRoomCapacity(ctx, classScheduler.RoomId, classScheduler.Capacity); err != nil {
+		return []api.Class{}, err
+	}
 
-		if err != nil {
-			// Remove the values from the cache if something went wrong in the repository
-			_ = c.removeDaysFromCache(key, possibleScheduler)
-			// all classes cannot be scheduler
-			return append(possibleScheduler, notPossibleSchedulerReport...), err
-		}
+	if err := c.validateInstructor(ctx, classScheduler.InstructorId); err != nil {
+		return []api.Class{}, err
+	}
+
+	classList, err := expandClasses(classScheduler)
+	if err != nil {
+		return []api.Class{}, utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			err.Error(),
+			"Please review the end date and the recurrence rule.")
 	}
 
-	return notPossibleSchedulerReport, nil
+	rejected, err := c.wrRep.Add(ctx, classScheduler.RRule, classList)
+	if err != nil {
+		// all classes cannot be scheduler
+		return classList, err
+	}
+
+	return rejected, nil
 }
 
 // UpdateClass updates the details of a class with the provided information.
@@ -141,8 +210,10 @@ func (c *classesUseCases) CreateClass(ctx context.Context, classScheduler api.Cl
 // This method takes a context.Context object for managing the lifecycle of the request
 // and an api.UpdateClass struct containing the updated details of the class.
 // It also takes the ID of the class to be updated.
-// It performs validations such as checking if the provided date is in the past
-// and if the updated capacity can be set, and then updates the class in the repository.
+// It performs validations such as checking if the provided date is in the past, if the class is cancelled
+// and if the room can hold the capacity of the class, and then updates the class in the repository,
+// which checks that the updated capacity can be set and that the new time range overlaps no other
+// class of the room or of the instructor.
 // It returns the number of rows affected by the update operation and nil error if successful.
 //
 // param: ctx context.Context - Context object for managing the request lifecycle.
@@ -153,7 +224,7 @@ func (c *classesUseCases) CreateClass(ctx context.Context, classScheduler api.Cl
 // @return error - Error if there is an issue updating the class.
 func (c *classesUseCases) UpdateClass(ctx context.Context, updateClass api.UpdateClass, classId int) (int64, error) {
 
-	if updateClass.Date != nil && updateClass.Date.Before(time.Now()) {
+	if updateClass.Date != nil && updateClass.Date.Before(c.clock.Now()) {
 		return 0, utils.E(http.StatusUnprocessableEntity,
 			nil,
 			map[string]string{"message": "Status Unprocessabe Entity"},
@@ -161,7 +232,15 @@ func (c *classesUseCases) UpdateClass(ctx context.Context, updateClass api.Updat
 			"Please select a valid day")
 	}
 
-	_, err := c.readRep.GetById(ctx, classId)
+	if updateClass.Duration != nil && *updateClass.Duration <= 0 {
+		return 0, utils.E(http.StatusBadRequest,
+			nil,
+			map[string]string{"message": "BadRequest"},
+			"Duration should be a positive number of minutes",
+			"Please select a valid duration.")
+	}
+
+	existing, err := c.readRep.GetById(ctx, classId)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -175,168 +254,410 @@ func (c *classesUseCases) UpdateClass(ctx context.Context, updateClass api.Updat
 		return 0, err
 	}
 
-	if updateClass.Date != nil {
-		// Validate in cache if day is availabe
-		key := fmt.Sprintf("%d-%02d", updateClass.Date.Year(), updateClass.Date.Month())
+	if existing.Status == api.ClassStatusCancelled {
+		return 0, utils.E(http.StatusUnprocessableEntity,
+			nil,
+			map[string]string{"message": "Class Cancelled"},
+			"The specified class is cancelled.",
+			"Please select a scheduled class.")
+	}
 
-		isAvailable := c.isDayAvailable(key, *updateClass.Date)
-		if !isAvailable {
-			return 0, utils.E(http.StatusNotFound,
-				nil,
-				map[string]string{"message": "Date already reserved"},
-				"The selected date is already reserved.",
-				"Please choose a different date or class.")
+	moved := existing.Class
+	if updateClass.Capacity != nil {
+		moved.Capacity = *updateClass.Capacity
+	}
+	if updateClass.RoomId != nil {
+		moved.RoomId = *updateClass.RoomId
+	}
+	if updateClass.InstructorId != nil {
+		moved.InstructorId = *updateClass.InstructorId
+	}
+
+	if updateClass.RoomId != nil || updateClass.Capacity != nil {
+		if err = c.validateRoomCapacity(ctx, moved.RoomId, moved.Capacity); err != nil {
+			return 0, err
 		}
 	}
 
+	if updateClass.InstructorId != nil {
+		if err = c.validateInstructor(ctx, moved.InstructorId); err != nil {
+			return 0, err
+		}
+	}
+
+	// The repository rejects a time range overlapping another class of the room or of the instructor
 	return c.wrRep.Update(ctx, classId, updateClass)
 }
 
-// removeDaysFromCache removes reserved days from the cache for a specific month.
+// UpdateSeries updates a class and all the following occurrences of its series.
 //
-// This method takes a string key representing the month and a slice of api.Class
-// representing the classes whose reserved days need to be removed from the cache.
-// It removes the reserved days associated with the provided classes from the cache.
-// It returns nil if the operation is successful.
+// This method takes a context.Context object for managing the lifecycle of the request,
+// an api.UpdateSeries struct containing the details to change and the ID of the first class to update.
+// A new start time moves every occurrence to that time of its own day. The time ranges of the
+// occurrences are checked against the other classes of their rooms and instructors, and the
+// classes are then updated in a single transaction, running the repository capacity checks on each one.
+// Cancelled occurrences are left untouched.
 //
-// param: key string - Key representing the month (e.g., "2024-03").
-// param: classList []api.Class - Slice of Class structs representing the classes.
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: updateSeries api.UpdateSeries - Struct containing the updated details of the occurrences.
+// param: classId int - ID of the first class to update.
 //
-// @return error - Error if there is an issue removing reserved days from the cache.
-func (c *classesUseCases) removeDaysFromCache(key string, classList []api.Class) error {
-	value, _ := c.reservedDays.LoadOrStore(key, &reservedDaysInfo{})
-	info := value.(*reservedDaysInfo)
+// @return int64 - Number of classes updated.
+// @return error - Error if any of the occurrences cannot be updated.
+func (c *classesUseCases) UpdateSeries(ctx context.Context, updateSeries api.UpdateSeries, classId int) (int64, error) {
+	if updateSeries.Duration != nil && *updateSeries.Duration <= 0 {
+		return 0, utils.E(http.StatusBadRequest,
+			nil,
+			map[string]string{"message": "BadRequest"},
+			"Duration should be a positive number of minutes",
+			"Please select a valid duration.")
+	}
 
-	// Lock to prevent concurrent access to the reserved days slice
-	info.mu.Lock()
-	defer info.mu.Unlock()
+	class, err := c.GetClassById(ctx, classId)
+	if err != nil {
+		return 0, err
+	}
 
-	// Create a map of reserved days for constant-time lookup
-	daysToRemove := make(map[int]struct{})
-	for _, day := range classList {
-		daysToRemove[day.Date.Day()] = struct{}{}
+	occurrences, err := c.getSeriesFrom(ctx, class)
+	if err != nil {
+		return 0, err
 	}
 
-	var newMouthCache []time.Time
-	for _, cached := range info.days {
-		if _, reserved := daysToRemove[cached.Day()]; !reserved {
-			newMouthCache = append(newMouthCache, cached)
+	if updateSeries.InstructorId != nil {
+		if err = c.validateInstructor(ctx, *updateSeries.InstructorId); err != nil {
+			return 0, err
 		}
 	}
 
-	c.reservedDays.Store(key, newMouthCache)
-	return nil
+	updates := make([]api.OccurrenceUpdate, 0, len(occurrences))
+	previous := make([]api.Class, 0, len(occurrences))
+	moved := make([]api.Class, 0, len(occurrences))
+	checkedRooms := make(map[[2]int]struct{})
+	for _, occurrence := range occurrences {
+		update := api.UpdateClass{
+			Name:         updateSeries.Name,
+			Duration:     updateSeries.Duration,
+			Capacity:     updateSeries.Capacity,
+			RoomId:       updateSeries.RoomId,
+			InstructorId: updateSeries.InstructorId,
+		}
+
+		class := occurrence.Class
+		if updateSeries.StartTime != nil {
+			y, m, d := class.Date.Date()
+			date := time.Date(y, m, d, 0, 0, 0, 0, class.Date.Location()).Add(*updateSeries.StartTime)
+			if date.Before(c.clock.Now()) {
+				return 0, utils.E(http.StatusUnprocessableEntity,
+					nil,
+					map[string]string{"message": "Status Unprocessabe Entity"},
+					fmt.Sprintf("New Date of class %d cannot be in the pass", occurrence.Id),
+					"Please select a valid start time or a later class")
+			}
+			update.Date = &date
+			class.Date = date
+		}
+		if updateSeries.Duration != nil {
+			class.Duration = *updateSeries.Duration
+		}
+		if updateSeries.Capacity != nil {
+			class.Capacity = *updateSeries.Capacity
+		}
+		if updateSeries.RoomId != nil {
+			class.RoomId = *updateSeries.RoomId
+		}
+		if updateSeries.InstructorId != nil {
+			class.InstructorId = *updateSeries.InstructorId
+		}
+
+		if updateSeries.RoomId != nil || updateSeries.Capacity != nil {
+			// occurrences sharing room and capacity only need one check
+			roomCapacity := [2]int{class.RoomId, class.Capacity}
+			if _, checked := checkedRooms[roomCapacity]; !checked {
+				if err = c.validateRoomCapacity(ctx, class.RoomId, class.Capacity); err != nil {
+					return 0, err
+				}
+				checkedRooms[roomCapacity] = struct{}{}
+			}
+		}
+
+		updates = append(updates, api.OccurrenceUpdate{ClassId: occurrence.Id, Update: update})
+		previous = append(previous, occurrence.Class)
+		moved = append(moved, class)
+	}
+
+	reschedule := updateSeries.StartTime != nil || updateSeries.Duration != nil || updateSeries.RoomId != nil || updateSeries.InstructorId != nil
+	if !reschedule {
+		return c.wrRep.UpdateMany(ctx, updates)
+	}
+
+	// Report every occurrence overlapping another class, the repository only rejects the first one
+	conflicts, err := c.findConflicts(ctx, moved, previous)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(conflicts) != 0 {
+		return 0, utils.E(http.StatusNotFound,
+			nil,
+			map[string]string{"message": "Date already reserved"},
+			fmt.Sprintf("The occurrences at %s overlap other classes.", strings.Join(conflicts, ", ")),
+			"Please choose a different start time, room or instructor.")
+	}
+
+	return c.wrRep.UpdateMany(ctx, updates)
 }
 
-// isDayAvailable checks if a specific day is available for scheduling.
+// CancelSeries cancels a class and all the following occurrences of its series.
 //
-// This method takes a string key representing the month and a time.Time object
-// representing the day to be checked for availability.
-// It checks if the provided day is already reserved and returns true if it is available,
-// otherwise returns false.
+// This method takes a context.Context object for managing the lifecycle of the request
+// and the ID of the first class to cancel. The classes keep existing with the cancelled status,
+// their bookings and waitlist entries are released in a single transaction and their
+// time ranges become available again. The occurrences already started are kept.
+// It returns a HTTP 422 error if the first class is in progress or finished.
 //
-// param: key string - Key representing the month (e.g., "2024-03").
-// param: date time.Time - Date to be checked for availability.
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: classId int - ID of the first class to cancel.
 //
-// @return bool - True if the day is available, false otherwise.
-func (c *classesUseCases) isDayAvailable(key string, date time.Time) bool {
-	// Load or initialize reserved days info for the key
-	value, _ := c.reservedDays.LoadOrStore(key, &reservedDaysInfo{})
-	info := value.(*reservedDaysInfo)
+// @return int64 - Number of classes cancelled.
+// @return error - Error if there is an issue cancelling the classes.
+func (c *classesUseCases) CancelSeries(ctx context.Context, classId int) (int64, error) {
+	class, err := c.GetClassById(ctx, classId)
+	if err != nil {
+		return 0, err
+	}
 
-	// Lock to prevent concurrent access to the reserved days slice
-	info.mu.Lock()
-	defer info.mu.Unlock()
+	if class.Status == api.ClassStatusScheduled {
+		if err = booking.CheckNotStarted(class.Date, class.Duration, c.clock.Now()); err != nil {
+			return 0, err
+		}
+	}
 
-	// Create a map of reserved days for constant-time lookup
-	reservedMap := make(map[int]struct{})
-	for _, day := range info.days {
-		reservedMap[day.Day()] = struct{}{}
+	occurrences, err := c.getSeriesFrom(ctx, class)
+	if err != nil {
+		return 0, err
 	}
 
-	if _, reserved := reservedMap[date.Day()]; reserved {
-		return false
+	classIds := make([]int, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		classIds = append(classIds, occurrence.Id)
 	}
 
-	info.days = append(info.days, date)
+	return c.wrRep.Cancel(ctx, classIds)
+}
+
+// CancelClass cancels a single class, e.g. when its instructor is sick.
+//
+// This method takes a context.Context object for managing the lifecycle of the request
+// and the ID of the class to cancel. The class keeps existing with the cancelled status,
+// its bookings and waitlist entries are released, every booked member is recorded in the
+// notification outbox and its time range becomes available again.
+// It returns a HTTP 404 error if the class does not exist and a HTTP 422 error if it is already cancelled,
+// in progress or finished.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: classId int - ID of the class to cancel.
+//
+// @return error - Error if there is an issue cancelling the class.
+func (c *classesUseCases) CancelClass(ctx context.Context, classId int) error {
+	class, err := c.GetClassById(ctx, classId)
+	if err != nil {
+		return err
+	}
 
-	return true
+	if class.Status == api.ClassStatusCancelled {
+		return utils.E(http.StatusUnprocessableEntity,
+			nil,
+			map[string]string{"message": "Class Cancelled"},
+			"The specified class is already cancelled.",
+			"Please select a scheduled class.")
+	}
+
+	if err = booking.CheckNotStarted(class.Date, class.Duration, c.clock.Now()); err != nil {
+		return err
+	}
+
+	_, err = c.wrRep.Cancel(ctx, []int{classId})
+	return err
 }
 
-// getAvailableDays retrieves available days for scheduling classes based on the provided class list.
+// getSeriesFrom retrieves a class and the following scheduled occurrences of its series.
 //
-// This method takes a string key representing the month and a slice of api.Class
-// representing the classes to be scheduled.
-// It checks for available days within the month and returns a slice of available classes
-// and a slice of classes that could not be scheduled due to unavailability.
-// It returns nil error if successful.
+// The occurrences already started are left out, they can no longer be changed.
 //
-// param: key string - Key representing the month (e.g., "2024-03").
-// param: classList []api.Class - Slice of Class structs representing the classes to be scheduled.
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: class api.ReadClass - First class.
 //
-// @return []api.Class - Slice of available Class structs.
-// @return []api.Class - Slice of unavailable Class structs.
-// @return error - Error if there is an issue retrieving available days.
-func (c *classesUseCases) getAvailableDays(key string, classList []api.Class) ([]api.Class, []api.Class, error) {
-	// Load or initialize reserved days info for the key
-	value, _ := c.reservedDays.LoadOrStore(key, &reservedDaysInfo{})
-	info := value.(*reservedDaysInfo)
+// @return []api.ReadClass - Occurrences ordered by date.
+// @return error - Error if the class is not part of a series or is cancelled.
+func (c *classesUseCases) getSeriesFrom(ctx context.Context, class api.ReadClass) ([]api.ReadClass, error) {
+	if class.SeriesId == 0 {
+		return nil, utils.E(http.StatusUnprocessableEntity,
+			nil,
+			map[string]string{"message": "Class Without Series"},
+			"The specified class is not part of a series.",
+			"Please update the class directly.")
+	}
 
-	// Lock to prevent concurrent access to the reserved days slice
-	info.mu.Lock()
-	defer info.mu.Unlock()
+	from := class.Date
+	if now := c.clock.Now(); now.After(from) {
+		from = now
+	}
 
-	reservedMap := make(map[int]struct{})
-	for _, day := range info.days {
-		reservedMap[day.Day()] = struct{}{}
+	occurrences, err := c.readRep.ListSeries(ctx, class.SeriesId, from)
+	if err != nil {
+		return nil, err
 	}
 
-	// Filter out the reserved days from the classList
-	var availableDays []api.Class
-	var notPossibleToReserve []api.Class
+	if len(occurrences) == 0 {
+		return nil, utils.E(http.StatusUnprocessableEntity,
+			nil,
+			map[string]string{"message": "Class Cancelled"},
+			"The specified class is cancelled.",
+			"Please select a scheduled class of the series.")
+	}
 
-	for _, class := range classList {
-		if _, reserved := reservedMap[class.Date.Day()]; !reserved {
-			availableDays = append(availableDays, class)
-		} else {
-			notPossibleToReserve = append(notPossibleToReserve, class)
+	return occurrences, nil
+}
+
+// validateRoomCapacity checks that the room exists and can hold the capacity of a class.
+//
+// Classes without room (roomId 0) are not validated.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: roomId int - ID of the room of the class.
+// param: capacity int - Capacity of the class.
+//
+// @return error - Error if the room does not exist or its maximum capacity is exceeded.
+func (c *classesUseCases) validateRoomCapacity(ctx context.Context, roomId int, capacity int) error {
+	if roomId == 0 {
+		return nil
+	}
+
+	room, err := c.roomsRep.GetById(ctx, roomId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return roomNotFoundError()
 		}
+
+		return err
+	}
+
+	if capacity > room.MaxCapacity {
+		return utils.E(http.StatusUnprocessableEntity,
+			nil,
+			map[string]string{"message": "Room Capacity Exceeded"},
+			fmt.Sprintf("Room %s holds at most %d people", room.Name, room.MaxCapacity),
+			"Lower the class capacity or choose a bigger room.")
 	}
 
-	// Update reserved days in the sync.Map
-	for _, class := range availableDays {
-		info.days = append(info.days, class.Date)
+	return nil
+}
+
+// validateInstructor checks that the instructor assigned to a class exists.
+//
+// Classes without instructor (instructorId 0) are not validated.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: instructorId int - ID of the instructor of the class.
+//
+// @return error - Error if the instructor does not exist.
+func (c *classesUseCases) validateInstructor(ctx context.Context, instructorId int) error {
+	if instructorId == 0 {
+		return nil
 	}
 
-	c.reservedDays.Store(key, info)
+	_, err := c.instructorsRep.GetById(ctx, instructorId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return instructorNotFoundError()
+		}
+
+		return err
+	}
 
-	// Return the available days for reservation
-	return availableDays, notPossibleToReserve, nil
+	return nil
 }
 
-// separateClassByYearMonth separates classes by year and month based on the provided class scheduler.
+// findConflicts returns the start of the classes overlapping another scheduled class of their room or instructor.
 //
-// This method takes an api.ClassScheduler struct representing the classes to be scheduled
-// and separates them into a map where the keys are strings representing the year and month (e.g., "2024-03")
-// and the values are slices of api.Class representing the classes scheduled for each month.
-// It returns the map containing the separated classes.
+// The classes of the repository in leaving are moving away from their slots and are not conflicts.
+// The check only reports the overlapping classes, the repository still rejects a class scheduled in the meantime.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: classList []api.Class - Classes to check.
+// param: leaving []api.Class - Classes of the repository moving away from their slots.
+//
+// @return []string - Start, in the RFC3339 format, of every overlapping class.
+// @return error - Error if there is an issue retrieving the scheduled classes.
+func (c *classesUseCases) findConflicts(ctx context.Context, classList []api.Class, leaving []api.Class) ([]string, error) {
+	left := make(map[api.Class]struct{}, len(leaving))
+	for _, class := range leaving {
+		left[class] = struct{}{}
+	}
+
+	schedules := make(map[scheduleBucket][]api.Class)
+	var conflicts []string
+	for _, class := range classList {
+		free := true
+		for _, key := range scheduleKeys(class) {
+			scheduled, loaded := schedules[key]
+			if !loaded {
+				var err error
+				scheduled, err = c.readRep.ListMonthSchedule(ctx, key.filter())
+				if err != nil {
+					return nil, err
+				}
+				schedules[key] = scheduled
+			}
+
+			for _, other := range scheduled {
+				if _, moving := left[other]; !moving && slotOf(other).overlaps(slotOf(class)) {
+					free = false
+				}
+			}
+		}
+
+		if !free {
+			conflicts = append(conflicts, class.Date.Format(time.RFC3339))
+		}
+	}
+
+	return conflicts, nil
+}
+
+// expandClasses returns the classes of the provided class scheduler.
+//
+// This method takes an api.ClassScheduler struct representing the classes to be scheduled and
+// expands its recurrence (one class per day when it has none) between the StartDate and the EndDate.
+// Every class starts at the time of day of the scheduler StartDate.
 //
 // param: base api.ClassScheduler - Struct containing details about the classes to be scheduled.
 //
-// @return map[string][]api.Class - Map where keys represent year and month, and values represent scheduled classes.
-func separateClassByYearMonth(base api.ClassScheduler) map[string][]api.Class {
-	datesMap := make(map[string][]api.Class)
-
-	current := base.StartDate
-	for current.Before(base.EndDate) || current.Equal(base.EndDate) {
-		key := fmt.Sprintf("%d-%02d", current.Year(), current.Month())
-		datesMap[key] = append(datesMap[key], api.Class{
-			Name:     base.Name,
-			Date:     current,
-			Capacity: base.Capacity,
+// @return []api.Class - Classes ordered by date.
+// @return error - Error if the recurrence has no end or expands to too many classes.
+func expandClasses(base api.ClassScheduler) ([]api.Class, error) {
+	rule := recurrence.Rule{Freq: recurrence.Daily, Interval: 1}
+	if base.Recurrence != nil {
+		rule = *base.Recurrence
+	}
+
+	occurrences, err := rule.Expand(base.StartDate, base.EndDate, base.ExDates)
+	if err != nil {
+		return nil, err
+	}
+
+	classList := make([]api.Class, 0, len(occurrences))
+	for _, current := range occurrences {
+		classList = append(classList, api.Class{
+			Name:         base.Name,
+			Type:         base.Type,
+			Date:         current,
+			Duration:     base.Duration,
+			Capacity:     base.Capacity,
+			RoomId:       base.RoomId,
+			InstructorId: base.InstructorId,
 		})
-		current = current.AddDate(0, 0, 1)
 	}
 
-	return datesMap
+	return classList, nil
 }
@@ -5,14 +5,18 @@ package usecases
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"net/http"
 	"sort"
 	"sync"
 	"testing"
 	"time"
 
 	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/recurrence"
+	"github.com/Flgado/fitnessStudioApp/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -20,24 +24,64 @@ import (
 // MockWriteRepository is a mock implementation of WriteRepository for testing purposes.
 type MockWriteRepository struct {
 	values []api.Class
+	read   *mockClassesReadRepository
 	sync.Mutex
 }
 
 // Add mocks the Add method of WriteRepository.
-func (m *MockWriteRepository) Add(ctx context.Context, classes []api.Class) error {
+func (m *MockWriteRepository) Add(ctx context.Context, rrule string, classes []api.Class) ([]api.Class, error) {
 	m.Lock()
 	defer m.Unlock()
-	m.values = append(m.values, classes...)
-	return nil
+	accepted, rejected := m.read.place(classes...)
+	m.values = append(m.values, accepted...)
+	return rejected, nil
 }
 
 func (m *MockWriteRepository) Update(ctx context.Context, classId int, classUpdate api.UpdateClass) (int64, error) {
 	return 2, nil
 }
 
+func (m *MockWriteRepository) UpdateMany(ctx context.Context, updates []api.OccurrenceUpdate) (int64, error) {
+	return int64(len(updates)), nil
+}
+
+func (m *MockWriteRepository) Cancel(ctx context.Context, classIds []int) (int64, error) {
+	return int64(len(classIds)), nil
+}
+
 type mockClassesReadRepository struct {
 	mock.Mock
+	// scheduled are the classes already in the repository
+	scheduled []api.Class
+	mu        sync.Mutex
+}
+
+// place adds classes to the repository like its exclusion constraints do: a class overlapping
+// a scheduled class of the same room, or of the same instructor, is rejected.
+func (m *mockClassesReadRepository) place(classes ...api.Class) ([]api.Class, []api.Class) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var accepted, rejected []api.Class
+	for _, class := range classes {
+		free := true
+		for _, other := range m.scheduled {
+			sameOwner := class.RoomId == other.RoomId || (class.InstructorId != 0 && class.InstructorId == other.InstructorId)
+			if sameOwner && slotOf(class).overlaps(slotOf(other)) {
+				free = false
+				break
+			}
+		}
+		if !free {
+			rejected = append(rejected, class)
+			continue
+		}
+		m.scheduled = append(m.scheduled, class)
+		accepted = append(accepted, class)
+	}
+
+	return accepted, rejected
 }
+
 type ReadRepository interface {
 	List(ctx context.Context, filters api.ClasseFilters) ([]api.ReadClass, error)
 	GetById(ctx context.Context, classId int) (api.ReadClass, error)
@@ -58,13 +102,65 @@ func (m *mockClassesReadRepository) GetById(ctx context.Context, classId int) (a
 	return args.Get(0).(api.ReadClass), args.Error(1)
 }
 
+func (m *mockClassesReadRepository) ListMonthSchedule(ctx context.Context, filter api.ScheduleFilter) ([]api.Class, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var classes []api.Class
+	for _, class := range m.scheduled {
+		sameOwner := class.RoomId == filter.RoomId
+		if filter.InstructorId != 0 {
+			sameOwner = class.InstructorId == filter.InstructorId
+		}
+		if sameOwner && class.Date.Year() == filter.Year && class.Date.Month() == filter.Month {
+			classes = append(classes, class)
+		}
+	}
+
+	return classes, nil
+}
+
+func (m *mockClassesReadRepository) IsTaughtBy(ctx context.Context, classId int, userId int) (bool, error) {
+	return false, nil
+}
+
+func (m *mockClassesReadRepository) ListSeries(ctx context.Context, seriesId int, from time.Time) ([]api.ReadClass, error) {
+	args := m.Called(ctx, seriesId, from)
+	return args.Get(0).([]api.ReadClass), args.Error(1)
+}
+
+// mockClassesWriteRepository places the classes it does not reject in the schedule of read.
 type mockClassesWriteRepository struct {
 	mock.Mock
+	read *mockClassesReadRepository
 }
 
-func (m *mockClassesWriteRepository) Add(ctx context.Context, classes []api.Class) error {
-	args := m.Called(ctx, classes)
-	return args.Error(0)
+// Add creates the series 1 for every scheduler request.
+func (m *mockClassesWriteRepository) Add(ctx context.Context, rrule string, classes []api.Class) ([]api.Class, error) {
+	series := make([]api.Class, 0, len(classes))
+	for _, class := range classes {
+		class.SeriesId = 1
+		series = append(series, class)
+	}
+	classes = series
+
+	args := m.Called(ctx, rrule, classes)
+	rejected, _ := args.Get(0).([]api.Class)
+	if args.Error(1) != nil {
+		return rejected, args.Error(1)
+	}
+
+	var candidates []api.Class
+	for _, class := range classes {
+		accepted := true
+		for _, r := range rejected {
+			accepted = accepted && r != class
+		}
+		if accepted {
+			candidates = append(candidates, class)
+		}
+	}
+	_, overlapping := m.read.place(candidates...)
+	return append(rejected, overlapping...), nil
 }
 
 func (m *mockClassesWriteRepository) Update(ctx context.Context, classId int, updateClass api.UpdateClass) (int64, error) {
@@ -72,11 +168,49 @@ func (m *mockClassesWriteRepository) Update(ctx context.Context, classId int, up
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *mockClassesWriteRepository) UpdateMany(ctx context.Context, updates []api.OccurrenceUpdate) (int64, error) {
+	args := m.Called(ctx, updates)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockClassesWriteRepository) Cancel(ctx context.Context, classIds []int) (int64, error) {
+	args := m.Called(ctx, classIds)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type mockRoomsReadRepository struct {
+	mock.Mock
+}
+
+func (m *mockRoomsReadRepository) List(ctx context.Context) ([]api.Room, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]api.Room), args.Error(1)
+}
+
+func (m *mockRoomsReadRepository) GetById(ctx context.Context, roomId int) (api.Room, error) {
+	args := m.Called(ctx, roomId)
+	return args.Get(0).(api.Room), args.Error(1)
+}
+
+type mockInstructorsReadRepository struct {
+	mock.Mock
+}
+
+func (m *mockInstructorsReadRepository) List(ctx context.Context) ([]api.Instructor, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]api.Instructor), args.Error(1)
+}
+
+func (m *mockInstructorsReadRepository) GetById(ctx context.Context, instructorId int) (api.Instructor, error) {
+	args := m.Called(ctx, instructorId)
+	return args.Get(0).(api.Instructor), args.Error(1)
+}
+
 func TestCreateClass_Success(t *testing.T) {
 	mockReadRepo := new(mockClassesReadRepository)
-	mockWriteRepo := new(mockClassesWriteRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
 
-	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo)
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), utils.SystemClock)
 
 	classScheduler := api.ClassScheduler{
 		Name:      "Test Class",
@@ -85,7 +219,7 @@ func TestCreateClass_Success(t *testing.T) {
 		Capacity:  10,
 	}
 
-	mockWriteRepo.On("Add", mock.Anything, mock.Anything).Return(nil)
+	mockWriteRepo.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 
 	classes, err := uc.CreateClass(context.Background(), classScheduler)
 
@@ -96,9 +230,9 @@ func TestCreateClass_Success(t *testing.T) {
 
 func TestCreateClass_Error(t *testing.T) {
 	mockReadRepo := new(mockClassesReadRepository)
-	mockWriteRepo := new(mockClassesWriteRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
 
-	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo)
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), utils.SystemClock)
 
 	classScheduler := api.ClassScheduler{
 		Name:      "Test Class",
@@ -107,7 +241,7 @@ func TestCreateClass_Error(t *testing.T) {
 		Capacity:  10,
 	}
 
-	mockWriteRepo.On("Add", mock.Anything, mock.Anything).Return(errors.New("error adding class"))
+	mockWriteRepo.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("error adding class"))
 
 	classes, err := uc.CreateClass(context.Background(), classScheduler)
 
@@ -149,11 +283,11 @@ func compareDates(t *testing.T, expected, actual time.Time) {
 	}
 }
 
-func TestCreateClass_FoundInCacheUnavailabeDay(t *testing.T) {
+func TestCreateClass_UnavailableDay(t *testing.T) {
 	mockReadRepo := new(mockClassesReadRepository)
-	mockWriteRepo := new(mockClassesWriteRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
 
-	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo)
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), utils.SystemClock)
 
 	classScheduler := api.ClassScheduler{
 		Name:      "Test Class",
@@ -162,9 +296,9 @@ func TestCreateClass_FoundInCacheUnavailabeDay(t *testing.T) {
 		Capacity:  10,
 	}
 
-	mockWriteRepo.On("Add", mock.Anything, mock.Anything).Return(nil)
+	mockWriteRepo.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 
-	// will find all days available in cache
+	// both days are free
 	emptyList, err := uc.CreateClass(context.Background(), classScheduler)
 
 	classScheduler2 := api.ClassScheduler{
@@ -184,12 +318,438 @@ func TestCreateClass_FoundInCacheUnavailabeDay(t *testing.T) {
 	assert.Equal(t, classScheduler2.StartDate.Day(), class[0].Date.Day())
 }
 
+func TestCreateClass_SameDayWithoutOverlap(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), utils.SystemClock)
+
+	day := time.Now().AddDate(0, 0, 1)
+	morning := time.Date(day.Year(), day.Month(), day.Day(), 8, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		testName     string
+		scheduler    api.ClassScheduler
+		notScheduled int
+	}{
+		{"morning class", api.ClassScheduler{Name: "Yoga", StartDate: morning, EndDate: morning, Duration: 60, Capacity: 10}, 0},
+		{"starts when the previous ends", api.ClassScheduler{Name: "Pilates", StartDate: morning.Add(time.Hour), EndDate: morning.Add(time.Hour), Duration: 45, Capacity: 10}, 0},
+		{"overlaps the morning class", api.ClassScheduler{Name: "Crossfit", StartDate: morning.Add(30 * time.Minute), EndDate: morning.Add(30 * time.Minute), Duration: 60, Capacity: 10}, 1},
+		{"default duration overlaps the pilates class", api.ClassScheduler{Name: "Spinning", StartDate: morning.Add(time.Hour).Add(-10 * time.Minute), EndDate: morning.Add(time.Hour).Add(-10 * time.Minute), Capacity: 10}, 1},
+		{"evening class", api.ClassScheduler{Name: "Boxing", StartDate: morning.Add(10 * time.Hour), EndDate: morning.Add(10 * time.Hour), Capacity: 10}, 0},
+	}
+
+	mockWriteRepo.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	for _, tc := range testCases {
+		notScheduled, err := uc.CreateClass(context.Background(), tc.scheduler)
+
+		assert.Nil(t, err, tc.testName)
+		assert.Len(t, notScheduled, tc.notScheduled, tc.testName)
+	}
+}
+
+func TestUpdateClass_MoveToOverlappingSlot(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), utils.SystemClock)
+
+	day := time.Now().AddDate(0, 0, 1)
+	morning := time.Date(day.Year(), day.Month(), day.Day(), 8, 0, 0, 0, time.UTC)
+	evening := morning.Add(10 * time.Hour)
+	overlapping := morning.Add(30 * time.Minute)
+	reserved := utils.E(http.StatusNotFound,
+		nil,
+		map[string]string{"message": "Date already reserved"},
+		"The selected date is already reserved.",
+		"Please choose a different date or class.")
+
+	// the exclusion constraints of the repository reject the new time range
+	mockReadRepo.On("GetById", mock.Anything, 2).Return(api.ReadClass{Id: 2, Class: api.Class{Name: "Boxing", Date: evening, Duration: 60, Capacity: 10}}, nil)
+	mockWriteRepo.On("Update", mock.Anything, 2, api.UpdateClass{Date: &overlapping}).Return(int64(0), reserved)
+
+	rows, err := uc.UpdateClass(context.Background(), api.UpdateClass{Date: &overlapping}, 2)
+
+	assert.Equal(t, int64(0), rows)
+	assert.Equal(t, reserved, err)
+	mockWriteRepo.AssertExpectations(t)
+}
+
+func TestCreateClass_SameTimeDifferentRooms(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+	mockRoomsRepo := new(mockRoomsReadRepository)
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, mockRoomsRepo, new(mockInstructorsReadRepository), utils.SystemClock)
+
+	day := time.Now().AddDate(0, 0, 1)
+	morning := time.Date(day.Year(), day.Month(), day.Day(), 8, 0, 0, 0, time.UTC)
+
+	mockRoomsRepo.On("GetById", mock.Anything, 1).Return(api.Room{Id: 1, Name: "Studio A", MaxCapacity: 20}, nil)
+	mockRoomsRepo.On("GetById", mock.Anything, 2).Return(api.Room{Id: 2, Name: "Studio B", MaxCapacity: 20}, nil)
+	mockWriteRepo.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	notScheduled, err := uc.CreateClass(context.Background(), api.ClassScheduler{Name: "Yoga", StartDate: morning, EndDate: morning, Capacity: 10, RoomId: 1})
+	assert.Nil(t, err)
+	assert.Empty(t, notScheduled)
+
+	// same time in another room
+	notScheduled, err = uc.CreateClass(context.Background(), api.ClassScheduler{Name: "Pilates", StartDate: morning, EndDate: morning, Capacity: 10, RoomId: 2})
+	assert.Nil(t, err)
+	assert.Empty(t, notScheduled)
+
+	// same time in the same room
+	notScheduled, err = uc.CreateClass(context.Background(), api.ClassScheduler{Name: "Crossfit", StartDate: morning, EndDate: morning, Capacity: 10, RoomId: 1})
+	assert.Nil(t, err)
+	assert.Len(t, notScheduled, 1)
+	assert.Equal(t, 1, notScheduled[0].RoomId)
+}
+
+func TestCreateClass_RoomValidation(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+	mockRoomsRepo := new(mockRoomsReadRepository)
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, mockRoomsRepo, new(mockInstructorsReadRepository), utils.SystemClock)
+
+	day := time.Now().AddDate(0, 0, 1)
+
+	mockRoomsRepo.On("GetById", mock.Anything, 1).Return(api.Room{Id: 1, Name: "Studio A", MaxCapacity: 20}, nil)
+	mockRoomsRepo.On("GetById", mock.Anything, 9).Return(api.Room{}, sql.ErrNoRows)
+
+	testCases := []struct {
+		testName  string
+		scheduler api.ClassScheduler
+		expected  error
+	}{
+		{"capacity above the room maximum", api.ClassScheduler{Name: "Yoga", StartDate: day, EndDate: day, Capacity: 21, RoomId: 1},
+			utils.E(http.StatusUnprocessableEntity,
+				nil,
+				map[string]string{"message": "Room Capacity Exceeded"},
+				"Room Studio A holds at most 20 people",
+				"Lower the class capacity or choose a bigger room.")},
+		{"unknown room", api.ClassScheduler{Name: "Yoga", StartDate: day, EndDate: day, Capacity: 10, RoomId: 9}, roomNotFoundError()},
+	}
+
+	for _, tc := range testCases {
+		notScheduled, err := uc.CreateClass(context.Background(), tc.scheduler)
+
+		assert.Equal(t, tc.expected, err, tc.testName)
+		assert.Empty(t, notScheduled, tc.testName)
+	}
+
+	mockWriteRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateClass_CapacityAboveRoomMaximum(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+	mockRoomsRepo := new(mockRoomsReadRepository)
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, mockRoomsRepo, new(mockInstructorsReadRepository), utils.SystemClock)
+
+	day := time.Now().AddDate(0, 0, 1)
+
+	mockReadRepo.On("GetById", mock.Anything, 3).Return(api.ReadClass{Id: 3, Class: api.Class{Name: "Yoga", Date: day, Duration: 60, Capacity: 10, RoomId: 1}}, nil)
+	mockRoomsRepo.On("GetById", mock.Anything, 1).Return(api.Room{Id: 1, Name: "Studio A", MaxCapacity: 20}, nil)
+
+	capacity := 25
+	rows, err := uc.UpdateClass(context.Background(), api.UpdateClass{Capacity: &capacity}, 3)
+
+	assert.Equal(t, int64(0), rows)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.(utils.Error).Code)
+	mockWriteRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateClass_InstructorInOverlappingClasses(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+	mockRoomsRepo := new(mockRoomsReadRepository)
+	mockInstructorsRepo := new(mockInstructorsReadRepository)
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, mockRoomsRepo, mockInstructorsRepo, utils.SystemClock)
+
+	day := time.Now().AddDate(0, 0, 1)
+	morning := time.Date(day.Year(), day.Month(), day.Day(), 8, 0, 0, 0, time.UTC)
+
+	mockRoomsRepo.On("GetById", mock.Anything, 1).Return(api.Room{Id: 1, Name: "Studio A", MaxCapacity: 20}, nil)
+	mockRoomsRepo.On("GetById", mock.Anything, 2).Return(api.Room{Id: 2, Name: "Studio B", MaxCapacity: 20}, nil)
+	mockInstructorsRepo.On("GetById", mock.Anything, 7).Return(api.Instructor{Id: 7, Name: "Ana"}, nil)
+	mockInstructorsRepo.On("GetById", mock.Anything, 8).Return(api.Instructor{Id: 8, Name: "Rui"}, nil)
+	mockInstructorsRepo.On("GetById", mock.Anything, 9).Return(api.Instructor{}, sql.ErrNoRows)
+	mockWriteRepo.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	testCases := []struct {
+		testName     string
+		scheduler    api.ClassScheduler
+		notScheduled int
+		expected     error
+	}{
+		{"first class of the instructor", api.ClassScheduler{Name: "Yoga", StartDate: morning, EndDate: morning, Capacity: 10, RoomId: 1, InstructorId: 7}, 0, nil},
+		{"same instructor in another room", api.ClassScheduler{Name: "Pilates", StartDate: morning.Add(30 * time.Minute), EndDate: morning.Add(30 * time.Minute), Capacity: 10, RoomId: 2, InstructorId: 7}, 1, nil},
+		{"another instructor in another room", api.ClassScheduler{Name: "Pilates", StartDate: morning.Add(30 * time.Minute), EndDate: morning.Add(30 * time.Minute), Capacity: 10, RoomId: 2, InstructorId: 8}, 0, nil},
+		{"same instructor after the first class", api.ClassScheduler{Name: "Boxing", StartDate: morning.Add(time.Hour), EndDate: morning.Add(time.Hour), Capacity: 10, RoomId: 1, InstructorId: 7}, 0, nil},
+		{"unknown instructor", api.ClassScheduler{Name: "Boxing", StartDate: morning.Add(5 * time.Hour), EndDate: morning.Add(5 * time.Hour), Capacity: 10, RoomId: 1, InstructorId: 9}, 0, instructorNotFoundError()},
+	}
+
+	for _, tc := range testCases {
+		notScheduled, err := uc.CreateClass(context.Background(), tc.scheduler)
+
+		assert.Equal(t, tc.expected, err, tc.testName)
+		assert.Len(t, notScheduled, tc.notScheduled, tc.testName)
+	}
+}
+
+func TestUpdateClass_UnknownInstructor(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+	mockInstructorsRepo := new(mockInstructorsReadRepository)
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), mockInstructorsRepo, utils.SystemClock)
+
+	day := time.Now().AddDate(0, 0, 1)
+	morning := time.Date(day.Year(), day.Month(), day.Day(), 8, 0, 0, 0, time.UTC)
+
+	mockInstructorsRepo.On("GetById", mock.Anything, 9).Return(api.Instructor{}, sql.ErrNoRows)
+	mockReadRepo.On("GetById", mock.Anything, 2).Return(api.ReadClass{Id: 2, Class: api.Class{Name: "Pilates", Date: morning, Duration: 60, Capacity: 10, RoomId: 2}}, nil)
+
+	instructorId := 9
+	rows, err := uc.UpdateClass(context.Background(), api.UpdateClass{InstructorId: &instructorId}, 2)
+
+	assert.Equal(t, int64(0), rows)
+	assert.Equal(t, instructorNotFoundError(), err)
+	mockWriteRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateClass_WeeklyRecurrence(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), utils.SystemClock)
+
+	// next monday at 18:00
+	day := time.Now().AddDate(0, 0, 1)
+	day = day.AddDate(0, 0, (8-int(day.Weekday()))%7)
+	monday := time.Date(day.Year(), day.Month(), day.Day(), 18, 0, 0, 0, time.UTC)
+
+	var added []api.Class
+	mockWriteRepo.On("Add", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		added = append(added, args.Get(2).([]api.Class)...)
+	}).Return(nil, nil)
+
+	// the class of the second wednesday overlaps an existing class
+	_, err := uc.CreateClass(context.Background(), api.ClassScheduler{Name: "Boxing", StartDate: monday.AddDate(0, 0, 9), EndDate: monday.AddDate(0, 0, 9), Capacity: 10})
+	assert.Nil(t, err)
+	added = nil
+
+	rule, err := recurrence.Parse("FREQ=WEEKLY;BYDAY=MO,WE;COUNT=6")
+	assert.Nil(t, err)
+
+	notScheduled, err := uc.CreateClass(context.Background(), api.ClassScheduler{
+		Name:       "Yoga",
+		StartDate:  monday,
+		Capacity:   10,
+		Recurrence: &rule,
+		ExDates:    []time.Time{monday.AddDate(0, 0, 7)},
+	})
+
+	sort.Slice(added, func(i, j int) bool {
+		return added[i].Date.Before(added[j].Date)
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, notScheduled, 1)
+	assert.Equal(t, monday.AddDate(0, 0, 9), notScheduled[0].Date)
+	// every occurrence but the excluded date reaches the repository, which rejects the overlapping one
+	assert.Len(t, added, 5)
+	for i, expected := range []time.Time{monday, monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 9), monday.AddDate(0, 0, 14), monday.AddDate(0, 0, 16)} {
+		assert.Equal(t, expected, added[i].Date)
+	}
+}
+
+func TestCreateClass_UnboundedRecurrence(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), utils.SystemClock)
+
+	rule, err := recurrence.Parse("FREQ=WEEKLY;BYDAY=MO")
+	assert.Nil(t, err)
+
+	notScheduled, err := uc.CreateClass(context.Background(), api.ClassScheduler{Name: "Yoga", StartDate: time.Now(), Capacity: 10, Recurrence: &rule})
+
+	assert.Empty(t, notScheduled)
+	assert.Equal(t, http.StatusBadRequest, err.(utils.Error).Code)
+	mockWriteRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateClass_RejectedByRepository(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), utils.SystemClock)
+
+	day := time.Now().AddDate(0, 0, 1)
+	evening := time.Date(day.Year(), day.Month(), day.Day(), 18, 0, 0, 0, time.UTC)
+	taken := api.Class{Name: "Yoga", Date: evening.AddDate(0, 0, 1), Duration: 60, Capacity: 10, SeriesId: 1}
+
+	// the second day was scheduled by another instance
+	mockWriteRepo.On("Add", mock.Anything, mock.Anything, mock.Anything).Return([]api.Class{taken}, nil)
+
+	notScheduled, err := uc.CreateClass(context.Background(), api.ClassScheduler{Name: "Yoga", StartDate: evening, EndDate: evening.AddDate(0, 0, 1), Capacity: 10})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []api.Class{taken}, notScheduled)
+}
+
+func TestUpdateSeries_MovesFollowingOccurrences(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), utils.SystemClock)
+
+	day := time.Now().AddDate(0, 0, 1)
+	evening := time.Date(day.Year(), day.Month(), day.Day(), 18, 0, 0, 0, time.UTC)
+
+	var added []api.Class
+	mockWriteRepo.On("Add", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		added = append(added, args.Get(2).([]api.Class)...)
+	}).Return(nil, nil)
+
+	_, err := uc.CreateClass(context.Background(), api.ClassScheduler{Name: "Yoga", StartDate: evening, EndDate: evening.AddDate(0, 0, 2), Capacity: 10})
+	assert.Nil(t, err)
+	// a class at 19:00 on the last day
+	_, err = uc.CreateClass(context.Background(), api.ClassScheduler{Name: "Boxing", StartDate: evening.AddDate(0, 0, 2).Add(time.Hour), EndDate: evening.AddDate(0, 0, 2).Add(time.Hour), Capacity: 10})
+	assert.Nil(t, err)
+
+	sort.Slice(added, func(i, j int) bool {
+		return added[i].Date.Before(added[j].Date)
+	})
+	assert.Equal(t, 1, added[0].SeriesId)
+
+	// update from the second occurrence
+	series := []api.ReadClass{{Id: 2, Class: added[1]}, {Id: 3, Class: added[2]}}
+	mockReadRepo.On("GetById", mock.Anything, 2).Return(api.ReadClass{Id: 2, Class: added[1]}, nil)
+	mockReadRepo.On("ListSeries", mock.Anything, 1, added[1].Date).Return(series, nil)
+
+	startTime := 19 * time.Hour
+	rows, err := uc.UpdateSeries(context.Background(), api.UpdateSeries{StartTime: &startTime}, 2)
+
+	assert.Equal(t, int64(0), rows)
+	assert.Equal(t, http.StatusNotFound, err.(utils.Error).Code)
+	mockWriteRepo.AssertNotCalled(t, "UpdateMany", mock.Anything, mock.Anything)
+
+	// moving to 20:00 does not overlap the boxing class
+	startTime = 20 * time.Hour
+	first := evening.AddDate(0, 0, 1).Add(2 * time.Hour)
+	second := evening.AddDate(0, 0, 2).Add(2 * time.Hour)
+	mockWriteRepo.On("UpdateMany", mock.Anything, []api.OccurrenceUpdate{
+		{ClassId: 2, Update: api.UpdateClass{Date: &first}},
+		{ClassId: 3, Update: api.UpdateClass{Date: &second}},
+	}).Return(int64(2), nil)
+
+	rows, err = uc.UpdateSeries(context.Background(), api.UpdateSeries{StartTime: &startTime}, 2)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), rows)
+	mockWriteRepo.AssertExpectations(t)
+}
+
+func TestUpdateSeries_OverlapsItsOwnSlots(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), utils.SystemClock)
+
+	day := time.Now().AddDate(0, 0, 1)
+	evening := time.Date(day.Year(), day.Month(), day.Day(), 18, 0, 0, 0, time.UTC)
+	yoga := api.Class{Name: "Yoga", Date: evening, Duration: 60, Capacity: 10, SeriesId: 1}
+	nextYoga := api.Class{Name: "Yoga", Date: evening.AddDate(0, 0, 1), Duration: 60, Capacity: 10, SeriesId: 1}
+
+	mockWriteRepo.On("Add", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	_, err := uc.CreateClass(context.Background(), api.ClassScheduler{Name: "Yoga", StartDate: evening, EndDate: evening.AddDate(0, 0, 1), Capacity: 10})
+	assert.Nil(t, err)
+
+	startTime := 18*time.Hour + 30*time.Minute
+	first := evening.Add(30 * time.Minute)
+	second := evening.AddDate(0, 0, 1).Add(30 * time.Minute)
+	mockReadRepo.On("GetById", mock.Anything, 1).Return(api.ReadClass{Id: 1, Class: yoga}, nil)
+	mockReadRepo.On("ListSeries", mock.Anything, 1, evening).Return([]api.ReadClass{{Id: 1, Class: yoga}, {Id: 2, Class: nextYoga}}, nil)
+	mockWriteRepo.On("UpdateMany", mock.Anything, []api.OccurrenceUpdate{
+		{ClassId: 1, Update: api.UpdateClass{Date: &first}},
+		{ClassId: 2, Update: api.UpdateClass{Date: &second}},
+	}).Return(int64(2), nil)
+
+	// the occurrences overlap their own previous slots, still in the repository
+	rows, err := uc.UpdateSeries(context.Background(), api.UpdateSeries{StartTime: &startTime}, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), rows)
+	mockWriteRepo.AssertExpectations(t)
+}
+
+func TestCancelSeries(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), utils.SystemClock)
+
+	day := time.Now().AddDate(0, 0, 1)
+	evening := time.Date(day.Year(), day.Month(), day.Day(), 18, 0, 0, 0, time.UTC)
+	first := api.Class{Name: "Yoga", Date: evening, Duration: 60, Capacity: 10, SeriesId: 1}
+	second := api.Class{Name: "Yoga", Date: evening.AddDate(0, 0, 1), Duration: 60, Capacity: 10, SeriesId: 1}
+
+	mockReadRepo.On("GetById", mock.Anything, 1).Return(api.ReadClass{Id: 1, Class: first, Status: api.ClassStatusScheduled}, nil)
+	mockReadRepo.On("GetById", mock.Anything, 5).Return(api.ReadClass{Id: 5, Class: api.Class{Name: "Single", Date: evening}}, nil)
+	mockReadRepo.On("ListSeries", mock.Anything, 1, evening).Return([]api.ReadClass{{Id: 1, Class: first}, {Id: 2, Class: second}}, nil)
+	mockWriteRepo.On("Cancel", mock.Anything, []int{1, 2}).Return(int64(2), nil)
+
+	rows, err := uc.CancelSeries(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), rows)
+
+	_, err = uc.CancelSeries(context.Background(), 5)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.(utils.Error).Code)
+	mockWriteRepo.AssertExpectations(t)
+}
+
+func TestCancelClass(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), utils.SystemClock)
+
+	day := time.Now().AddDate(0, 0, 1)
+	evening := time.Date(day.Year(), day.Month(), day.Day(), 18, 0, 0, 0, time.UTC)
+	yoga := api.Class{Name: "Yoga", Date: evening, Duration: 60, Capacity: 10, SeriesId: 1}
+
+	mockWriteRepo.On("Cancel", mock.Anything, []int{1}).Return(int64(1), nil).Once()
+	mockReadRepo.On("GetById", mock.Anything, 1).Return(api.ReadClass{Id: 1, Class: yoga, Status: api.ClassStatusScheduled}, nil).Once()
+	mockReadRepo.On("GetById", mock.Anything, 1).Return(api.ReadClass{Id: 1, Class: yoga, Status: api.ClassStatusCancelled}, nil)
+
+	err := uc.CancelClass(context.Background(), 1)
+	assert.Nil(t, err)
+
+	// a cancelled class can neither be cancelled again nor updated
+	err = uc.CancelClass(context.Background(), 1)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.(utils.Error).Code)
+
+	capacity := 20
+	_, err = uc.UpdateClass(context.Background(), api.UpdateClass{Capacity: &capacity}, 1)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.(utils.Error).Code)
+	mockWriteRepo.AssertExpectations(t)
+	mockWriteRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestClassesUseCases_CreateClassThreadSafe(t *testing.T) {
 	// Initialize your use case with a mock WriteRepository
-	mockWriteRepo := &MockWriteRepository{}
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &MockWriteRepository{read: mockReadRepo}
 	useCase := classesUseCases{
-		wrRep:        mockWriteRepo,
-		reservedDays: sync.Map{},
+		readRep: mockReadRepo,
+		wrRep:   mockWriteRepo,
+		clock:   utils.SystemClock,
 	}
 
 	data := []api.ClassScheduler{
@@ -246,3 +806,66 @@ func TestClassesUseCases_CreateClassThreadSafe(t *testing.T) {
 		uniqueDates[date] = struct{}{}
 	}
 }
+
+func TestCancelClass_Started(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+
+	now := time.Date(2030, time.March, 17, 10, 0, 0, 0, time.UTC)
+	clock := utils.ClockFunc(func() time.Time { return now })
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), clock)
+
+	inProgress := api.Class{Name: "Yoga", Date: now.Add(-30 * time.Minute), Duration: 60, Capacity: 10, SeriesId: 1}
+	finished := api.Class{Name: "Pilates", Date: now.Add(-2 * time.Hour), Duration: 60, Capacity: 10}
+	mockReadRepo.On("GetById", mock.Anything, 1).Return(api.ReadClass{Id: 1, Class: inProgress, Status: api.ClassStatusScheduled}, nil)
+	mockReadRepo.On("GetById", mock.Anything, 2).Return(api.ReadClass{Id: 2, Class: finished, Status: api.ClassStatusScheduled}, nil)
+
+	testCases := []struct {
+		testName string
+		cancel   func() error
+		message  string
+	}{
+		{"class in progress", func() error { return uc.CancelClass(context.Background(), 1) }, "Class In Progress"},
+		{"finished class", func() error { return uc.CancelClass(context.Background(), 2) }, "Class Already Finished"},
+		{"series from a class in progress", func() error {
+			_, err := uc.CancelSeries(context.Background(), 1)
+			return err
+		}, "Class In Progress"},
+	}
+
+	for _, tc := range testCases {
+		err := tc.cancel()
+
+		e, ok := err.(utils.Error)
+		assert.True(t, ok, tc.testName)
+		assert.Equal(t, http.StatusUnprocessableEntity, e.StatusCode(), tc.testName)
+		assert.Equal(t, tc.message, e.Message()["message"], tc.testName)
+	}
+
+	mockWriteRepo.AssertNotCalled(t, "Cancel", mock.Anything, mock.Anything)
+}
+
+func TestUpdateSeries_SkipsStartedOccurrences(t *testing.T) {
+	mockReadRepo := new(mockClassesReadRepository)
+	mockWriteRepo := &mockClassesWriteRepository{read: mockReadRepo}
+
+	now := time.Date(2030, time.March, 17, 10, 0, 0, 0, time.UTC)
+	clock := utils.ClockFunc(func() time.Time { return now })
+	uc := NewClassesUseCases(mockReadRepo, mockWriteRepo, new(mockRoomsReadRepository), new(mockInstructorsReadRepository), clock)
+
+	inProgress := api.Class{Name: "Yoga", Date: now.Add(-30 * time.Minute), Duration: 60, Capacity: 10, SeriesId: 1}
+	next := api.Class{Name: "Yoga", Date: inProgress.Date.AddDate(0, 0, 1), Duration: 60, Capacity: 10, SeriesId: 1}
+	name := "Hatha Yoga"
+
+	// the occurrences are listed from now, leaving out the class in progress
+	mockReadRepo.On("GetById", mock.Anything, 1).Return(api.ReadClass{Id: 1, Class: inProgress, Status: api.ClassStatusScheduled}, nil)
+	mockReadRepo.On("ListSeries", mock.Anything, 1, now).Return([]api.ReadClass{{Id: 2, Class: next}}, nil)
+	mockWriteRepo.On("UpdateMany", mock.Anything, []api.OccurrenceUpdate{{ClassId: 2, Update: api.UpdateClass{Name: &name}}}).Return(int64(1), nil)
+
+	rows, err := uc.UpdateSeries(context.Background(), api.UpdateSeries{Name: &name}, 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), rows)
+	mockReadRepo.AssertExpectations(t)
+	mockWriteRepo.AssertExpectations(t)
+}
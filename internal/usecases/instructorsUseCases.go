@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/database/instructors"
+	"github.com/Flgado/fitnessStudioApp/utils"
+)
+
+type InstructorsUseCases interface {
+	GetAllInstructors(ctx context.Context) ([]api.Instructor, error)
+	GetInstructorById(ctx context.Context, instructorId int) (api.Instructor, error)
+	CreateInstructor(ctx context.Context, instructor api.CreateInstructor) error
+	UpdateInstructor(ctx context.Context, instructor api.PatchInstructor) (int64, error)
+}
+
+type instructorsUseCases struct {
+	readRep  instructors.ReadRepository
+	writeRep instructors.WriteRepository
+}
+
+func NewInstructorsUseCases(readRep instructors.ReadRepository, writeRep instructors.WriteRepository) InstructorsUseCases {
+	return &instructorsUseCases{
+		readRep:  readRep,
+		writeRep: writeRep,
+	}
+}
+
+func (u *instructorsUseCases) GetAllInstructors(ctx context.Context) ([]api.Instructor, error) {
+	return u.readRep.List(ctx)
+}
+
+func (u *instructorsUseCases) GetInstructorById(ctx context.Context, instructorId int) (api.Instructor, error) {
+	instructor, err := u.readRep.GetById(ctx, instructorId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.Instructor{}, instructorNotFoundError()
+		}
+
+		return api.Instructor{}, err
+	}
+
+	return instructor, nil
+}
+
+func (u *instructorsUseCases) CreateInstructor(ctx context.Context, instructor api.CreateInstructor) error {
+	return u.writeRep.Add(ctx, instructor)
+}
+
+func (u *instructorsUseCases) UpdateInstructor(ctx context.Context, instructor api.PatchInstructor) (int64, error) {
+	rows, err := u.writeRep.Update(ctx, instructor)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, instructorNotFoundError()
+		}
+
+		return 0, err
+	}
+
+	return rows, nil
+}
+
+func instructorNotFoundError() error {
+	return utils.E(http.StatusNotFound,
+		nil,
+		map[string]string{"message": "Instructor Not Found"},
+		"The specified instructor does not exist.",
+		"Please provide a valid instructor ID.")
+}
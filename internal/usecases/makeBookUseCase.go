@@ -2,38 +2,62 @@ package usecases
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
 	"github.com/Flgado/fitnessStudioApp/internal/database/booking"
+	"github.com/Flgado/fitnessStudioApp/internal/database/classes"
+	"github.com/Flgado/fitnessStudioApp/internal/policies"
 	"github.com/Flgado/fitnessStudioApp/utils"
 )
 
 type MakeBookUseCase interface {
-	Book(ctx context.Context, userId int, classId int) error
+	Book(ctx context.Context, userId int, classId int) (api.BookingResult, error)
+	Cancel(ctx context.Context, userId int, classId int) (api.CancellationResult, error)
 }
 
 type makeBookUseCase struct {
-	readRep booking.ReadRepository
-	wrRep   booking.WriteRepository
+	readRep      booking.ReadRepository
+	wrRep        booking.WriteRepository
+	classReadRep classes.ReadRepository
+	policies     *policies.Engine
+	clock        utils.Clock
 }
 
-func NewMakeBookUseCase(readRep booking.ReadRepository, wrRep booking.WriteRepository) MakeBookUseCase {
+func NewMakeBookUseCase(readRep booking.ReadRepository, wrRep booking.WriteRepository, classReadRep classes.ReadRepository, policies *policies.Engine, clock utils.Clock) MakeBookUseCase {
 	return &makeBookUseCase{
-		readRep: readRep,
-		wrRep:   wrRep,
+		readRep:      readRep,
+		wrRep:        wrRep,
+		classReadRep: classReadRep,
+		policies:     policies,
+		clock:        clock,
 	}
 }
 
-func (uc *makeBookUseCase) Book(ctx context.Context, userId int, classId int) error {
+// Book reserves a seat of a class for a user, or adds the user to the class waitlist when the class is full.
+//
+// Classes in progress or finished cannot be booked, and the booking must respect the booking
+// window and cutoff of the class policy. The repository checks the start of the class and its
+// policy again on the class locked by the booking transaction.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user.
+// param: classId int - ID of the class.
+//
+// @return api.BookingResult - Booking status, with the waitlist position when the user is waitlisted.
+// @return error - Error if the class cannot be booked.
+func (uc *makeBookUseCase) Book(ctx context.Context, userId int, classId int) (api.BookingResult, error) {
 	reserved, err := uc.readRep.IsClassBookedByUser(ctx, userId, classId)
 
 	if err != nil {
-		return err
+		return api.BookingResult{}, err
 	}
 
 	if reserved {
-		return utils.E(http.StatusConflict,
+		return api.BookingResult{}, utils.E(http.StatusConflict,
 			nil,
 			map[string]string{"message": "Conflict Status"},
 			fmt.Sprintf("Class with Id: %d is already reserved by User with id %d",
@@ -42,11 +66,84 @@ func (uc *makeBookUseCase) Book(ctx context.Context, userId int, classId int) er
 			"Validate user reserved classes")
 	}
 
-	err = uc.wrRep.Add(ctx, userId, classId)
+	class, err := uc.getClass(ctx, classId)
+	if err != nil {
+		return api.BookingResult{}, err
+	}
+
+	now := uc.clock.Now()
+
+	// Cancelled classes are refused by the repository
+	if class.Status == api.ClassStatusScheduled {
+		if err = booking.CheckNotStarted(class.Date, class.Duration, now); err != nil {
+			return api.BookingResult{}, err
+		}
+
+		if err = uc.policies.CheckBooking(class.Class, now); err != nil {
+			return api.BookingResult{}, err
+		}
+	}
+
+	return uc.wrRep.Add(ctx, userId, classId, now, uc.policies.CheckBooking)
+}
+
+// Cancel cancels the booking of a user, or removes the user from the class waitlist.
+//
+// Cancelling a booking within the late cancellation window of the class policy is late:
+// it is recorded, or refused when the policy rejects late cancellations. The booking of a class in
+// progress or finished cannot be cancelled, the repository checks the start of the class again
+// in the cancellation transaction.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user.
+// param: classId int - ID of the class.
+//
+// @return api.CancellationResult - Cancellation, reporting whether it was late.
+// @return error - Error if the booking cannot be cancelled.
+func (uc *makeBookUseCase) Cancel(ctx context.Context, userId int, classId int) (api.CancellationResult, error) {
+	booked, err := uc.readRep.IsClassBookedByUser(ctx, userId, classId)
+	if err != nil {
+		return api.CancellationResult{}, err
+	}
+
+	now := uc.clock.Now()
+	late := false
+	if booked {
+		var class api.ReadClass
+		class, err = uc.getClass(ctx, classId)
+		if err != nil {
+			return api.CancellationResult{}, err
+		}
+
+		if class.Status == api.ClassStatusScheduled {
+			if err = booking.CheckNotStarted(class.Date, class.Duration, now); err != nil {
+				return api.CancellationResult{}, err
+			}
+
+			late, err = uc.policies.CheckCancel(class.Class, now)
+			if err != nil {
+				return api.CancellationResult{}, err
+			}
+		}
+	}
 
+	err = uc.wrRep.Remove(ctx, userId, classId, late, now)
 	if err != nil {
-		return err
+		return api.CancellationResult{}, err
+	}
+
+	return api.CancellationResult{ClassId: classId, UserId: userId, Late: late}, nil
+}
+
+func (uc *makeBookUseCase) getClass(ctx context.Context, classId int) (api.ReadClass, error) {
+	class, err := uc.classReadRep.GetById(ctx, classId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.ReadClass{}, utils.E(http.StatusNotFound,
+			nil,
+			map[string]string{"message": "Class Not Found"},
+			"The specified class does not exist.",
+			"Please provide a valid class ID.")
 	}
 
-	return nil
+	return class, err
 }
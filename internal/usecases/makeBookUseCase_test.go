@@ -8,8 +8,12 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/Flgado/fitnessStudioApp/config"
 	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/database/booking"
+	"github.com/Flgado/fitnessStudioApp/internal/policies"
 	"github.com/Flgado/fitnessStudioApp/internal/usecases"
 	"github.com/Flgado/fitnessStudioApp/utils"
 	"github.com/stretchr/testify/assert"
@@ -35,21 +39,104 @@ func (m *mockBookingReadRepository) IsClassBookedByUser(ctx context.Context, use
 
 type mockBookingWriteRepository struct {
 	mock.Mock
+	// locked is the class read by the booking transaction, given to the check when set
+	locked *api.Class
 }
 
-func (m *mockBookingWriteRepository) Add(ctx context.Context, userId int, classId int) error {
-	args := m.Called(ctx, userId, classId)
+func (m *mockBookingWriteRepository) Add(ctx context.Context, userId int, classId int, now time.Time, check booking.BookingCheck) (api.BookingResult, error) {
+	args := m.Called(ctx, userId, classId, now)
+	if m.locked != nil && check != nil {
+		if err := check(*m.locked, now); err != nil {
+			return api.BookingResult{}, err
+		}
+	}
+	return args.Get(0).(api.BookingResult), args.Error(1)
+}
+
+func (m *mockBookingWriteRepository) Remove(ctx context.Context, userId int, classId int, late bool, now time.Time) error {
+	args := m.Called(ctx, userId, classId, late, now)
 	return args.Error(0)
 }
 
+func (m *mockBookingWriteRepository) CheckIn(ctx context.Context, userId int, classId int, checkedInBy int, at time.Time) error {
+	args := m.Called(ctx, userId, classId, checkedInBy, at)
+	return args.Error(0)
+}
+
+func (m *mockBookingWriteRepository) MarkNoShows(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type mockClassReadRepository struct {
+	mock.Mock
+}
+
+func (m *mockClassReadRepository) List(ctx context.Context, filters api.ClasseFilters) ([]api.ReadClass, error) {
+	return nil, nil
+}
+
+func (m *mockClassReadRepository) GetById(ctx context.Context, classId int) (api.ReadClass, error) {
+	args := m.Called(ctx, classId)
+	return args.Get(0).(api.ReadClass), args.Error(1)
+}
+
+func (m *mockClassReadRepository) GetClassReservations(ctx context.Context, classId int) (int, error) {
+	return 0, nil
+}
+
+func (m *mockClassReadRepository) ListSeries(ctx context.Context, seriesId int, from time.Time) ([]api.ReadClass, error) {
+	return nil, nil
+}
+
+func (m *mockClassReadRepository) IsTaughtBy(ctx context.Context, classId int, userId int) (bool, error) {
+	return false, nil
+}
+
+func (m *mockClassReadRepository) ListMonthSchedule(ctx context.Context, filter api.ScheduleFilter) ([]api.Class, error) {
+	return nil, nil
+}
+
+// testPolicies closes the bookings 15 minutes before the class and counts the cancellations
+// within 12 hours as late, spinning classes reject the late cancellations
+func testPolicies(t *testing.T) *policies.Engine {
+	engine, err := policies.NewEngine(config.Policies{
+		Booking: config.BookingPolicy{
+			OpensBefore:      14 * 24 * time.Hour,
+			ClosesBefore:     15 * time.Minute,
+			LateCancelWithin: 12 * time.Hour,
+		},
+		ClassTypes: map[string]config.PolicyOverride{
+			"spinning": {LateCancel: policies.LateCancelReject},
+		},
+	})
+	assert.NoError(t, err)
+	return engine
+}
+
+// testNow is the current time of the use cases under test
+var testNow = time.Date(2030, time.March, 17, 10, 0, 0, 0, time.UTC)
+
+var testClock = utils.ClockFunc(func() time.Time { return testNow })
+
+// scheduledClass returns a scheduled class of a type starting after the given delay, negative for a started class
+func scheduledClass(classId int, classType string, startsIn time.Duration) api.ReadClass {
+	return api.ReadClass{
+		Id:     classId,
+		Class:  api.Class{Name: "Test", Type: classType, Date: testNow.Add(startsIn), Duration: 60, Capacity: 10},
+		Status: api.ClassStatusScheduled,
+	}
+}
+
 // TestBook_Success tests the Book method when the class is successfully booked
 func TestBook_Success(t *testing.T) {
 	// Initialize mock repositories
 	mockReadRepo := new(mockBookingReadRepository)
 	mockWriteRepo := new(mockBookingWriteRepository)
+	mockClassRepo := new(mockClassReadRepository)
 
 	// Create the use case with mock repositories
-	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo)
+	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo, mockClassRepo, testPolicies(t), testClock)
 
 	// Define test data
 	userId := 123
@@ -57,13 +144,16 @@ func TestBook_Success(t *testing.T) {
 
 	// Set up mock behavior
 	mockReadRepo.On("IsClassBookedByUser", mock.Anything, userId, classId).Return(false, nil)
-	mockWriteRepo.On("Add", mock.Anything, userId, classId).Return(nil)
+	mockClassRepo.On("GetById", mock.Anything, classId).Return(scheduledClass(classId, "", 24*time.Hour), nil)
+	booked := api.BookingResult{ClassId: classId, UserId: userId, Status: api.BookingStatusBooked}
+	mockWriteRepo.On("Add", mock.Anything, userId, classId, testNow).Return(booked, nil)
 
 	// Call the method under test
-	err := uc.Book(context.Background(), userId, classId)
+	result, err := uc.Book(context.Background(), userId, classId)
 
 	// Assertions
 	assert.NoError(t, err)
+	assert.Equal(t, booked, result)
 	mockReadRepo.AssertExpectations(t)
 	mockWriteRepo.AssertExpectations(t)
 }
@@ -73,9 +163,10 @@ func TestBook_Conflict(t *testing.T) {
 	// Initialize mock repositories
 	mockReadRepo := new(mockBookingReadRepository)
 	mockWriteRepo := new(mockBookingWriteRepository)
+	mockClassRepo := new(mockClassReadRepository)
 
 	// Create the use case with mock repositories
-	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo)
+	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo, mockClassRepo, testPolicies(t), testClock)
 
 	// Define test data
 	userId := 123
@@ -85,7 +176,7 @@ func TestBook_Conflict(t *testing.T) {
 	mockReadRepo.On("IsClassBookedByUser", mock.Anything, userId, classId).Return(true, nil)
 
 	// Call the method under test
-	err := uc.Book(context.Background(), userId, classId)
+	_, err := uc.Book(context.Background(), userId, classId)
 
 	// Assertions
 	assert.EqualError(t, err, utils.E(http.StatusConflict, nil, map[string]string{"message": "Conflict Status"},
@@ -99,9 +190,10 @@ func TestBook_RepositoryError(t *testing.T) {
 	// Initialize mock repositories
 	mockReadRepo := new(mockBookingReadRepository)
 	mockWriteRepo := new(mockBookingWriteRepository)
+	mockClassRepo := new(mockClassReadRepository)
 
 	// Create the use case with mock repositories
-	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo)
+	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo, mockClassRepo, testPolicies(t), testClock)
 
 	// Define test data
 	userId := 123
@@ -111,7 +203,7 @@ func TestBook_RepositoryError(t *testing.T) {
 	mockReadRepo.On("IsClassBookedByUser", mock.Anything, userId, classId).Return(false, errors.New("repository error"))
 
 	// Call the method under test
-	err := uc.Book(context.Background(), userId, classId)
+	_, err := uc.Book(context.Background(), userId, classId)
 
 	// Assertions
 	assert.Error(t, err)
@@ -119,3 +211,332 @@ func TestBook_RepositoryError(t *testing.T) {
 
 	mockReadRepo.AssertExpectations(t)
 }
+
+// TestBook_ClassFullJoinsWaitlist tests the Book method when the class is full and the user is waitlisted
+func TestBook_ClassFullJoinsWaitlist(t *testing.T) {
+	// Initialize mock repositories
+	mockReadRepo := new(mockBookingReadRepository)
+	mockWriteRepo := new(mockBookingWriteRepository)
+	mockClassRepo := new(mockClassReadRepository)
+
+	// Create the use case with mock repositories
+	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo, mockClassRepo, testPolicies(t), testClock)
+
+	// Define test data
+	userId := 123
+	classId := 456
+
+	// Set up mock behavior
+	mockReadRepo.On("IsClassBookedByUser", mock.Anything, userId, classId).Return(false, nil)
+	mockClassRepo.On("GetById", mock.Anything, classId).Return(scheduledClass(classId, "", 24*time.Hour), nil)
+	waitlisted := api.BookingResult{ClassId: classId, UserId: userId, Status: api.BookingStatusWaitlisted, WaitlistPosition: 2}
+	mockWriteRepo.On("Add", mock.Anything, userId, classId, testNow).Return(waitlisted, nil)
+
+	// Call the method under test
+	result, err := uc.Book(context.Background(), userId, classId)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, api.BookingStatusWaitlisted, result.Status)
+	assert.Equal(t, 2, result.WaitlistPosition)
+	mockReadRepo.AssertExpectations(t)
+	mockWriteRepo.AssertExpectations(t)
+}
+
+// TestCancel_Success tests the Cancel method when the booking is successfully removed
+func TestCancel_Success(t *testing.T) {
+	// Initialize mock repositories
+	mockReadRepo := new(mockBookingReadRepository)
+	mockWriteRepo := new(mockBookingWriteRepository)
+	mockClassRepo := new(mockClassReadRepository)
+
+	// Create the use case with mock repositories
+	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo, mockClassRepo, testPolicies(t), testClock)
+
+	// Define test data
+	userId := 123
+	classId := 456
+
+	// Set up mock behavior
+	mockReadRepo.On("IsClassBookedByUser", mock.Anything, userId, classId).Return(true, nil)
+	mockClassRepo.On("GetById", mock.Anything, classId).Return(scheduledClass(classId, "", 24*time.Hour), nil)
+	mockWriteRepo.On("Remove", mock.Anything, userId, classId, false, testNow).Return(nil)
+
+	// Call the method under test
+	result, err := uc.Cancel(context.Background(), userId, classId)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.False(t, result.Late)
+	mockReadRepo.AssertExpectations(t)
+	mockWriteRepo.AssertExpectations(t)
+}
+
+// TestCancel_BookingNotFound tests the Cancel method when the user has no booking for the class
+func TestCancel_BookingNotFound(t *testing.T) {
+	// Initialize mock repositories
+	mockReadRepo := new(mockBookingReadRepository)
+	mockWriteRepo := new(mockBookingWriteRepository)
+	mockClassRepo := new(mockClassReadRepository)
+
+	// Create the use case with mock repositories
+	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo, mockClassRepo, testPolicies(t), testClock)
+
+	// Define test data
+	userId := 123
+	classId := 456
+	notFound := utils.E(http.StatusNotFound,
+		nil,
+		map[string]string{"message": "Booking Not Found"},
+		"The specified user has no booking for this class.",
+		"Please provide a valid user ID and class ID.")
+
+	// Set up mock behavior
+	mockReadRepo.On("IsClassBookedByUser", mock.Anything, userId, classId).Return(false, nil)
+	mockWriteRepo.On("Remove", mock.Anything, userId, classId, false, testNow).Return(notFound)
+
+	// Call the method under test
+	_, err := uc.Cancel(context.Background(), userId, classId)
+
+	// Assertions
+	assert.Equal(t, notFound, err)
+	mockWriteRepo.AssertExpectations(t)
+}
+
+// TestBook_BookingClosed tests the Book method when the class starts within the booking cutoff
+func TestBook_BookingClosed(t *testing.T) {
+	// Initialize mock repositories
+	mockReadRepo := new(mockBookingReadRepository)
+	mockWriteRepo := new(mockBookingWriteRepository)
+	mockClassRepo := new(mockClassReadRepository)
+
+	// Create the use case with mock repositories
+	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo, mockClassRepo, testPolicies(t), testClock)
+
+	// Define test data
+	userId := 123
+	classId := 456
+
+	// Set up mock behavior
+	mockReadRepo.On("IsClassBookedByUser", mock.Anything, userId, classId).Return(false, nil)
+	mockClassRepo.On("GetById", mock.Anything, classId).Return(scheduledClass(classId, "", 10*time.Minute), nil)
+
+	// Call the method under test
+	_, err := uc.Book(context.Background(), userId, classId)
+
+	// Assertions
+	e, ok := err.(utils.Error)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, e.StatusCode())
+	assert.Equal(t, policies.RuleBookingCutoff, e.Message()["rule"])
+	mockWriteRepo.AssertNotCalled(t, "Add", mock.Anything, userId, classId, mock.Anything)
+}
+
+// TestBook_BookingClosedOnLockedClass tests the Book method when the class is moved within the booking cutoff
+// before the booking transaction locks it
+func TestBook_BookingClosedOnLockedClass(t *testing.T) {
+	// Initialize mock repositories
+	mockReadRepo := new(mockBookingReadRepository)
+	moved := scheduledClass(456, "", 10*time.Minute).Class
+	mockWriteRepo := &mockBookingWriteRepository{locked: &moved}
+	mockClassRepo := new(mockClassReadRepository)
+
+	// Create the use case with mock repositories
+	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo, mockClassRepo, testPolicies(t), testClock)
+
+	// Define test data
+	userId := 123
+	classId := 456
+
+	// Set up mock behavior
+	mockReadRepo.On("IsClassBookedByUser", mock.Anything, userId, classId).Return(false, nil)
+	mockClassRepo.On("GetById", mock.Anything, classId).Return(scheduledClass(classId, "", 2*time.Hour), nil)
+	mockWriteRepo.On("Add", mock.Anything, userId, classId, testNow).Return(api.BookingResult{}, nil)
+
+	// Call the method under test
+	_, err := uc.Book(context.Background(), userId, classId)
+
+	// Assertions
+	e, ok := err.(utils.Error)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, e.StatusCode())
+	assert.Equal(t, policies.RuleBookingCutoff, e.Message()["rule"])
+	mockWriteRepo.AssertExpectations(t)
+}
+
+// TestBook_BookingNotOpen tests the Book method when the class is beyond the booking window
+func TestBook_BookingNotOpen(t *testing.T) {
+	// Initialize mock repositories
+	mockReadRepo := new(mockBookingReadRepository)
+	mockWriteRepo := new(mockBookingWriteRepository)
+	mockClassRepo := new(mockClassReadRepository)
+
+	// Create the use case with mock repositories
+	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo, mockClassRepo, testPolicies(t), testClock)
+
+	// Define test data
+	userId := 123
+	classId := 456
+
+	// Set up mock behavior
+	mockReadRepo.On("IsClassBookedByUser", mock.Anything, userId, classId).Return(false, nil)
+	mockClassRepo.On("GetById", mock.Anything, classId).Return(scheduledClass(classId, "", 30*24*time.Hour), nil)
+
+	// Call the method under test
+	_, err := uc.Book(context.Background(), userId, classId)
+
+	// Assertions
+	e, ok := err.(utils.Error)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, e.StatusCode())
+	assert.Equal(t, policies.RuleBookingWindow, e.Message()["rule"])
+	mockWriteRepo.AssertNotCalled(t, "Add", mock.Anything, userId, classId, mock.Anything)
+}
+
+// TestCancel_Late tests the Cancel method when the booking is cancelled within the late cancellation window
+func TestCancel_Late(t *testing.T) {
+	// Initialize mock repositories
+	mockReadRepo := new(mockBookingReadRepository)
+	mockWriteRepo := new(mockBookingWriteRepository)
+	mockClassRepo := new(mockClassReadRepository)
+
+	// Create the use case with mock repositories
+	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo, mockClassRepo, testPolicies(t), testClock)
+
+	// Define test data
+	userId := 123
+	classId := 456
+
+	// Set up mock behavior
+	mockReadRepo.On("IsClassBookedByUser", mock.Anything, userId, classId).Return(true, nil)
+	mockClassRepo.On("GetById", mock.Anything, classId).Return(scheduledClass(classId, "", 2*time.Hour), nil)
+	mockWriteRepo.On("Remove", mock.Anything, userId, classId, true, testNow).Return(nil)
+
+	// Call the method under test
+	result, err := uc.Cancel(context.Background(), userId, classId)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.True(t, result.Late)
+	mockWriteRepo.AssertExpectations(t)
+}
+
+// TestCancel_LateRejectedByClassType tests the Cancel method when the class type policy rejects late cancellations
+func TestCancel_LateRejectedByClassType(t *testing.T) {
+	// Initialize mock repositories
+	mockReadRepo := new(mockBookingReadRepository)
+	mockWriteRepo := new(mockBookingWriteRepository)
+	mockClassRepo := new(mockClassReadRepository)
+
+	// Create the use case with mock repositories
+	uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo, mockClassRepo, testPolicies(t), testClock)
+
+	// Define test data
+	userId := 123
+	classId := 456
+
+	// Set up mock behavior
+	mockReadRepo.On("IsClassBookedByUser", mock.Anything, userId, classId).Return(true, nil)
+	mockClassRepo.On("GetById", mock.Anything, classId).Return(scheduledClass(classId, "Spinning", 2*time.Hour), nil)
+
+	// Call the method under test
+	_, err := uc.Cancel(context.Background(), userId, classId)
+
+	// Assertions
+	e, ok := err.(utils.Error)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, e.StatusCode())
+	assert.Equal(t, policies.RuleLateCancellation, e.Message()["rule"])
+	assert.Equal(t, "spinning", e.Message()["policy"])
+	mockWriteRepo.AssertNotCalled(t, "Remove", mock.Anything, userId, classId, mock.Anything, mock.Anything)
+}
+
+// TestBook_ClassStart tests the Book method for past, in progress and future classes
+func TestBook_ClassStart(t *testing.T) {
+	testCases := []struct {
+		testName string
+		startsIn time.Duration
+		message  string
+	}{
+		{"finished class", -2 * time.Hour, "Class Already Finished"},
+		{"class in progress", -30 * time.Minute, "Class In Progress"},
+		{"future class", 2 * time.Hour, ""},
+	}
+
+	for _, tc := range testCases {
+		// Initialize mock repositories
+		mockReadRepo := new(mockBookingReadRepository)
+		mockWriteRepo := new(mockBookingWriteRepository)
+		mockClassRepo := new(mockClassReadRepository)
+
+		// Create the use case with mock repositories
+		uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo, mockClassRepo, testPolicies(t), testClock)
+
+		// Define test data
+		userId := 123
+		classId := 456
+		booked := api.BookingResult{ClassId: classId, UserId: userId, Status: api.BookingStatusBooked}
+
+		// Set up mock behavior
+		mockReadRepo.On("IsClassBookedByUser", mock.Anything, userId, classId).Return(false, nil)
+		mockClassRepo.On("GetById", mock.Anything, classId).Return(scheduledClass(classId, "", tc.startsIn), nil)
+		mockWriteRepo.On("Add", mock.Anything, userId, classId, testNow).Return(booked, nil)
+
+		// Call the method under test
+		result, err := uc.Book(context.Background(), userId, classId)
+
+		// Assertions
+		if tc.message == "" {
+			assert.NoError(t, err, tc.testName)
+			assert.Equal(t, booked, result, tc.testName)
+			mockWriteRepo.AssertExpectations(t)
+			continue
+		}
+
+		e, ok := err.(utils.Error)
+		assert.True(t, ok, tc.testName)
+		assert.Equal(t, http.StatusUnprocessableEntity, e.StatusCode(), tc.testName)
+		assert.Equal(t, tc.message, e.Message()["message"], tc.testName)
+		mockWriteRepo.AssertNotCalled(t, "Add", mock.Anything, userId, classId, mock.Anything)
+	}
+}
+
+// TestCancel_ClassStarted tests the Cancel method for bookings of classes in progress or finished
+func TestCancel_ClassStarted(t *testing.T) {
+	testCases := []struct {
+		testName string
+		startsIn time.Duration
+		message  string
+	}{
+		{"finished class", -2 * time.Hour, "Class Already Finished"},
+		{"class in progress", -30 * time.Minute, "Class In Progress"},
+	}
+
+	for _, tc := range testCases {
+		// Initialize mock repositories
+		mockReadRepo := new(mockBookingReadRepository)
+		mockWriteRepo := new(mockBookingWriteRepository)
+		mockClassRepo := new(mockClassReadRepository)
+
+		// Create the use case with mock repositories
+		uc := usecases.NewMakeBookUseCase(mockReadRepo, mockWriteRepo, mockClassRepo, testPolicies(t), testClock)
+
+		// Define test data
+		userId := 123
+		classId := 456
+
+		// Set up mock behavior
+		mockReadRepo.On("IsClassBookedByUser", mock.Anything, userId, classId).Return(true, nil)
+		mockClassRepo.On("GetById", mock.Anything, classId).Return(scheduledClass(classId, "", tc.startsIn), nil)
+
+		// Call the method under test
+		_, err := uc.Cancel(context.Background(), userId, classId)
+
+		// Assertions
+		e, ok := err.(utils.Error)
+		assert.True(t, ok, tc.testName)
+		assert.Equal(t, http.StatusUnprocessableEntity, e.StatusCode(), tc.testName)
+		assert.Equal(t, tc.message, e.Message()["message"], tc.testName)
+		mockWriteRepo.AssertNotCalled(t, "Remove", mock.Anything, userId, classId, mock.Anything, mock.Anything)
+	}
+}
@@ -0,0 +1,115 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/database/memberships"
+	"github.com/Flgado/fitnessStudioApp/utils"
+)
+
+type MembershipsUseCases interface {
+	GetAllPlans(ctx context.Context) ([]api.Plan, error)
+	CreatePlan(ctx context.Context, plan api.CreatePlan) error
+	GetUserMemberships(ctx context.Context, userId int) ([]api.Membership, error)
+	AssignMembership(ctx context.Context, userId int, planId int, validFrom time.Time) (api.Membership, error)
+}
+
+type membershipsUseCases struct {
+	readRep  memberships.ReadRepository
+	writeRep memberships.WriteRepository
+	clock    utils.Clock
+}
+
+func NewMembershipsUseCases(readRep memberships.ReadRepository, writeRep memberships.WriteRepository, clock utils.Clock) MembershipsUseCases {
+	return &membershipsUseCases{
+		readRep:  readRep,
+		writeRep: writeRep,
+		clock:    clock,
+	}
+}
+
+func (u *membershipsUseCases) GetAllPlans(ctx context.Context) ([]api.Plan, error) {
+	return u.readRep.ListPlans(ctx)
+}
+
+// CreatePlan adds a membership plan to the studio.
+//
+// A credit pack needs a positive number of credits, an unlimited plan has no credits.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: plan api.CreatePlan - Plan to create.
+//
+// @return error - Error if the plan is invalid or cannot be created.
+func (u *membershipsUseCases) CreatePlan(ctx context.Context, plan api.CreatePlan) error {
+	switch plan.Type {
+	case api.PlanTypeUnlimited:
+		if plan.Credits != 0 {
+			return invalidPlanError("Unlimited plans have no credits")
+		}
+	case api.PlanTypeCredits:
+		if plan.Credits <= 0 {
+			return invalidPlanError("Credit packs should have more than zero credits")
+		}
+	default:
+		return invalidPlanError("Plan type should be unlimited or credits")
+	}
+
+	if plan.ValidityDays <= 0 {
+		return invalidPlanError("Plan validity should be higher than zero days")
+	}
+
+	if plan.Price < 0 {
+		return invalidPlanError("Plan price cannot be negative")
+	}
+
+	return u.writeRep.AddPlan(ctx, plan)
+}
+
+func (u *membershipsUseCases) GetUserMemberships(ctx context.Context, userId int) ([]api.Membership, error) {
+	return u.readRep.ListUserMemberships(ctx, userId)
+}
+
+// AssignMembership gives a membership plan to a user.
+//
+// The membership starts at validFrom, or now when validFrom is zero.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user.
+// param: planId int - ID of the plan.
+// param: validFrom time.Time - Start of the validity period.
+//
+// @return api.Membership - The new membership.
+// @return error - Error if the user or the plan does not exist.
+func (u *membershipsUseCases) AssignMembership(ctx context.Context, userId int, planId int, validFrom time.Time) (api.Membership, error) {
+	plan, err := u.readRep.GetPlanById(ctx, planId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.Membership{}, utils.E(http.StatusNotFound,
+				nil,
+				map[string]string{"message": "Plan Not Found"},
+				"The specified plan does not exist.",
+				"Please provide a valid plan ID.")
+		}
+
+		return api.Membership{}, err
+	}
+
+	if validFrom.IsZero() {
+		validFrom = u.clock.Now()
+	}
+
+	return u.writeRep.Assign(ctx, userId, plan, validFrom)
+}
+
+func invalidPlanError(details string) error {
+	return utils.E(http.StatusBadRequest,
+		nil,
+		map[string]string{"message": "BadRequest"},
+		details,
+		"Read our documentation for more details")
+}
@@ -0,0 +1,124 @@
+//go:build unittests
+// +build unittests
+
+package usecases_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockMembershipsReadRepository struct {
+	mock.Mock
+}
+
+func (m *mockMembershipsReadRepository) ListPlans(ctx context.Context) ([]api.Plan, error) {
+	return nil, nil
+}
+
+func (m *mockMembershipsReadRepository) GetPlanById(ctx context.Context, planId int) (api.Plan, error) {
+	args := m.Called(ctx, planId)
+	return args.Get(0).(api.Plan), args.Error(1)
+}
+
+func (m *mockMembershipsReadRepository) ListUserMemberships(ctx context.Context, userId int) ([]api.Membership, error) {
+	return nil, nil
+}
+
+type mockMembershipsWriteRepository struct {
+	mock.Mock
+}
+
+func (m *mockMembershipsWriteRepository) AddPlan(ctx context.Context, plan api.CreatePlan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *mockMembershipsWriteRepository) Assign(ctx context.Context, userId int, plan api.Plan, validFrom time.Time) (api.Membership, error) {
+	args := m.Called(ctx, userId, plan, validFrom)
+	return args.Get(0).(api.Membership), args.Error(1)
+}
+
+func TestCreatePlan_Validation(t *testing.T) {
+	testCases := []struct {
+		testName string
+		plan     api.CreatePlan
+		valid    bool
+	}{
+		{"credit pack", api.CreatePlan{Name: "10 classes", Type: api.PlanTypeCredits, Credits: 10, ValidityDays: 90}, true},
+		{"unlimited", api.CreatePlan{Name: "Monthly", Type: api.PlanTypeUnlimited, ValidityDays: 30}, true},
+		{"credit pack without credits", api.CreatePlan{Name: "Empty", Type: api.PlanTypeCredits, ValidityDays: 90}, false},
+		{"unlimited with credits", api.CreatePlan{Name: "Odd", Type: api.PlanTypeUnlimited, Credits: 5, ValidityDays: 30}, false},
+		{"unknown type", api.CreatePlan{Name: "Yearly", Type: "yearly", ValidityDays: 365}, false},
+		{"no validity", api.CreatePlan{Name: "Monthly", Type: api.PlanTypeUnlimited}, false},
+	}
+
+	for _, tc := range testCases {
+		// Arrange
+		mockWriteRepo := new(mockMembershipsWriteRepository)
+		uc := usecases.NewMembershipsUseCases(new(mockMembershipsReadRepository), mockWriteRepo, testClock)
+		mockWriteRepo.On("AddPlan", mock.Anything, tc.plan).Return(nil)
+
+		// Act
+		err := uc.CreatePlan(context.Background(), tc.plan)
+
+		// Assert
+		if tc.valid {
+			assert.NoError(t, err, tc.testName)
+			mockWriteRepo.AssertExpectations(t)
+			continue
+		}
+
+		e, ok := err.(utils.Error)
+		assert.True(t, ok, tc.testName)
+		assert.Equal(t, http.StatusBadRequest, e.StatusCode(), tc.testName)
+		mockWriteRepo.AssertNotCalled(t, "AddPlan", mock.Anything, tc.plan)
+	}
+}
+
+func TestAssignMembership_StartsNowByDefault(t *testing.T) {
+	// Arrange
+	mockReadRepo := new(mockMembershipsReadRepository)
+	mockWriteRepo := new(mockMembershipsWriteRepository)
+	uc := usecases.NewMembershipsUseCases(mockReadRepo, mockWriteRepo, testClock)
+
+	plan := api.Plan{Id: 1, Name: "Monthly", Type: api.PlanTypeUnlimited, ValidityDays: 30}
+	membership := api.Membership{Id: 1, UserId: 2, PlanId: 1, ValidFrom: testNow, ValidUntil: testNow.AddDate(0, 0, 30)}
+	mockReadRepo.On("GetPlanById", mock.Anything, 1).Return(plan, nil)
+	mockWriteRepo.On("Assign", mock.Anything, 2, plan, testNow).Return(membership, nil)
+
+	// Act
+	result, err := uc.AssignMembership(context.Background(), 2, 1, time.Time{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, membership, result)
+	mockWriteRepo.AssertExpectations(t)
+}
+
+func TestAssignMembership_PlanNotFound(t *testing.T) {
+	// Arrange
+	mockReadRepo := new(mockMembershipsReadRepository)
+	mockWriteRepo := new(mockMembershipsWriteRepository)
+	uc := usecases.NewMembershipsUseCases(mockReadRepo, mockWriteRepo, testClock)
+
+	mockReadRepo.On("GetPlanById", mock.Anything, 9).Return(api.Plan{}, sql.ErrNoRows)
+
+	// Act
+	_, err := uc.AssignMembership(context.Background(), 2, 9, time.Time{})
+
+	// Assert
+	e, ok := err.(utils.Error)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, e.StatusCode())
+	mockWriteRepo.AssertNotCalled(t, "Assign", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,351 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Flgado/fitnessStudioApp/config"
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/database/booking"
+	"github.com/Flgado/fitnessStudioApp/internal/database/classes"
+	"github.com/Flgado/fitnessStudioApp/internal/database/memberships"
+	paymentsdb "github.com/Flgado/fitnessStudioApp/internal/database/payments"
+	"github.com/Flgado/fitnessStudioApp/internal/payments"
+	"github.com/Flgado/fitnessStudioApp/internal/policies"
+	"github.com/Flgado/fitnessStudioApp/utils"
+)
+
+type PaymentsUseCases interface {
+	PayMembership(ctx context.Context, userId int, planId int) (api.Payment, error)
+	PayDropIn(ctx context.Context, userId int, classId int) (api.Payment, error)
+	GetPayment(ctx context.Context, paymentId int) (api.Payment, error)
+	GetUserPayments(ctx context.Context, userId int) ([]api.Payment, error)
+	Capture(ctx context.Context, paymentId int) (api.Payment, error)
+	Refund(ctx context.Context, paymentId int) (api.Payment, error)
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+	ExpireDropIns(ctx context.Context) (int64, error)
+}
+
+// defaultDropInHoldTTL is used when the configuration has no payments.DropInHoldTTL.
+const defaultDropInHoldTTL = 15 * time.Minute
+
+type paymentsUseCases struct {
+	readRep      paymentsdb.ReadRepository
+	writeRep     paymentsdb.WriteRepository
+	planReadRep  memberships.ReadRepository
+	classReadRep classes.ReadRepository
+	policies     *policies.Engine
+	provider     payments.PaymentProvider
+	settings     config.Payments
+	clock        utils.Clock
+}
+
+func NewPaymentsUseCases(readRep paymentsdb.ReadRepository, writeRep paymentsdb.WriteRepository, planReadRep memberships.ReadRepository, classReadRep classes.ReadRepository, policies *policies.Engine, provider payments.PaymentProvider, settings config.Payments, clock utils.Clock) PaymentsUseCases {
+	return &paymentsUseCases{
+		readRep:      readRep,
+		writeRep:     writeRep,
+		planReadRep:  planReadRep,
+		classReadRep: classReadRep,
+		policies:     policies,
+		provider:     provider,
+		settings:     settings,
+		clock:        clock,
+	}
+}
+
+// PayMembership starts the payment of a membership plan at its price.
+//
+// The membership is granted when the payment is captured.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user buying the plan.
+// param: planId int - ID of the plan.
+//
+// @return api.Payment - The pending payment, with the client secret of the provider.
+// @return error - Error if the user or the plan does not exist, or the provider refuses the payment.
+func (u *paymentsUseCases) PayMembership(ctx context.Context, userId int, planId int) (api.Payment, error) {
+	plan, err := u.planReadRep.GetPlanById(ctx, planId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.Payment{}, utils.E(http.StatusNotFound,
+				nil,
+				map[string]string{"message": "Plan Not Found"},
+				"The specified plan does not exist.",
+				"Please provide a valid plan ID.")
+		}
+
+		return api.Payment{}, err
+	}
+
+	payment, err := u.writeRep.AddMembershipPayment(ctx, userId, plan, u.settings.Currency, u.provider.Name())
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	return u.createIntent(ctx, payment)
+}
+
+// PayDropIn starts the payment of a single class at the drop-in price and holds a seat for the user.
+//
+// The class must respect the same rules as a booking: it has not started and the booking window
+// and cutoff of the class policy are respected. The repository checks them again on the locked class.
+// The booking is confirmed when the payment is captured.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user buying the class.
+// param: classId int - ID of the class.
+//
+// @return api.Payment - The pending payment, with the client secret of the provider.
+// @return error - Error if the class cannot be booked or the provider refuses the payment.
+func (u *paymentsUseCases) PayDropIn(ctx context.Context, userId int, classId int) (api.Payment, error) {
+	class, err := u.classReadRep.GetById(ctx, classId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.Payment{}, utils.E(http.StatusNotFound,
+				nil,
+				map[string]string{"message": "Class Not Found"},
+				"The specified class does not exist.",
+				"Please provide a valid class ID.")
+		}
+
+		return api.Payment{}, err
+	}
+
+	now := u.clock.Now()
+
+	// Cancelled classes are refused by the repository
+	if class.Status == api.ClassStatusScheduled {
+		if err = booking.CheckNotStarted(class.Date, class.Duration, now); err != nil {
+			return api.Payment{}, err
+		}
+
+		if err = u.policies.CheckBooking(class.Class, now); err != nil {
+			return api.Payment{}, err
+		}
+	}
+
+	payment, err := u.writeRep.AddDropInPayment(ctx, userId, classId, u.settings.DropInPrice, u.settings.Currency, u.provider.Name(), now, u.policies.CheckBooking)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	return u.createIntent(ctx, payment)
+}
+
+func (u *paymentsUseCases) GetPayment(ctx context.Context, paymentId int) (api.Payment, error) {
+	payment, err := u.readRep.GetById(ctx, paymentId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return api.Payment{}, paymentNotFoundError()
+	}
+
+	return payment, err
+}
+
+func (u *paymentsUseCases) GetUserPayments(ctx context.Context, userId int) ([]api.Payment, error) {
+	return u.readRep.ListUserPayments(ctx, userId)
+}
+
+// Capture collects a pending payment with the provider and delivers what was bought.
+//
+// The payment is locked and what was bought delivered before the provider collects the money, so a
+// refused payment delivers nothing. A drop-in whose seat was released in the meantime fails without being collected.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: paymentId int - ID of the payment.
+//
+// @return api.Payment - The captured payment.
+// @return error - Error if the payment is not pending or the provider cannot capture it.
+func (u *paymentsUseCases) Capture(ctx context.Context, paymentId int) (api.Payment, error) {
+	payment, err := u.GetPayment(ctx, paymentId)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	if payment.Status != api.PaymentStatusPending {
+		return api.Payment{}, paymentsdb.PaymentStatusError(payment, "Only pending payments can be captured.")
+	}
+
+	captured, err := u.writeRep.Capture(ctx, paymentId, u.clock.Now(), u.collectWithProvider)
+	if errors.Is(err, booking.ErrSeatReleased) {
+		if _, err = u.writeRep.Fail(ctx, paymentId, u.clock.Now()); err != nil {
+			return api.Payment{}, err
+		}
+
+		return api.Payment{}, utils.E(http.StatusConflict,
+			nil,
+			map[string]string{"message": "Seat Released"},
+			"The booking of the drop-in was cancelled before its payment was captured, the payment failed.",
+			"Please pay the drop-in again to book the class.")
+	}
+
+	return captured, err
+}
+
+// Refund gives a captured payment back to the user with the provider and takes back what was bought.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: paymentId int - ID of the payment.
+//
+// @return api.Payment - The refunded payment.
+// @return error - Error if the payment is not captured or the provider cannot refund it.
+func (u *paymentsUseCases) Refund(ctx context.Context, paymentId int) (api.Payment, error) {
+	payment, err := u.GetPayment(ctx, paymentId)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	if payment.Status != api.PaymentStatusCaptured {
+		return api.Payment{}, paymentsdb.PaymentStatusError(payment, "Only captured payments can be refunded.")
+	}
+
+	return u.writeRep.Refund(ctx, paymentId, u.clock.Now(), u.refundWithProvider)
+}
+
+// HandleWebhook applies a callback of the provider to the payment it is about.
+//
+// A succeeded payment is captured, confirming its pending booking or granting its membership, and a
+// failed payment releases its pending booking. A succeeded drop-in whose seat was released in the
+// meantime fails and is refunded with the provider, and so is a drop-in already failed because its
+// booking was cancelled or its hold expired. The refund is recorded on the payment, so repeated callbacks
+// change nothing and never refund the user twice. Unknown events are ignored.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: payload []byte - Body of the callback.
+// param: signature string - Signature of the body by the provider.
+//
+// @return error - Error if the callback is not signed by the provider or its payment does not exist.
+func (u *paymentsUseCases) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := u.provider.ParseWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, payments.ErrInvalidSignature) {
+			return utils.E(http.StatusUnauthorized,
+				err,
+				map[string]string{"message": "Invalid Signature"},
+				"The callback is not signed by the payment provider.",
+				"Check the webhook secret of the payment provider.")
+		}
+
+		return utils.E(http.StatusBadRequest,
+			err,
+			map[string]string{"message": "BadRequest"},
+			"Callback body not expected",
+			"Read the documentation of the payment provider")
+	}
+
+	payment, err := u.readRep.GetByProviderRef(ctx, u.provider.Name(), event.Ref)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return paymentNotFoundError()
+		}
+
+		return err
+	}
+
+	switch event.Type {
+	case payments.EventPaymentSucceeded:
+		if payment.Purpose == api.PaymentPurposeDropIn &&
+			(payment.Status == api.PaymentStatusFailed || payment.Status == api.PaymentStatusRefunded) {
+			// The seat is gone, the money collected by the provider goes back to the user once
+			_, err = u.writeRep.RefundFailed(ctx, payment.Id, u.refundWithProvider)
+			return err
+		}
+
+		// The provider already collected the money
+		_, err = u.writeRep.Capture(ctx, payment.Id, u.clock.Now(), nil)
+		if errors.Is(err, booking.ErrSeatReleased) {
+			err = u.refundReleasedSeat(ctx, payment)
+		}
+	case payments.EventPaymentFailed:
+		_, err = u.writeRep.Fail(ctx, payment.Id, u.clock.Now())
+	}
+
+	return err
+}
+
+// ExpireDropIns fails the drop-ins whose payment is still pending after the hold TTL and releases their seats.
+//
+// A payment the provider completes later is refunded when its callback arrives.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+//
+// @return int64 - Number of drop-ins expired.
+// @return error - Error if there is an issue expiring the drop-ins.
+func (u *paymentsUseCases) ExpireDropIns(ctx context.Context) (int64, error) {
+	ttl := u.settings.DropInHoldTTL
+	if ttl <= 0 {
+		ttl = defaultDropInHoldTTL
+	}
+
+	now := u.clock.Now()
+	return u.writeRep.ExpireDropIns(ctx, now.Add(-ttl), now)
+}
+
+// refundReleasedSeat fails a drop-in collected by the provider after its seat was released,
+// and gives its money back.
+func (u *paymentsUseCases) refundReleasedSeat(ctx context.Context, payment api.Payment) error {
+	_, err := u.writeRep.Fail(ctx, payment.Id, u.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = u.writeRep.RefundFailed(ctx, payment.Id, u.refundWithProvider)
+	return err
+}
+
+// collectWithProvider collects the money of a payment with the provider.
+func (u *paymentsUseCases) collectWithProvider(ctx context.Context, payment api.Payment) error {
+	if err := u.provider.Capture(ctx, payment.ProviderRef); err != nil {
+		return providerError(err)
+	}
+
+	return nil
+}
+
+// refundWithProvider gives the money of a payment back to the user with the provider.
+func (u *paymentsUseCases) refundWithProvider(ctx context.Context, payment api.Payment) error {
+	if err := u.provider.Refund(ctx, payment.ProviderRef, payment.Amount); err != nil {
+		return providerError(err)
+	}
+
+	return nil
+}
+
+// createIntent registers a pending payment with the provider. The payment fails if the provider refuses it.
+func (u *paymentsUseCases) createIntent(ctx context.Context, payment api.Payment) (api.Payment, error) {
+	intent, err := u.provider.CreateIntent(ctx, payment)
+	if err != nil {
+		if _, failErr := u.writeRep.Fail(ctx, payment.Id, u.clock.Now()); failErr != nil {
+			return api.Payment{}, failErr
+		}
+
+		return api.Payment{}, providerError(err)
+	}
+
+	err = u.writeRep.SetProviderRef(ctx, payment.Id, intent.Ref)
+	if err != nil {
+		return api.Payment{}, err
+	}
+
+	payment.ProviderRef = intent.Ref
+	payment.ClientSecret = intent.ClientSecret
+	return payment, nil
+}
+
+func paymentNotFoundError() error {
+	return utils.E(http.StatusNotFound,
+		nil,
+		map[string]string{"message": "Payment Not Found"},
+		"The specified payment does not exist.",
+		"Please provide a valid payment ID.")
+}
+
+func providerError(err error) error {
+	return utils.E(http.StatusBadGateway,
+		err,
+		map[string]string{"message": "Payment Provider Error"},
+		"The payment provider could not process the payment.",
+		"Please try again later.")
+}
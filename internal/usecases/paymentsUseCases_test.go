@@ -0,0 +1,319 @@
+//go:build unittests
+// +build unittests
+
+package usecases_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Flgado/fitnessStudioApp/config"
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/database/booking"
+	paymentsdb "github.com/Flgado/fitnessStudioApp/internal/database/payments"
+	"github.com/Flgado/fitnessStudioApp/internal/payments"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockPaymentsReadRepository struct {
+	mock.Mock
+}
+
+func (m *mockPaymentsReadRepository) GetById(ctx context.Context, paymentId int) (api.Payment, error) {
+	args := m.Called(ctx, paymentId)
+	return args.Get(0).(api.Payment), args.Error(1)
+}
+
+func (m *mockPaymentsReadRepository) GetByProviderRef(ctx context.Context, provider string, ref string) (api.Payment, error) {
+	args := m.Called(ctx, provider, ref)
+	return args.Get(0).(api.Payment), args.Error(1)
+}
+
+func (m *mockPaymentsReadRepository) ListUserPayments(ctx context.Context, userId int) ([]api.Payment, error) {
+	return nil, nil
+}
+
+func (m *mockPaymentsReadRepository) IsPaidBy(ctx context.Context, paymentId int, userId int) (bool, error) {
+	return false, nil
+}
+
+type mockPaymentsWriteRepository struct {
+	mock.Mock
+}
+
+func (m *mockPaymentsWriteRepository) AddMembershipPayment(ctx context.Context, userId int, plan api.Plan, currency string, provider string) (api.Payment, error) {
+	args := m.Called(ctx, userId, plan, currency, provider)
+	return args.Get(0).(api.Payment), args.Error(1)
+}
+
+func (m *mockPaymentsWriteRepository) AddDropInPayment(ctx context.Context, userId int, classId int, amount int, currency string, provider string, now time.Time, check booking.BookingCheck) (api.Payment, error) {
+	args := m.Called(ctx, userId, classId, amount, currency, provider, now)
+	return args.Get(0).(api.Payment), args.Error(1)
+}
+
+func (m *mockPaymentsWriteRepository) SetProviderRef(ctx context.Context, paymentId int, ref string) error {
+	args := m.Called(ctx, paymentId, ref)
+	return args.Error(0)
+}
+
+// Capture runs collect when the capture succeeds, like the repository does before committing
+func (m *mockPaymentsWriteRepository) Capture(ctx context.Context, paymentId int, now time.Time, collect paymentsdb.ProviderAction) (api.Payment, error) {
+	args := m.Called(ctx, paymentId, now)
+	payment, err := args.Get(0).(api.Payment), args.Error(1)
+	if err == nil && collect != nil {
+		if err = collect(ctx, payment); err != nil {
+			return api.Payment{}, err
+		}
+	}
+	return payment, err
+}
+
+func (m *mockPaymentsWriteRepository) Fail(ctx context.Context, paymentId int, now time.Time) (api.Payment, error) {
+	args := m.Called(ctx, paymentId)
+	return args.Get(0).(api.Payment), args.Error(1)
+}
+
+// Refund runs refund when the refund succeeds, like the repository does before committing
+func (m *mockPaymentsWriteRepository) Refund(ctx context.Context, paymentId int, now time.Time, refund paymentsdb.ProviderAction) (api.Payment, error) {
+	args := m.Called(ctx, paymentId, now)
+	payment, err := args.Get(0).(api.Payment), args.Error(1)
+	if err == nil {
+		if err = refund(ctx, payment); err != nil {
+			return api.Payment{}, err
+		}
+	}
+	return payment, err
+}
+
+func (m *mockPaymentsWriteRepository) RefundFailed(ctx context.Context, paymentId int, refund paymentsdb.ProviderAction) (api.Payment, error) {
+	args := m.Called(ctx, paymentId)
+	return args.Get(0).(api.Payment), args.Error(1)
+}
+
+func (m *mockPaymentsWriteRepository) ExpireDropIns(ctx context.Context, createdBefore time.Time, now time.Time) (int64, error) {
+	args := m.Called(ctx, createdBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+var testPaymentSettings = config.Payments{Provider: payments.ProviderFake, WebhookSecret: "secret", Currency: "EUR", DropInPrice: 1500}
+
+type paymentsTest struct {
+	readRepo  *mockPaymentsReadRepository
+	writeRepo *mockPaymentsWriteRepository
+	planRepo  *mockMembershipsReadRepository
+	classRepo *mockClassReadRepository
+	provider  *payments.FakeProvider
+	uc        usecases.PaymentsUseCases
+}
+
+func newPaymentsTest(t *testing.T) paymentsTest {
+	pt := paymentsTest{
+		readRepo:  new(mockPaymentsReadRepository),
+		writeRepo: new(mockPaymentsWriteRepository),
+		planRepo:  new(mockMembershipsReadRepository),
+		classRepo: new(mockClassReadRepository),
+		provider:  payments.NewFakeProvider(testPaymentSettings.WebhookSecret),
+	}
+	pt.uc = usecases.NewPaymentsUseCases(pt.readRepo, pt.writeRepo, pt.planRepo, pt.classRepo, testPolicies(t), pt.provider, testPaymentSettings, testClock)
+	return pt
+}
+
+func TestPayDropIn_HoldsSeatAndCreatesIntent(t *testing.T) {
+	// Arrange
+	pt := newPaymentsTest(t)
+	pending := api.Payment{Id: 7, UserId: 1, Purpose: api.PaymentPurposeDropIn, ClassId: 2, Amount: 1500, Currency: "EUR", Status: api.PaymentStatusPending}
+	pt.classRepo.On("GetById", mock.Anything, 2).Return(scheduledClass(2, "", 24*time.Hour), nil)
+	pt.writeRepo.On("AddDropInPayment", mock.Anything, 1, 2, 1500, "EUR", payments.ProviderFake, testNow).Return(pending, nil)
+	pt.writeRepo.On("SetProviderRef", mock.Anything, 7, "fake_pi_7").Return(nil)
+
+	// Act
+	payment, err := pt.uc.PayDropIn(context.Background(), 1, 2)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "fake_pi_7", payment.ProviderRef)
+	assert.NotEmpty(t, payment.ClientSecret)
+	pt.writeRepo.AssertExpectations(t)
+}
+
+func TestPayDropIn_ClassStarted(t *testing.T) {
+	// Arrange
+	pt := newPaymentsTest(t)
+	pt.classRepo.On("GetById", mock.Anything, 2).Return(scheduledClass(2, "", -10*time.Minute), nil)
+
+	// Act
+	_, err := pt.uc.PayDropIn(context.Background(), 1, 2)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, err.(utils.Error).StatusCode())
+	pt.writeRepo.AssertNotCalled(t, "AddDropInPayment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleWebhook(t *testing.T) {
+	pending := api.Payment{Id: 7, Status: api.PaymentStatusPending, ProviderRef: "fake_pi_7"}
+
+	testCases := []struct {
+		testName  string
+		eventType string
+		call      string
+	}{
+		{"succeeded payment is captured", payments.EventPaymentSucceeded, "Capture"},
+		{"failed payment is failed", payments.EventPaymentFailed, "Fail"},
+	}
+
+	for _, tc := range testCases {
+		// Arrange
+		pt := newPaymentsTest(t)
+		pt.readRepo.On("GetByProviderRef", mock.Anything, payments.ProviderFake, "fake_pi_7").Return(pending, nil)
+		pt.writeRepo.On("Capture", mock.Anything, 7, testNow).Return(pending, nil)
+		pt.writeRepo.On("Fail", mock.Anything, 7).Return(pending, nil)
+		payload, signature := pt.provider.SignedEvent(payments.Event{Type: tc.eventType, Ref: "fake_pi_7"})
+
+		// Act
+		err := pt.uc.HandleWebhook(context.Background(), payload, signature)
+
+		// Assert
+		assert.NoError(t, err, tc.testName)
+		pt.writeRepo.AssertNumberOfCalls(t, "Capture", boolToCalls(tc.call == "Capture"))
+		pt.writeRepo.AssertNumberOfCalls(t, "Fail", boolToCalls(tc.call == "Fail"))
+	}
+}
+
+func TestHandleWebhook_InvalidSignature(t *testing.T) {
+	// Arrange
+	pt := newPaymentsTest(t)
+	payload, _ := pt.provider.SignedEvent(payments.Event{Type: payments.EventPaymentSucceeded, Ref: "fake_pi_7"})
+
+	// Act
+	err := pt.uc.HandleWebhook(context.Background(), payload, "forged")
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, err.(utils.Error).StatusCode())
+	pt.writeRepo.AssertNotCalled(t, "Capture", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCapture_SeatReleasedFails(t *testing.T) {
+	// Arrange
+	pt := newPaymentsTest(t)
+	pending := api.Payment{Id: 7, Purpose: api.PaymentPurposeDropIn, Amount: 1500, Status: api.PaymentStatusPending, ProviderRef: "fake_pi_7"}
+	pt.readRepo.On("GetById", mock.Anything, 7).Return(pending, nil)
+	pt.readRepo.On("GetByProviderRef", mock.Anything, payments.ProviderFake, "fake_pi_7").Return(pending, nil)
+	pt.writeRepo.On("Capture", mock.Anything, 7, testNow).Return(api.Payment{}, booking.ErrSeatReleased)
+	pt.writeRepo.On("Fail", mock.Anything, 7).Return(pending, nil)
+	pt.writeRepo.On("RefundFailed", mock.Anything, 7).Return(api.Payment{Id: 7, Status: api.PaymentStatusRefunded}, nil)
+	payload, signature := pt.provider.SignedEvent(payments.Event{Type: payments.EventPaymentSucceeded, Ref: "fake_pi_7"})
+
+	// Act
+	_, err := pt.uc.Capture(context.Background(), 7)
+	err1 := pt.uc.HandleWebhook(context.Background(), payload, signature)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusConflict, err.(utils.Error).StatusCode())
+	assert.Equal(t, "Seat Released", err.(utils.Error).Message()["message"])
+	assert.NoError(t, err1)
+	pt.writeRepo.AssertNumberOfCalls(t, "Fail", 2)
+	// only the callback refunds, the capture never collected the money
+	pt.writeRepo.AssertNumberOfCalls(t, "RefundFailed", 1)
+}
+
+func TestCapture_ProviderRefused(t *testing.T) {
+	// Arrange
+	pt := newPaymentsTest(t)
+	pending := api.Payment{Id: 7, Purpose: api.PaymentPurposeDropIn, Amount: 1500, Status: api.PaymentStatusPending, ProviderRef: "unknown_7"}
+	pt.readRepo.On("GetById", mock.Anything, 7).Return(pending, nil)
+	pt.writeRepo.On("Capture", mock.Anything, 7, testNow).Return(pending, nil)
+
+	// Act
+	_, err := pt.uc.Capture(context.Background(), 7)
+
+	// Assert
+	// the provider collects inside the capture, so its refusal rolls the payment back
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadGateway, err.(utils.Error).StatusCode())
+	pt.writeRepo.AssertNotCalled(t, "Fail", mock.Anything, mock.Anything)
+}
+
+func TestHandleWebhook_FailedDropInIsRefunded(t *testing.T) {
+	testCases := []struct {
+		testName string
+		status   string
+	}{
+		{"expired drop-in", api.PaymentStatusFailed},
+		{"repeated callback", api.PaymentStatusRefunded},
+	}
+
+	for _, tc := range testCases {
+		// Arrange
+		pt := newPaymentsTest(t)
+		dropIn := api.Payment{Id: 7, Purpose: api.PaymentPurposeDropIn, Amount: 1500, Status: tc.status, ProviderRef: "fake_pi_7"}
+		pt.readRepo.On("GetByProviderRef", mock.Anything, payments.ProviderFake, "fake_pi_7").Return(dropIn, nil)
+		pt.writeRepo.On("RefundFailed", mock.Anything, 7).Return(api.Payment{Id: 7, Status: api.PaymentStatusRefunded}, nil)
+		payload, signature := pt.provider.SignedEvent(payments.Event{Type: payments.EventPaymentSucceeded, Ref: "fake_pi_7"})
+
+		// Act
+		err := pt.uc.HandleWebhook(context.Background(), payload, signature)
+
+		// Assert
+		// the repository records the refund and skips the ones already recorded
+		assert.NoError(t, err, tc.testName)
+		pt.writeRepo.AssertNumberOfCalls(t, "RefundFailed", 1)
+		pt.writeRepo.AssertNotCalled(t, "Capture", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestExpireDropIns(t *testing.T) {
+	testCases := []struct {
+		testName      string
+		holdTTL       time.Duration
+		createdBefore time.Time
+	}{
+		{"configured hold", 30 * time.Minute, testNow.Add(-30 * time.Minute)},
+		{"default hold", 0, testNow.Add(-15 * time.Minute)},
+	}
+
+	for _, tc := range testCases {
+		// Arrange
+		pt := newPaymentsTest(t)
+		settings := testPaymentSettings
+		settings.DropInHoldTTL = tc.holdTTL
+		uc := usecases.NewPaymentsUseCases(pt.readRepo, pt.writeRepo, pt.planRepo, pt.classRepo, testPolicies(t), pt.provider, settings, testClock)
+		pt.writeRepo.On("ExpireDropIns", mock.Anything, tc.createdBefore).Return(int64(2), nil)
+
+		// Act
+		rows, err := uc.ExpireDropIns(context.Background())
+
+		// Assert
+		assert.NoError(t, err, tc.testName)
+		assert.Equal(t, int64(2), rows, tc.testName)
+		pt.writeRepo.AssertExpectations(t)
+	}
+}
+
+func TestRefund_OnlyCapturedPayments(t *testing.T) {
+	// Arrange
+	pt := newPaymentsTest(t)
+	pt.readRepo.On("GetById", mock.Anything, 7).Return(api.Payment{Id: 7, Status: api.PaymentStatusPending, ProviderRef: "fake_pi_7"}, nil)
+
+	// Act
+	_, err := pt.uc.Refund(context.Background(), 7)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusConflict, err.(utils.Error).StatusCode())
+	pt.writeRepo.AssertNotCalled(t, "Refund", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func boolToCalls(called bool) int {
+	if called {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/database/rooms"
+	"github.com/Flgado/fitnessStudioApp/utils"
+)
+
+type RoomsUseCases interface {
+	GetAllRooms(ctx context.Context) ([]api.Room, error)
+	GetRoomById(ctx context.Context, roomId int) (api.Room, error)
+	CreateRoom(ctx context.Context, room api.CreateRoom) error
+	UpdateRoom(ctx context.Context, room api.PatchRoom) (int64, error)
+}
+
+type roomsUseCases struct {
+	readRep  rooms.ReadRepository
+	writeRep rooms.WriteRepository
+}
+
+func NewRoomsUseCases(readRep rooms.ReadRepository, writeRep rooms.WriteRepository) RoomsUseCases {
+	return &roomsUseCases{
+		readRep:  readRep,
+		writeRep: writeRep,
+	}
+}
+
+func (u *roomsUseCases) GetAllRooms(ctx context.Context) ([]api.Room, error) {
+	return u.readRep.List(ctx)
+}
+
+func (u *roomsUseCases) GetRoomById(ctx context.Context, roomId int) (api.Room, error) {
+	room, err := u.readRep.GetById(ctx, roomId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.Room{}, roomNotFoundError()
+		}
+
+		return api.Room{}, err
+	}
+
+	return room, nil
+}
+
+func (u *roomsUseCases) CreateRoom(ctx context.Context, room api.CreateRoom) error {
+	if room.MaxCapacity <= 0 {
+		return invalidRoomCapacityError()
+	}
+
+	return u.writeRep.Add(ctx, room)
+}
+
+func (u *roomsUseCases) UpdateRoom(ctx context.Context, room api.PatchRoom) (int64, error) {
+	if room.MaxCapacity != nil && *room.MaxCapacity <= 0 {
+		return 0, invalidRoomCapacityError()
+	}
+
+	rows, err := u.writeRep.Update(ctx, room)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, roomNotFoundError()
+		}
+
+		return 0, err
+	}
+
+	return rows, nil
+}
+
+func roomNotFoundError() error {
+	return utils.E(http.StatusNotFound,
+		nil,
+		map[string]string{"message": "Room Not Found"},
+		"The specified room does not exist.",
+		"Please provide a valid room ID.")
+}
+
+func invalidRoomCapacityError() error {
+	return utils.E(http.StatusBadRequest,
+		nil,
+		map[string]string{"message": "BadRequest"},
+		"Room maximum capacity should be higher than zero",
+		"Use a valid maximum capacity")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 
 	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
@@ -12,10 +13,12 @@ import (
 )
 
 type UserUseCases interface {
-	GetAllUsers(ctx context.Context) ([]api.User, error)
+	SearchUsers(ctx context.Context, filters api.UserFilters) ([]api.User, int, error)
 	GetUserById(ctx context.Context, userId int) (api.User, error)
-	CreateUser(ctx context.Context, userName string) error
-	UpdateUser(ctx context.Context, user api.User) (int64, error)
+	GetUserByEmail(ctx context.Context, email string) (api.User, error)
+	CreateUser(ctx context.Context, user api.CreateUser) error
+	UpdateUser(ctx context.Context, user api.PatchUser) (int64, error)
+	SetUserRole(ctx context.Context, userId int, role string) error
 }
 
 type userUseCases struct {
@@ -30,8 +33,32 @@ func NewUserUseCase(readRep users.ReadRepository, writeRepo users.WriteRepositor
 	}
 }
 
-func (u *userUseCases) GetAllUsers(ctx context.Context) ([]api.User, error) {
-	return u.readRep.List(ctx)
+// SearchUsers searches the users by name, email and role, a page at a time.
+//
+// The first page of api.UsersDefaultPageSize users sorted by ID is returned when no page is given.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: filters api.UserFilters - Search, sort and page of the users.
+//
+// @return []api.User - Users of the page.
+// @return int - Number of users matching the filters, in all the pages.
+// @return error - Error if there is an issue retrieving the users.
+func (u *userUseCases) SearchUsers(ctx context.Context, filters api.UserFilters) ([]api.User, int, error) {
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+
+	if filters.PageSize < 1 {
+		filters.PageSize = api.UsersDefaultPageSize
+	}
+
+	filters.PageSize = min(filters.PageSize, api.UsersMaxPageSize)
+
+	if filters.SortBy == "" {
+		filters.SortBy = "id"
+	}
+
+	return u.readRep.List(ctx, filters)
 }
 
 func (u *userUseCases) GetUserById(ctx context.Context, userId int) (api.User, error) {
@@ -53,11 +80,35 @@ func (u *userUseCases) GetUserById(ctx context.Context, userId int) (api.User, e
 	return user, err
 }
 
-func (u *userUseCases) CreateUser(ctx context.Context, userName string) error {
-	return u.writeRep.Add(ctx, api.UpdateUser{Name: userName})
+// GetUserByEmail looks up a user by contact email, case insensitive.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: email string - Email of the user.
+//
+// @return api.User - The user.
+// @return error - HTTP 404 error if no user has the email.
+func (u *userUseCases) GetUserByEmail(ctx context.Context, email string) (api.User, error) {
+	user, err := u.readRep.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return api.User{}, utils.E(http.StatusNotFound,
+				nil,
+				map[string]string{"message": "User Not Found"},
+				"No user has the specified email.",
+				"Please provide the email of an existing user.")
+		}
+
+		return api.User{}, err
+	}
+
+	return user, nil
+}
+
+func (u *userUseCases) CreateUser(ctx context.Context, user api.CreateUser) error {
+	return u.writeRep.Add(ctx, user)
 }
 
-func (u *userUseCases) UpdateUser(ctx context.Context, user api.User) (int64, error) {
+func (u *userUseCases) UpdateUser(ctx context.Context, user api.PatchUser) (int64, error) {
 	ur, err := u.writeRep.Update(ctx, user)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -72,3 +123,35 @@ func (u *userUseCases) UpdateUser(ctx context.Context, user api.User) (int64, er
 	}
 	return ur, err
 }
+
+// SetUserRole changes the role of a user, which applies from the next sign in of the user.
+//
+// param: ctx context.Context - Context object for managing the request lifecycle.
+// param: userId int - ID of the user.
+// param: role string - New role: owner, instructor or member.
+//
+// @return error - Error if the role is not valid or the user does not exist.
+func (u *userUseCases) SetUserRole(ctx context.Context, userId int, role string) error {
+	if !api.ValidRole(role) {
+		return utils.E(http.StatusBadRequest,
+			nil,
+			map[string]string{"message": "BadRequest"},
+			fmt.Sprintf("Role %q is not valid", role),
+			fmt.Sprintf("Use one of %s, %s or %s.", api.RoleOwner, api.RoleInstructor, api.RoleMember))
+	}
+
+	rows, err := u.writeRep.SetRole(ctx, userId, role)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return utils.E(http.StatusNotFound,
+			nil,
+			map[string]string{"message": "User Not Found"},
+			"The specified user does not exist.",
+			"Please provide a valid user ID.")
+	}
+
+	return nil
+}
@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/Flgado/fitnessStudioApp/config"
 	_ "github.com/Flgado/fitnessStudioApp/docs"
+	"github.com/Flgado/fitnessStudioApp/handlers"
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
 	dbfactory "github.com/Flgado/fitnessStudioApp/internal/database/dbFactory"
+	"github.com/Flgado/fitnessStudioApp/internal/database/migrations"
+	"github.com/Flgado/fitnessStudioApp/internal/jobs"
+	"github.com/Flgado/fitnessStudioApp/internal/payments"
+	"github.com/Flgado/fitnessStudioApp/internal/policies"
 	"github.com/Flgado/fitnessStudioApp/routes"
 	"github.com/Flgado/fitnessStudioApp/utils"
 	"github.com/go-chi/chi"
@@ -15,6 +25,12 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+// defaultNoShowInterval is used when the configuration has no jobs.NoShowInterval.
+const defaultNoShowInterval = 5 * time.Minute
+
+// defaultDropInExpiryInterval is used when the configuration has no jobs.DropInExpiryInterval.
+const defaultDropInExpiryInterval = time.Minute
+
 // @tittle FitnessStudioApp
 // @version 1
 // @Description "App to book"
@@ -24,8 +40,21 @@ import (
 // @contact.email jfolgado94@gmail.com
 
 // @host localhost:8080
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token from /v1/fitnessstudio/auth/login, sent as "Bearer <access_token>".
+
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key of a machine client, created by an owner at /v1/fitnessstudio/api-keys.
 func main() {
-	configPath := utils.GetConfigPath()
+	profile := flag.String("profile", config.DefaultProfile(), "configuration profile: local, test or prod, "+config.ProfileEnv+" by default")
+	flag.Parse()
+
+	configPath := utils.GetConfigPath(*profile)
 
 	cfgFile, err := config.LoadConfig(configPath)
 	if err != nil {
@@ -39,8 +68,38 @@ func main() {
 
 	portString := cfg.Server.Port
 
-	if portString == "" {
-		log.Fatal("PORT is not found in the conf file")
+	// all the invalid settings are reported at once
+	configErrs := []error{config.ValidateDatabase(cfg), config.ValidateServer(cfg)}
+
+	policyEngine, err := policies.NewEngine(cfg.Policies)
+	if err != nil {
+		configErrs = append(configErrs, fmt.Errorf("invalid booking policies: %w", err))
+	}
+
+	paymentProvider, err := payments.NewProvider(cfg.Payments)
+	configErrs = append(configErrs, err)
+
+	tokens, err := auth.NewTokens(cfg.Auth, utils.SystemClock)
+	configErrs = append(configErrs, err)
+
+	if err := errors.Join(configErrs...); err != nil {
+		log.Fatalf("Invalid configuration of the %s profile:\n%v", *profile, err)
+	}
+
+	// database factory
+	df := dbfactory.NewDBFactory(cfg)
+	dbPoll, err := df.GetDbContext()
+
+	if err != nil {
+		log.Fatalf("Impossible to start database pool connections: Error %s", err)
+	}
+
+	if cfg.Migrations.AutoMigrate {
+		from, to, err := migrations.Migrate(context.Background(), dbPoll.DB)
+		if err != nil {
+			log.Fatalf("Impossible to migrate the database: %v", err)
+		}
+		log.Printf("Database migrated from version %d to %d", from, to)
 	}
 
 	router := chi.NewRouter()
@@ -49,30 +108,56 @@ func main() {
 		AllowedOrigins:   []string{"https://*", "http://*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"*"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", "X-Total-Count"},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
+	router.Use(routes.BuildAPIKeyMiddleware(dbPoll))
 
 	router.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:8080/swagger/doc.json"),
 	))
 
-	// database factory
-	df := dbfactory.NewDBFactory(cfg)
-	dbPoll, err := df.GetDbContext()
-
-	if err != nil {
-		log.Fatalf("Impossible to start database pool connections: Error %s", err)
-	}
-
 	uRoute := routes.BuildUserRoutes(dbPoll)
 	cRoute := routes.BuildClassesRoutes(dbPoll)
-	rRoute := routes.BuildReservationRoutes(dbPoll)
+	rRoute := routes.BuildReservationRoutes(dbPoll, policyEngine)
+	roomsRoute := routes.BuildRoomsRoutes(dbPoll)
+	instructorsRoute := routes.BuildInstructorsRoutes(dbPoll)
+	membershipsRoute := routes.BuildMembershipsRoutes(dbPoll)
+	paymentsRoute := routes.BuildPaymentsRoutes(dbPoll, policyEngine, paymentProvider, cfg.Payments, tokens)
+	authRoute := routes.BuildAuthRoutes(dbPoll, tokens)
+	apiKeysRoute := routes.BuildAPIKeysRoutes(dbPoll)
+
+	router.Mount("/v1/fitnessstudio/auth", authRoute)
+	// payments authenticates its routes itself, its webhook is called by the payment provider
+	router.Mount("/v1/fitnessstudio/payments", paymentsRoute)
+
+	router.Group(func(r chi.Router) {
+		r.Use(handlers.Authenticate(tokens))
+		r.Mount("/v1/fitnessstudio/users", uRoute)
+		r.Mount("/v1/fitnessstudio/classes", cRoute)
+		r.Mount("/v1/fitnessstudio/bookings", rRoute)
+		r.Mount("/v1/fitnessstudio/rooms", roomsRoute)
+		r.Mount("/v1/fitnessstudio/instructors", instructorsRoute)
+		r.Mount("/v1/fitnessstudio/memberships", membershipsRoute)
+		r.Mount("/v1/fitnessstudio/api-keys", apiKeysRoute)
+	})
+
+	// background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	noShowInterval := cfg.Jobs.NoShowInterval
+	if noShowInterval <= 0 {
+		noShowInterval = defaultNoShowInterval
+	}
+	go jobs.Every(jobsCtx, noShowInterval, "mark no-shows", routes.BuildNoShowJob(dbPoll))
 
-	router.Mount("/v1/fitnessstudio/users", uRoute)
-	router.Mount("/v1/fitnessstudio/classes", cRoute)
-	router.Mount("/v1/fitnessstudio/bookings", rRoute)
+	dropInExpiryInterval := cfg.Jobs.DropInExpiryInterval
+	if dropInExpiryInterval <= 0 {
+		dropInExpiryInterval = defaultDropInExpiryInterval
+	}
+	go jobs.Every(jobsCtx, dropInExpiryInterval, "expire unpaid drop-ins", routes.BuildDropInExpiryJob(dbPoll, policyEngine, paymentProvider, cfg.Payments))
 
 	srv := &http.Server{
 		Handler: router,
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/Flgado/fitnessStudioApp/handlers"
+	"github.com/Flgado/fitnessStudioApp/internal/database/apikeys"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/go-chi/chi"
+	"github.com/jmoiron/sqlx"
+)
+
+func BuildAPIKeysRoutes(dbPoll *sqlx.DB) *chi.Mux {
+	// handlers
+	h := handlers.NewAPIKeysHandler(buildAPIKeysUseCases(dbPoll))
+
+	// routes
+	kRouter := chi.NewRouter()
+	kRouter.Use(owners)
+	kRouter.Post("/", h.HandlerCreateAPIKey)
+	kRouter.Get("/", h.HandlerGetAPIKeys)
+	kRouter.Delete("/{keyId}", h.HandlerRevokeAPIKey)
+	return kRouter
+}
+
+// BuildAPIKeyMiddleware builds the middleware identifying the machine clients by their X-API-Key header.
+func BuildAPIKeyMiddleware(dbPoll *sqlx.DB) func(http.Handler) http.Handler {
+	return handlers.APIKeys(buildAPIKeysUseCases(dbPoll))
+}
+
+func buildAPIKeysUseCases(dbPoll *sqlx.DB) usecases.APIKeysUseCases {
+	// repositories
+	readRepo := apikeys.NewReadRepository(dbPoll)
+	wrRepo := apikeys.NewWriteRepository(dbPoll)
+
+	// usecases
+	return usecases.NewAPIKeysUseCases(readRepo, wrRepo, utils.SystemClock)
+}
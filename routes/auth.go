@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/Flgado/fitnessStudioApp/handlers"
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
+	"github.com/Flgado/fitnessStudioApp/internal/database/users"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/go-chi/chi"
+	"github.com/jmoiron/sqlx"
+)
+
+func BuildAuthRoutes(dbPoll *sqlx.DB, tokens *auth.Tokens) *chi.Mux {
+	// repositories
+	readRepo := users.NewReadRepository(dbPoll)
+	wrRepo := users.NewWriteRepository(dbPoll)
+
+	// usecases
+	uc := usecases.NewAuthUseCases(readRepo, wrRepo, tokens)
+
+	// handlers
+	h := handlers.NewAuthHandler(uc)
+
+	// routes
+	aRouter := chi.NewRouter()
+	aRouter.Post("/register", h.HandlerRegister)
+	aRouter.Post("/login", h.HandlerLogin)
+	return aRouter
+}
@@ -3,7 +3,10 @@ package routes
 import (
 	"github.com/Flgado/fitnessStudioApp/handlers"
 	"github.com/Flgado/fitnessStudioApp/internal/database/classes"
+	"github.com/Flgado/fitnessStudioApp/internal/database/instructors"
+	"github.com/Flgado/fitnessStudioApp/internal/database/rooms"
 	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
 	"github.com/go-chi/chi"
 	"github.com/jmoiron/sqlx"
 )
@@ -13,9 +16,11 @@ func BuildClassesRoutes(dbPoll *sqlx.DB) *chi.Mux {
 	// repositories
 	readRepo := classes.NewReadRepository(dbPoll)
 	wrRepo := classes.NewWriteRepository(dbPoll)
+	roomsRepo := rooms.NewReadRepository(dbPoll)
+	instructorsRepo := instructors.NewReadRepository(dbPoll)
 
 	// usecases
-	uc := usecases.NewClassesUseCases(readRepo, wrRepo)
+	uc := usecases.NewClassesUseCases(readRepo, wrRepo, roomsRepo, instructorsRepo, utils.SystemClock)
 
 	// handler
 	h := handlers.NewClassesHandler(uc)
@@ -24,8 +29,11 @@ func BuildClassesRoutes(dbPoll *sqlx.DB) *chi.Mux {
 	cRouter := chi.NewRouter()
 	cRouter.Get("/", h.HandlerGetClasses)
 	cRouter.Get("/{classId}", h.HandlerGetClassById)
-	cRouter.Post("/", h.HandlerAddClass)
-	cRouter.Patch("/", h.HandlerUpdateClass)
+	cRouter.With(owners).Post("/", h.HandlerAddClass)
+	cRouter.With(owners).Patch("/", h.HandlerUpdateClass)
+	cRouter.With(owners).Post("/{classId}/cancel", h.HandlerCancelClass)
+	cRouter.With(owners).Patch("/{classId}/series", h.HandlerUpdateSeries)
+	cRouter.With(owners).Post("/{classId}/series/cancel", h.HandlerCancelSeries)
 
 	return cRouter
 }
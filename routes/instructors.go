@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/Flgado/fitnessStudioApp/handlers"
+	"github.com/Flgado/fitnessStudioApp/internal/database/instructors"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/go-chi/chi"
+	"github.com/jmoiron/sqlx"
+)
+
+func BuildInstructorsRoutes(dbPoll *sqlx.DB) *chi.Mux {
+	// repositories
+	readRepo := instructors.NewReadRepository(dbPoll)
+	wrRepo := instructors.NewWriteRepository(dbPoll)
+
+	// usecases
+	uc := usecases.NewInstructorsUseCases(readRepo, wrRepo)
+
+	// handlers
+	h := handlers.NewInstructorsHandler(uc)
+
+	// routes
+	iRouter := chi.NewRouter()
+	iRouter.Get("/", h.HandlerGetInstructors)
+	iRouter.Get("/{instructorId}", h.HandlerGetInstructorById)
+	iRouter.With(owners).Post("/", h.HandlerCreateInstructor)
+	iRouter.With(owners).Patch("/", h.HandlerUpdateInstructor)
+	return iRouter
+}
@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"context"
+	"log"
+
+	"github.com/Flgado/fitnessStudioApp/config"
+	"github.com/Flgado/fitnessStudioApp/internal/database/booking"
+	"github.com/Flgado/fitnessStudioApp/internal/database/classes"
+	"github.com/Flgado/fitnessStudioApp/internal/database/memberships"
+	paymentsdb "github.com/Flgado/fitnessStudioApp/internal/database/payments"
+	"github.com/Flgado/fitnessStudioApp/internal/payments"
+	"github.com/Flgado/fitnessStudioApp/internal/policies"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+// BuildNoShowJob returns the background job marking the bookings of ended classes without check-in as no-shows.
+func BuildNoShowJob(dbPoll *sqlx.DB) func(ctx context.Context) error {
+	uc := usecases.NewAttendanceUseCase(booking.NewWriteRepository(dbPoll), utils.SystemClock)
+
+	return func(ctx context.Context) error {
+		rows, err := uc.MarkNoShows(ctx)
+		if err != nil {
+			return err
+		}
+
+		if rows != 0 {
+			log.Printf("Marked %d bookings as no-shows", rows)
+		}
+
+		return nil
+	}
+}
+
+// BuildDropInExpiryJob returns the background job failing the drop-ins not paid within their hold TTL.
+func BuildDropInExpiryJob(dbPoll *sqlx.DB, policyEngine *policies.Engine, provider payments.PaymentProvider, settings config.Payments) func(ctx context.Context) error {
+	uc := usecases.NewPaymentsUseCases(paymentsdb.NewReadRepository(dbPoll), paymentsdb.NewWriteRepository(dbPoll),
+		memberships.NewReadRepository(dbPoll), classes.NewReadRepository(dbPoll), policyEngine, provider, settings, utils.SystemClock)
+
+	return func(ctx context.Context) error {
+		rows, err := uc.ExpireDropIns(ctx)
+		if err != nil {
+			return err
+		}
+
+		if rows != 0 {
+			log.Printf("Expired %d unpaid drop-ins", rows)
+		}
+
+		return nil
+	}
+}
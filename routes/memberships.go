@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/Flgado/fitnessStudioApp/handlers"
+	"github.com/Flgado/fitnessStudioApp/internal/database/memberships"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/go-chi/chi"
+	"github.com/jmoiron/sqlx"
+)
+
+func BuildMembershipsRoutes(dbPoll *sqlx.DB) *chi.Mux {
+	// repositories
+	readRepo := memberships.NewReadRepository(dbPoll)
+	wrRepo := memberships.NewWriteRepository(dbPoll)
+
+	// usecases
+	uc := usecases.NewMembershipsUseCases(readRepo, wrRepo, utils.SystemClock)
+
+	// handlers
+	h := handlers.NewMembershipsHandler(uc)
+
+	// routes
+	mRouter := chi.NewRouter()
+	mRouter.Get("/plans", h.HandlerGetPlans)
+	mRouter.With(owners).Post("/plans", h.HandlerCreatePlan)
+	mRouter.With(ownersOrSelf).Get("/users/{userId}", h.HandlerGetUserMemberships)
+	mRouter.With(owners).Post("/users/{userId}", h.HandlerAssignMembership)
+	return mRouter
+}
@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"github.com/Flgado/fitnessStudioApp/config"
+	"github.com/Flgado/fitnessStudioApp/handlers"
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
+	"github.com/Flgado/fitnessStudioApp/internal/database/classes"
+	"github.com/Flgado/fitnessStudioApp/internal/database/memberships"
+	paymentsdb "github.com/Flgado/fitnessStudioApp/internal/database/payments"
+	"github.com/Flgado/fitnessStudioApp/internal/payments"
+	"github.com/Flgado/fitnessStudioApp/internal/policies"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
+	"github.com/go-chi/chi"
+	"github.com/jmoiron/sqlx"
+)
+
+func BuildPaymentsRoutes(dbPoll *sqlx.DB, policyEngine *policies.Engine, provider payments.PaymentProvider, settings config.Payments, tokens *auth.Tokens) *chi.Mux {
+	// repositories
+	readRepo := paymentsdb.NewReadRepository(dbPoll)
+	wrRepo := paymentsdb.NewWriteRepository(dbPoll)
+	plansReadRepo := memberships.NewReadRepository(dbPoll)
+	classesReadRepo := classes.NewReadRepository(dbPoll)
+
+	// usecases
+	uc := usecases.NewPaymentsUseCases(readRepo, wrRepo, plansReadRepo, classesReadRepo, policyEngine, provider, settings, utils.SystemClock)
+
+	// handlers
+	h := handlers.NewPaymentsHandler(uc)
+
+	// routes
+	pRouter := chi.NewRouter()
+
+	// the payment provider signs its callbacks instead of sending a bearer token
+	pRouter.Post("/webhook", h.HandlerPaymentWebhook)
+
+	pRouter.Group(func(r chi.Router) {
+		r.Use(handlers.Authenticate(tokens))
+		r.Post("/memberships", h.HandlerPayMembership)
+		r.Post("/drop-ins", h.HandlerPayDropIn)
+		r.With(ownersOrSelf).Get("/users/{userId}", h.HandlerGetUserPayments)
+		r.With(handlers.Allow(handlers.Role(api.RoleOwner), handlers.Related("paymentId", readRepo.IsPaidBy))).
+			Get("/{paymentId}", h.HandlerGetPayment)
+		r.With(owners).Post("/{paymentId}/capture", h.HandlerCapturePayment)
+		r.With(owners).Post("/{paymentId}/refund", h.HandlerRefundPayment)
+	})
+	return pRouter
+}
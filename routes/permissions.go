@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/Flgado/fitnessStudioApp/handlers"
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+)
+
+// Permissions shared by the routes of the studio, every route is behind handlers.Authenticate.
+var (
+	// owners manage the studio: classes, rooms, instructors, plans and roles
+	owners = handlers.Allow(handlers.Role(api.RoleOwner))
+	// ownersOrSelf lets members use the routes of their own userId
+	ownersOrSelf = handlers.Allow(handlers.Role(api.RoleOwner), handlers.Self("userId"))
+)
+
+// instructorOf grants the routes of a class to the instructor teaching it.
+func instructorOf(teaches handlers.Relation) handlers.Permission {
+	return handlers.All(handlers.Role(api.RoleInstructor), handlers.Related("classId", teaches))
+}
+
+// checkIns marks the attendance of the members of a class: the owners and the instructor of the class.
+// Members cannot check themselves in, the check-in proves that they showed up.
+func checkIns(teaches handlers.Relation) func(http.Handler) http.Handler {
+	return handlers.Allow(handlers.Role(api.RoleOwner), instructorOf(teaches))
+}
@@ -0,0 +1,56 @@
+//go:build unittests
+// +build unittests
+
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+// teaches is a relation where user 9 teaches class 1.
+func teaches(ctx context.Context, classId int, userId int) (bool, error) {
+	return classId == 1 && userId == 9, nil
+}
+
+func TestCheckIns(t *testing.T) {
+	router := chi.NewRouter()
+	router.With(checkIns(teaches)).Post("/users/{userId}/classes/{classId}/checkin", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	testCases := []struct {
+		testName       string
+		path           string
+		caller         auth.Identity
+		expectedStatus int
+	}{
+		{"owner", "/users/7/classes/1/checkin", auth.Identity{UserId: 1, Role: api.RoleOwner}, http.StatusOK},
+		{"instructor of the class", "/users/7/classes/1/checkin", auth.Identity{UserId: 9, Role: api.RoleInstructor}, http.StatusOK},
+		{"instructor of another class", "/users/7/classes/2/checkin", auth.Identity{UserId: 9, Role: api.RoleInstructor}, http.StatusForbidden},
+		{"API key", "/users/7/classes/1/checkin", auth.Identity{UserId: 1, Role: api.RoleOwner, APIKeyId: 5, Scopes: []string{"bookings:write"}}, http.StatusOK},
+		{"API key of a former owner", "/users/7/classes/1/checkin", auth.Identity{UserId: 1, Role: api.RoleMember, APIKeyId: 5, Scopes: []string{"bookings:write"}}, http.StatusForbidden},
+		{"member checking themselves in", "/users/7/classes/1/checkin", auth.Identity{UserId: 7, Role: api.RoleMember}, http.StatusForbidden},
+		{"instructor checking themselves in", "/users/9/classes/2/checkin", auth.Identity{UserId: 9, Role: api.RoleInstructor}, http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		// Arrange
+		req := httptest.NewRequest(http.MethodPost, tc.path, nil)
+		req = req.WithContext(auth.WithIdentity(req.Context(), tc.caller))
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, tc.expectedStatus, w.Code, tc.testName)
+	}
+}
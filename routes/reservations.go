@@ -2,30 +2,41 @@ package routes
 
 import (
 	"github.com/Flgado/fitnessStudioApp/handlers"
+	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
 	"github.com/Flgado/fitnessStudioApp/internal/database/booking"
+	"github.com/Flgado/fitnessStudioApp/internal/database/classes"
+	"github.com/Flgado/fitnessStudioApp/internal/policies"
 	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/Flgado/fitnessStudioApp/utils"
 	"github.com/go-chi/chi"
 	"github.com/jmoiron/sqlx"
 )
 
-func BuildReservationRoutes(dbPoll *sqlx.DB) *chi.Mux {
+func BuildReservationRoutes(dbPoll *sqlx.DB, policyEngine *policies.Engine) *chi.Mux {
 
 	// repositories
 	readRepo := booking.NewReadRepository(dbPoll)
 	wrRepo := booking.NewWriteRepository(dbPoll)
+	classesReadRepo := classes.NewReadRepository(dbPoll)
 
 	// usecases
 	uc := usecases.NewBookUseCase(readRepo, wrRepo)
-	muc := usecases.NewMakeBookUseCase(readRepo, wrRepo)
+	muc := usecases.NewMakeBookUseCase(readRepo, wrRepo, classesReadRepo, policyEngine, utils.SystemClock)
+	auc := usecases.NewAttendanceUseCase(wrRepo, utils.SystemClock)
 
 	// handlers
 	h := handlers.NewBookingInfoHandler(uc)
 	hm := handlers.NewMakeReservationHandler(muc)
+	ha := handlers.NewAttendanceHandler(auc)
 
 	// routes
 	cRouter := chi.NewRouter()
-	cRouter.Get("/users/{userId}/classes", h.HandlerGetUserClasses)
-	cRouter.Get("/classes/{classId}/users", h.HandlerGetClassUsers)
+	teachers := handlers.Allow(handlers.Role(api.RoleOwner), instructorOf(classesReadRepo.IsTaughtBy))
+
+	cRouter.With(ownersOrSelf).Get("/users/{userId}/classes", h.HandlerGetUserClasses)
+	cRouter.With(teachers).Get("/classes/{classId}/users", h.HandlerGetClassUsers)
 	cRouter.Post("/", hm.HandlerCreateBooking)
+	cRouter.With(ownersOrSelf).Delete("/users/{userId}/classes/{classId}", hm.HandlerCancelBooking)
+	cRouter.With(checkIns(classesReadRepo.IsTaughtBy)).Post("/users/{userId}/classes/{classId}/checkin", ha.HandlerCheckIn)
 	return cRouter
 }
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/Flgado/fitnessStudioApp/handlers"
+	"github.com/Flgado/fitnessStudioApp/internal/database/rooms"
+	"github.com/Flgado/fitnessStudioApp/internal/usecases"
+	"github.com/go-chi/chi"
+	"github.com/jmoiron/sqlx"
+)
+
+func BuildRoomsRoutes(dbPoll *sqlx.DB) *chi.Mux {
+	// repositories
+	readRepo := rooms.NewReadRepository(dbPoll)
+	wrRepo := rooms.NewWriteRepository(dbPoll)
+
+	// usecases
+	uc := usecases.NewRoomsUseCases(readRepo, wrRepo)
+
+	// handlers
+	h := handlers.NewRoomsHandler(uc)
+
+	// routes
+	rRouter := chi.NewRouter()
+	rRouter.Get("/", h.HandlerGetRooms)
+	rRouter.Get("/{roomId}", h.HandlerGetRoomById)
+	rRouter.With(owners).Post("/", h.HandlerCreateRoom)
+	rRouter.With(owners).Patch("/", h.HandlerUpdateRoom)
+	return rRouter
+}
@@ -21,9 +21,12 @@ func BuildUserRoutes(dbPoll *sqlx.DB) *chi.Mux {
 
 	// routes
 	uRouter := chi.NewRouter()
-	uRouter.Get("/", h.HandlerGetUsers)
-	uRouter.Get("/{userId}", h.HandlerGetUserById)
-	uRouter.Post("/", h.HandlerCreateUser)
+	uRouter.With(owners).Get("/", h.HandlerGetUsers)
+	uRouter.With(owners).Get("/by-email", h.HandlerGetUserByEmail)
+	uRouter.With(ownersOrSelf).Get("/{userId}", h.HandlerGetUserById)
+	uRouter.With(owners).Post("/", h.HandlerCreateUser)
+	// members can only update their own profile, the user is in the body
 	uRouter.Patch("/", h.HandlerUpdateUser)
+	uRouter.With(handlers.DenyAPIKeys, owners).Put("/{userId}/role", h.HandlerSetUserRole)
 	return uRouter
 }
@@ -12,10 +12,21 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Flgado/fitnessStudioApp/config"
 	api "github.com/Flgado/fitnessStudioApp/internal/api/models"
+	"github.com/Flgado/fitnessStudioApp/internal/auth"
+	"github.com/Flgado/fitnessStudioApp/internal/database/apikeys"
 	"github.com/Flgado/fitnessStudioApp/internal/database/booking"
 	"github.com/Flgado/fitnessStudioApp/internal/database/classes"
+	"github.com/Flgado/fitnessStudioApp/internal/database/instructors"
+	"github.com/Flgado/fitnessStudioApp/internal/database/memberships"
+	"github.com/Flgado/fitnessStudioApp/internal/database/migrations"
+	paymentsdb "github.com/Flgado/fitnessStudioApp/internal/database/payments"
+	"github.com/Flgado/fitnessStudioApp/internal/database/rooms"
 	"github.com/Flgado/fitnessStudioApp/internal/database/users"
+	"github.com/Flgado/fitnessStudioApp/internal/payments"
+	"github.com/Flgado/fitnessStudioApp/internal/policies"
+	"github.com/Flgado/fitnessStudioApp/internal/seed"
 	"github.com/Flgado/fitnessStudioApp/internal/usecases"
 	"github.com/Flgado/fitnessStudioApp/utils"
 	"github.com/jmoiron/sqlx"
@@ -32,7 +43,15 @@ func TestMain(m *testing.M) {
 }
 
 func cleanupUserTableDatabase() {
-	_, err := testDbInstance.Exec("DELETE FROM users")
+	_, err := testDbInstance.Exec("DELETE FROM api_keys")
+	if err != nil {
+		log.Fatalf("Error cleaning up database: %v", err)
+	}
+	_, err = testDbInstance.Exec("DELETE FROM user_credentials")
+	if err != nil {
+		log.Fatalf("Error cleaning up database: %v", err)
+	}
+	_, err = testDbInstance.Exec("DELETE FROM users")
 	if err != nil {
 		log.Fatalf("Error cleaning up database: %v", err)
 	}
@@ -43,10 +62,26 @@ func resetAutoIncrement() {
 	if err != nil {
 		log.Fatalf("Error resetting auto-increment counter: %v", err)
 	}
+	_, err = testDbInstance.Exec("ALTER SEQUENCE class_series_id_seq RESTART WITH 1")
+	if err != nil {
+		log.Fatalf("Error resetting auto-increment counter: %v", err)
+	}
 }
 
 func cleanupClassesTableDatabase() {
-	_, err := testDbInstance.Exec("DELETE FROM classes")
+	_, err := testDbInstance.Exec("DELETE FROM notification_outbox")
+	if err != nil {
+		log.Fatalf("Error cleaning up database: %v", err)
+	}
+	_, err = testDbInstance.Exec("DELETE FROM late_cancellations")
+	if err != nil {
+		log.Fatalf("Error cleaning up database: %v", err)
+	}
+	_, err = testDbInstance.Exec("DELETE FROM classes")
+	if err != nil {
+		log.Fatalf("Error cleaning up database: %v", err)
+	}
+	_, err = testDbInstance.Exec("DELETE FROM class_series")
 	if err != nil {
 		log.Fatalf("Error cleaning up database: %v", err)
 	}
@@ -54,11 +89,88 @@ func cleanupClassesTableDatabase() {
 	resetAutoIncrement()
 }
 
+func cleanupRoomsTableDatabase() {
+	cleanupClassesTableDatabase()
+	_, err := testDbInstance.Exec("DELETE FROM rooms")
+	if err != nil {
+		log.Fatalf("Error cleaning up database: %v", err)
+	}
+	_, err = testDbInstance.Exec("ALTER SEQUENCE rooms_id_seq RESTART WITH 1")
+	if err != nil {
+		log.Fatalf("Error resetting auto-increment counter: %v", err)
+	}
+}
+
+func cleanupInstructorsTableDatabase() {
+	cleanupRoomsTableDatabase()
+	_, err := testDbInstance.Exec("DELETE FROM instructors")
+	if err != nil {
+		log.Fatalf("Error cleaning up database: %v", err)
+	}
+	_, err = testDbInstance.Exec("ALTER SEQUENCE instructors_id_seq RESTART WITH 1")
+	if err != nil {
+		log.Fatalf("Error resetting auto-increment counter: %v", err)
+	}
+}
+
+func cleanupMembershipsTableDatabase() {
+	_, err := testDbInstance.Exec("DELETE FROM user_memberships")
+	if err != nil {
+		log.Fatalf("Error cleaning up database: %v", err)
+	}
+	_, err = testDbInstance.Exec("DELETE FROM membership_plans")
+	if err != nil {
+		log.Fatalf("Error cleaning up database: %v", err)
+	}
+	_, err = testDbInstance.Exec("ALTER SEQUENCE membership_plans_id_seq RESTART WITH 1")
+	if err != nil {
+		log.Fatalf("Error resetting auto-increment counter: %v", err)
+	}
+	_, err = testDbInstance.Exec("ALTER SEQUENCE user_memberships_id_seq RESTART WITH 1")
+	if err != nil {
+		log.Fatalf("Error resetting auto-increment counter: %v", err)
+	}
+}
+
+func cleanupPaymentsTableDatabase() {
+	_, err := testDbInstance.Exec("DELETE FROM payment_ledger")
+	if err != nil {
+		log.Fatalf("Error cleaning up database: %v", err)
+	}
+	_, err = testDbInstance.Exec("DELETE FROM payments")
+	if err != nil {
+		log.Fatalf("Error cleaning up database: %v", err)
+	}
+	_, err = testDbInstance.Exec("ALTER SEQUENCE payments_id_seq RESTART WITH 1")
+	if err != nil {
+		log.Fatalf("Error resetting auto-increment counter: %v", err)
+	}
+}
+
+// addUnlimitedMemberships gives an unlimited membership to every user, so they can book any class.
+func addUnlimitedMemberships() {
+	_, err := testDbInstance.Exec(`INSERT INTO membership_plans (plan_name, plan_type, validity_days) VALUES('Test Unlimited', 'unlimited', 36500)`)
+	if err != nil {
+		log.Fatalf("Error adding memberships: %v", err)
+	}
+	_, err = testDbInstance.Exec(`INSERT INTO user_memberships (user_id, plan_id, valid_from, valid_until)
+	SELECT u.id, p.id, '2000-01-01', '2100-01-01' FROM users u, membership_plans p WHERE p.plan_name = 'Test Unlimited'`)
+	if err != nil {
+		log.Fatalf("Error adding memberships: %v", err)
+	}
+}
+
 func cleanupAllTablesDatabase() {
 	_, err := testDbInstance.Exec("DELETE FROM booking")
 	if err != nil {
 		log.Fatalf("Error cleaning up database: %v", err)
 	}
+	_, err = testDbInstance.Exec("DELETE FROM waitlist")
+	if err != nil {
+		log.Fatalf("Error cleaning up database: %v", err)
+	}
+	cleanupPaymentsTableDatabase()
+	cleanupMembershipsTableDatabase()
 	cleanupClassesTableDatabase()
 	cleanupUserTableDatabase()
 	_, err = testDbInstance.Exec("ALTER SEQUENCE users_id_seq RESTART WITH 1")
@@ -77,13 +189,14 @@ func TestCreateUser(t *testing.T) {
 	expectedUser := api.User{
 		Id:   1,
 		Name: "Joao Folgado",
+		Role: api.RoleMember,
 	}
 
 	ctx := context.Background()
 	uc := usecases.NewUserUseCase(wriRep, readRep)
 
 	// act
-	err1 := uc.CreateUser(ctx, "Joao Folgado")
+	err1 := uc.CreateUser(ctx, api.CreateUser{Name: "Joao Folgado"})
 	user, err2 := uc.GetUserById(ctx, 1)
 
 	// assert
@@ -92,6 +205,78 @@ func TestCreateUser(t *testing.T) {
 	assert.Equal(t, user, expectedUser)
 }
 
+func TestUserProfiles(t *testing.T) {
+	defer cleanupUserTableDatabase()
+	// Arrange
+	uc := usecases.NewUserUseCase(users.NewReadRepository(testDbInstance), users.NewWriteRepository(testDbInstance))
+	ctx := context.Background()
+
+	contact := &api.EmergencyContact{Name: "Rita Folgado", Phone: "+351 913 000 000"}
+	expectedUser := api.User{
+		Id:               1,
+		Name:             "Joao Folgado",
+		Email:            "joao@studio.com",
+		Phone:            "+351 912 345 678",
+		BirthDate:        "1990-05-17",
+		EmergencyContact: contact,
+		Role:             api.RoleMember,
+	}
+
+	// act
+	err1 := uc.CreateUser(ctx, api.CreateUser{Name: "Joao Folgado", Email: "joao@studio.com", Phone: "+351 912 345 678",
+		BirthDate: "1990-05-17", EmergencyContact: contact})
+	user, err2 := uc.GetUserByEmail(ctx, "JOAO@studio.com")
+	errDuplicated := uc.CreateUser(ctx, api.CreateUser{Name: "Other", Email: "joao@studio.com"})
+	err3 := uc.CreateUser(ctx, api.CreateUser{Name: "Ana", Email: "ana@studio.com"})
+
+	taken := "ana@studio.com"
+	_, errConflict := uc.UpdateUser(ctx, api.PatchUser{Id: 1, Email: &taken})
+
+	empty := ""
+	_, err4 := uc.UpdateUser(ctx, api.PatchUser{Id: 1, Phone: &empty, BirthDate: &empty, EmergencyContact: &api.EmergencyContact{}})
+	updated, err5 := uc.GetUserById(ctx, 1)
+	_, errNotFound := uc.GetUserByEmail(ctx, "nobody@studio.com")
+
+	// assert
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	assert.Nil(t, err4)
+	assert.Nil(t, err5)
+	assert.Equal(t, expectedUser, user)
+	assert.Equal(t, http.StatusConflict, errDuplicated.(utils.Error).Code)
+	assert.Equal(t, http.StatusConflict, errConflict.(utils.Error).Code)
+	assert.Equal(t, api.User{Id: 1, Name: "Joao Folgado", Email: "joao@studio.com", Role: api.RoleMember}, updated)
+	assert.Equal(t, http.StatusNotFound, errNotFound.(utils.Error).Code)
+}
+
+func TestCreateUser_ConcurrentSameEmail(t *testing.T) {
+	defer cleanupUserTableDatabase()
+	// Arrange
+	uc := usecases.NewUserUseCase(users.NewReadRepository(testDbInstance), users.NewWriteRepository(testDbInstance))
+	ctx := context.Background()
+
+	// act
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		go func() {
+			errs <- uc.CreateUser(ctx, api.CreateUser{Name: "Joao Folgado", Email: "joao@studio.com"})
+		}()
+	}
+
+	// assert
+	created := 0
+	for i := 0; i < 5; i++ {
+		err := <-errs
+		if err == nil {
+			created++
+			continue
+		}
+		assert.Equal(t, http.StatusConflict, err.(utils.Error).Code)
+	}
+	assert.Equal(t, 1, created)
+}
+
 func TestGetAllUsers(t *testing.T) {
 	defer cleanupUserTableDatabase()
 	// Arrange
@@ -111,18 +296,65 @@ func TestGetAllUsers(t *testing.T) {
 	uc := usecases.NewUserUseCase(wriRep, readRep)
 
 	// act
-	err1 := uc.CreateUser(ctx, "Joao Folgado1")
-	err2 := uc.CreateUser(ctx, "Joao Folgado2")
-	users, err3 := uc.GetAllUsers(ctx)
+	err1 := uc.CreateUser(ctx, api.CreateUser{Name: "Joao Folgado1"})
+	err2 := uc.CreateUser(ctx, api.CreateUser{Name: "Joao Folgado2"})
+	users, total, err3 := uc.SearchUsers(ctx, api.UserFilters{})
 
 	// assert
 	assert.Nil(t, err1)
 	assert.Nil(t, err2)
 	assert.Nil(t, err3)
 	assert.Len(t, users, 2)
+	assert.Equal(t, 2, total)
 	assert.Equal(t, expectedUser, expectedUser)
 }
 
+func TestSearchUsers(t *testing.T) {
+	defer cleanupUserTableDatabase()
+	// Arrange
+	uc := usecases.NewUserUseCase(users.NewReadRepository(testDbInstance), users.NewWriteRepository(testDbInstance))
+	ctx := context.Background()
+
+	for _, user := range []api.CreateUser{
+		{Name: "Ana Silva", Email: "ana@studio.com"},
+		{Name: "Bruno Costa", Email: "bruno@mail.com"},
+		{Name: "Carla Anaya", Email: "carla@studio.com"},
+		{Name: "Diogo_Santos", Email: "diogo@mail.com"},
+	} {
+		assert.Nil(t, uc.CreateUser(ctx, user))
+	}
+
+	names := func(users []api.User) []string {
+		var n []string
+		for _, user := range users {
+			n = append(n, user.Name)
+		}
+		return n
+	}
+
+	// act
+	byText, totalByText, err1 := uc.SearchUsers(ctx, api.UserFilters{Search: "ANA"})
+	byEmail, _, err2 := uc.SearchUsers(ctx, api.UserFilters{Email: "studio", SortBy: "name", Descending: true})
+	wildcard, _, err3 := uc.SearchUsers(ctx, api.UserFilters{Name: "_"})
+	secondPage, totalPaged, err4 := uc.SearchUsers(ctx, api.UserFilters{SortBy: "email", Page: 2, PageSize: 3})
+	pastLastPage, totalPastLastPage, err5 := uc.SearchUsers(ctx, api.UserFilters{Page: 3, PageSize: 3})
+
+	// assert
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	assert.Nil(t, err4)
+	assert.Nil(t, err5)
+	assert.Equal(t, []string{"Ana Silva", "Carla Anaya"}, names(byText))
+	assert.Equal(t, 2, totalByText)
+	assert.Equal(t, []string{"Carla Anaya", "Ana Silva"}, names(byEmail))
+	assert.Equal(t, []string{"Diogo_Santos"}, names(wildcard))
+	assert.Equal(t, []string{"Diogo_Santos"}, names(secondPage))
+	assert.Equal(t, 4, totalPaged)
+	assert.Empty(t, pastLastPage)
+	assert.Equal(t, 4, totalPastLastPage)
+}
+
 func TestCreateClasses(t *testing.T) {
 	defer cleanupClassesTableDatabase()
 	// Arrange
@@ -135,12 +367,12 @@ func TestCreateClasses(t *testing.T) {
 	}
 
 	expectedClasses := []api.ReadClass{
-		{Id: 1, Class: api.Class{Name: "Test1", Date: startDate, Capacity: 10}, NumRegistrations: 0},
-		{Id: 2, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 1), Capacity: 10}, NumRegistrations: 0},
-		{Id: 3, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 2), Capacity: 10}, NumRegistrations: 0},
-		{Id: 4, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 3), Capacity: 10}, NumRegistrations: 0},
-		{Id: 5, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 4), Capacity: 10}, NumRegistrations: 0},
-		{Id: 6, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 5), Capacity: 10}, NumRegistrations: 0},
+		{Id: 1, Class: api.Class{Name: "Test1", Date: startDate, Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 2, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 1), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 3, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 2), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 4, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 3), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 5, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 4), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 6, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 5), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
 	}
 
 	sort.Slice(expectedClasses, func(i, j int) bool {
@@ -152,7 +384,7 @@ func TestCreateClasses(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	uc := usecases.NewClassesUseCases(wriRep, readRep)
+	uc := usecases.NewClassesUseCases(wriRep, readRep, rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
 
 	// act
 	noPossibleToScheduler, err1 := uc.CreateClass(ctx, data[0])
@@ -185,21 +417,21 @@ func TestCreateClassesForUnavailableDays(t *testing.T) {
 	}
 
 	expectedClasses := []api.ReadClass{
-		{Id: 1, Class: api.Class{Name: "Test1", Date: startDate, Capacity: 10}, NumRegistrations: 0},
-		{Id: 2, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 1), Capacity: 10}, NumRegistrations: 0},
-		{Id: 3, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 2), Capacity: 10}, NumRegistrations: 0},
-		{Id: 4, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 3), Capacity: 10}, NumRegistrations: 0},
-		{Id: 5, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 4), Capacity: 10}, NumRegistrations: 0},
-		{Id: 6, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 5), Capacity: 10}, NumRegistrations: 0},
+		{Id: 1, Class: api.Class{Name: "Test1", Date: startDate, Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 2, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 1), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 3, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 2), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 4, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 3), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 5, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 4), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 6, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 5), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
 	}
 
 	notPossibleToAddExpected := []api.Class{
-		{Name: "Test2", Date: startDate, Capacity: 10},
-		{Name: "Test2", Date: startDate.AddDate(0, 0, 1), Capacity: 10},
-		{Name: "Test2", Date: startDate.AddDate(0, 0, 2), Capacity: 10},
-		{Name: "Test2", Date: startDate.AddDate(0, 0, 3), Capacity: 10},
-		{Name: "Test2", Date: startDate.AddDate(0, 0, 4), Capacity: 10},
-		{Name: "Test2", Date: startDate.AddDate(0, 0, 5), Capacity: 10},
+		{Name: "Test2", Date: startDate, Duration: 60, Capacity: 10},
+		{Name: "Test2", Date: startDate.AddDate(0, 0, 1), Duration: 60, Capacity: 10},
+		{Name: "Test2", Date: startDate.AddDate(0, 0, 2), Duration: 60, Capacity: 10},
+		{Name: "Test2", Date: startDate.AddDate(0, 0, 3), Duration: 60, Capacity: 10},
+		{Name: "Test2", Date: startDate.AddDate(0, 0, 4), Duration: 60, Capacity: 10},
+		{Name: "Test2", Date: startDate.AddDate(0, 0, 5), Duration: 60, Capacity: 10},
 	}
 
 	sort.Slice(expectedClasses, func(i, j int) bool {
@@ -211,7 +443,7 @@ func TestCreateClassesForUnavailableDays(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	uc := usecases.NewClassesUseCases(wriRep, readRep)
+	uc := usecases.NewClassesUseCases(wriRep, readRep, rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
 
 	// act
 	noPossibleToScheduler, err1 := uc.CreateClass(ctx, data[0])
@@ -248,24 +480,24 @@ func TestCreateClassesForParcialNotAvailableDays(t *testing.T) {
 	}
 
 	expectedClasses := []api.ReadClass{
-		{Id: 1, Class: api.Class{Name: "Test1", Date: startDate, Capacity: 10}, NumRegistrations: 0},
-		{Id: 2, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 1), Capacity: 10}, NumRegistrations: 0},
-		{Id: 3, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 2), Capacity: 10}, NumRegistrations: 0},
-		{Id: 4, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 3), Capacity: 10}, NumRegistrations: 0},
-		{Id: 5, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 4), Capacity: 10}, NumRegistrations: 0},
-		{Id: 6, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 5), Capacity: 10}, NumRegistrations: 0},
-		{Id: 7, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 6), Capacity: 10}, NumRegistrations: 0},
-		{Id: 8, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 7), Capacity: 10}, NumRegistrations: 0},
-		{Id: 9, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 8), Capacity: 10}, NumRegistrations: 0},
+		{Id: 1, Class: api.Class{Name: "Test1", Date: startDate, Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 2, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 1), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 3, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 2), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 4, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 3), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 5, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 4), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 6, Class: api.Class{Name: "Test1", Date: startDate.AddDate(0, 0, 5), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 7, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 6), Duration: 60, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 8, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 7), Duration: 60, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 9, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 8), Duration: 60, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
 	}
 
 	notPossibleToAddExpected := []api.Class{
-		{Name: "Test2", Date: startDate, Capacity: 10},
-		{Name: "Test2", Date: startDate.AddDate(0, 0, 1), Capacity: 10},
-		{Name: "Test2", Date: startDate.AddDate(0, 0, 2), Capacity: 10},
-		{Name: "Test2", Date: startDate.AddDate(0, 0, 3), Capacity: 10},
-		{Name: "Test2", Date: startDate.AddDate(0, 0, 4), Capacity: 10},
-		{Name: "Test2", Date: startDate.AddDate(0, 0, 5), Capacity: 10},
+		{Name: "Test2", Date: startDate, Duration: 60, Capacity: 10},
+		{Name: "Test2", Date: startDate.AddDate(0, 0, 1), Duration: 60, Capacity: 10},
+		{Name: "Test2", Date: startDate.AddDate(0, 0, 2), Duration: 60, Capacity: 10},
+		{Name: "Test2", Date: startDate.AddDate(0, 0, 3), Duration: 60, Capacity: 10},
+		{Name: "Test2", Date: startDate.AddDate(0, 0, 4), Duration: 60, Capacity: 10},
+		{Name: "Test2", Date: startDate.AddDate(0, 0, 5), Duration: 60, Capacity: 10},
 	}
 
 	sort.Slice(expectedClasses, func(i, j int) bool {
@@ -277,7 +509,7 @@ func TestCreateClassesForParcialNotAvailableDays(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	uc := usecases.NewClassesUseCases(wriRep, readRep)
+	uc := usecases.NewClassesUseCases(wriRep, readRep, rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
 
 	// act
 	noPossibleToScheduler, err1 := uc.CreateClass(ctx, data[0])
@@ -295,6 +527,50 @@ func TestCreateClassesForParcialNotAvailableDays(t *testing.T) {
 	assert.Equal(t, expectedClasses, allClasses)
 }
 
+func TestCreateClassesSameDayDifferentTimes(t *testing.T) {
+	defer cleanupClassesTableDatabase()
+	// Arrange
+	wriRep := classes.NewReadRepository(testDbInstance)
+	readRep := classes.NewWriteRepository(testDbInstance)
+
+	morning := time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC)
+	data := []api.ClassScheduler{
+		{Name: "Yoga", StartDate: morning, EndDate: morning.AddDate(0, 0, 1), Duration: 60, Capacity: 10},
+		{Name: "Pilates", StartDate: morning.Add(time.Hour), EndDate: morning.Add(time.Hour).AddDate(0, 0, 1), Duration: 45, Capacity: 10},
+		{Name: "Crossfit", StartDate: morning.Add(90 * time.Minute), EndDate: morning.Add(90 * time.Minute), Duration: 60, Capacity: 10},
+	}
+
+	expectedClasses := []api.ReadClass{
+		{Id: 1, Class: api.Class{Name: "Yoga", Date: morning, Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 2, Class: api.Class{Name: "Yoga", Date: morning.AddDate(0, 0, 1), Duration: 60, Capacity: 10, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 3, Class: api.Class{Name: "Pilates", Date: morning.Add(time.Hour), Duration: 45, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 4, Class: api.Class{Name: "Pilates", Date: morning.Add(time.Hour).AddDate(0, 0, 1), Duration: 45, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+	}
+
+	notPossibleToAddExpected := []api.Class{
+		{Name: "Crossfit", Date: morning.Add(90 * time.Minute), Duration: 60, Capacity: 10},
+	}
+
+	ctx := context.Background()
+	uc := usecases.NewClassesUseCases(wriRep, readRep, rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
+
+	// act
+	noPossibleToScheduler1, err1 := uc.CreateClass(ctx, data[0])
+	noPossibleToScheduler2, err2 := uc.CreateClass(ctx, data[1])
+	noPossibleToScheduler3, err3 := uc.CreateClass(ctx, data[2])
+	allClasses, err4 := uc.GetFilteredClasses(ctx, api.ClasseFilters{})
+
+	// assert
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	assert.Nil(t, err4)
+	assert.Nil(t, noPossibleToScheduler1)
+	assert.Nil(t, noPossibleToScheduler2)
+	assert.Equal(t, notPossibleToAddExpected, noPossibleToScheduler3)
+	assert.Equal(t, expectedClasses, allClasses)
+}
+
 func TestGetClassesWithFilters(t *testing.T) {
 	defer cleanupClassesTableDatabase()
 	// Arrange
@@ -312,11 +588,11 @@ func TestGetClassesWithFilters(t *testing.T) {
 	}
 
 	expectedForFilter1 := []api.ReadClass{
-		{Id: 7, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 6), Capacity: 10}, NumRegistrations: 0},
-		{Id: 8, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 7), Capacity: 10}, NumRegistrations: 0},
-		{Id: 9, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 8), Capacity: 10}, NumRegistrations: 0},
-		{Id: 10, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 9), Capacity: 10}, NumRegistrations: 0},
-		{Id: 11, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 10), Capacity: 10}, NumRegistrations: 0},
+		{Id: 7, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 6), Duration: 60, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 8, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 7), Duration: 60, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 9, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 8), Duration: 60, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 10, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 9), Duration: 60, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 11, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 10), Duration: 60, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
 	}
 
 	dateFilter2 := startDate.AddDate(0, 0, 8)
@@ -326,9 +602,9 @@ func TestGetClassesWithFilters(t *testing.T) {
 	}
 
 	expectedForFilter2 := []api.ReadClass{
-		{Id: 9, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 8), Capacity: 10}, NumRegistrations: 0},
-		{Id: 10, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 9), Capacity: 10}, NumRegistrations: 0},
-		{Id: 11, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 10), Capacity: 10}, NumRegistrations: 0},
+		{Id: 9, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 8), Duration: 60, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 10, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 9), Duration: 60, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+		{Id: 11, Class: api.Class{Name: "Test2", Date: startDate.AddDate(0, 0, 10), Duration: 60, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
 	}
 
 	filter3 := api.ClasseFilters{
@@ -347,7 +623,7 @@ func TestGetClassesWithFilters(t *testing.T) {
 	}
 	// act
 	ctx := context.Background()
-	uc := usecases.NewClassesUseCases(wriRep, readRep)
+	uc := usecases.NewClassesUseCases(wriRep, readRep, rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
 	for _, d := range data {
 		_, _ = uc.CreateClass(ctx, d)
 	}
@@ -384,7 +660,9 @@ func TestUpdateExistingClassDate_DateUnavailable(t *testing.T) {
 	class := api.Class{
 		Name:     "Updated",
 		Date:     updateDate,
+		Duration: 60,
 		Capacity: 20,
+		SeriesId: 1,
 	}
 
 	expectedError := utils.E(http.StatusNotFound,
@@ -396,10 +674,11 @@ func TestUpdateExistingClassDate_DateUnavailable(t *testing.T) {
 	expectedClass := api.ReadClass{
 		Id:               5,
 		Class:            class,
+		Status:           api.ClassStatusScheduled,
 		NumRegistrations: 0,
 	}
 	ctx := context.Background()
-	uc := usecases.NewClassesUseCases(wriRep, readRep)
+	uc := usecases.NewClassesUseCases(wriRep, readRep, rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
 
 	// act
 	_, err := uc.CreateClass(ctx, data[0])
@@ -435,16 +714,19 @@ func TestUpdateExistingClassDate(t *testing.T) {
 	class := api.Class{
 		Name:     "Updated",
 		Date:     updateDate,
+		Duration: 60,
 		Capacity: 10,
+		SeriesId: 1,
 	}
 
 	expectedClass := api.ReadClass{
 		Id:               5,
 		Class:            class,
+		Status:           api.ClassStatusScheduled,
 		NumRegistrations: 0,
 	}
 	ctx := context.Background()
-	uc := usecases.NewClassesUseCases(wriRep, readRep)
+	uc := usecases.NewClassesUseCases(wriRep, readRep, rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
 
 	// act
 	_, err := uc.CreateClass(ctx, data[0])
@@ -483,6 +765,7 @@ func TestUpdateExisting_NewCapacityLowerThenNumRegistrations(t *testing.T) {
 	class := api.Class{
 		Name:     "Test",
 		Date:     expectedDate,
+		Duration: 60,
 		Capacity: 3,
 	}
 
@@ -495,10 +778,11 @@ func TestUpdateExisting_NewCapacityLowerThenNumRegistrations(t *testing.T) {
 	expectedClass := api.ReadClass{
 		Id:               1,
 		Class:            class,
+		Status:           api.ClassStatusScheduled,
 		NumRegistrations: 3,
 	}
 	ctx := context.Background()
-	uc := usecases.NewClassesUseCases(wriRep, readRep)
+	uc := usecases.NewClassesUseCases(wriRep, readRep, rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
 
 	// act
 	rowsUpdated, err := uc.UpdateClass(ctx, updateClass, 1)
@@ -511,58 +795,68 @@ func TestUpdateExisting_NewCapacityLowerThenNumRegistrations(t *testing.T) {
 	assert.Equal(t, expectedClass, classToValidate)
 }
 
-func TestCreateReservation_ClassAlreadyFull(t *testing.T) {
+func TestCreateReservation_ClassAlreadyFullJoinsWaitlist(t *testing.T) {
 	defer cleanupAllTablesDatabase()
 	// Arrange
-	date := "2024-03-17"
+	date := "2030-03-17"
 	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, num_registrations)
 	VALUES('Test', '` + date + `', 3, 3)`)
 	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado')`)
+	addUnlimitedMemberships()
 
 	redRep := booking.NewReadRepository(testDbInstance)
 	wrRep := booking.NewWriteRepository(testDbInstance)
 
 	bookUseCase := usecases.NewBookUseCase(redRep, wrRep)
-	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep)
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
 
-	expectedError := utils.E(http.StatusUnprocessableEntity,
-		nil,
-		map[string]string{"message": "Class Capacity Reached"},
-		"The class is already full and cannot accept any more registrations.",
-		"Please select another class or try again later.")
+	expectedResult := api.BookingResult{
+		ClassId:          1,
+		UserId:           1,
+		Status:           api.BookingStatusWaitlisted,
+		WaitlistPosition: 1,
+	}
 
 	// Act
-	err := makeReservationUseCase.Book(context.Background(), 1, 1)
+	result, err := makeReservationUseCase.Book(context.Background(), 1, 1)
 
 	// assert
-	assert.Equal(t, expectedError, err)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResult, result)
 	classReservations, err2 := bookUseCase.GetClassesReservations(context.Background(), 1)
 	assert.Len(t, classReservations, 0)
 	assert.Nil(t, err2)
+	usersReservationList, err3 := bookUseCase.GetUserReservations(context.Background(), 1)
+	assert.Nil(t, err3)
+	assert.Len(t, usersReservationList, 1)
+	assert.Equal(t, api.BookingStatusWaitlisted, usersReservationList[0].Status)
+	assert.Equal(t, 1, usersReservationList[0].WaitlistPosition)
 }
 
 func TestCreateReservation_ClassAvailable(t *testing.T) {
 	cleanupAllTablesDatabase()
 	// Arrange
-	date := "2024-03-17T12:00:00Z"
+	date := "2030-03-17T12:00:00Z"
 	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, num_registrations)
 	VALUES('Test', '` + date + `', 3, 2)`)
 	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado')`)
+	addUnlimitedMemberships()
 
 	redRep := booking.NewReadRepository(testDbInstance)
 	wrRep := booking.NewWriteRepository(testDbInstance)
 
 	bookUseCase := usecases.NewBookUseCase(redRep, wrRep)
-	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep)
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
 
 	expectedResult := []api.UsersBooked{
 		{ClassId: 1, UserId: 1, UserName: "Joao Folgado"},
 	}
 	// Act
-	err := makeReservationUseCase.Book(context.Background(), 1, 1)
+	result, err := makeReservationUseCase.Book(context.Background(), 1, 1)
 
 	// assert
 	assert.Nil(t, err)
+	assert.Equal(t, api.BookingStatusBooked, result.Status)
 	classReservationsList, err2 := bookUseCase.GetClassesReservations(context.Background(), 1)
 	usersReservationList, err3 := bookUseCase.GetUserReservations(context.Background(), 1)
 	assert.Len(t, classReservationsList, 1)
@@ -572,10 +866,1311 @@ func TestCreateReservation_ClassAvailable(t *testing.T) {
 	assert.Len(t, usersReservationList, 1)
 }
 
-func Int(i int) *int {
-	return &i
+func TestCancelReservation_ReleasesSpot(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	date := "2030-03-17T12:00:00Z"
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, num_registrations)
+	VALUES('Test', '` + date + `', 1, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado')`)
+	addUnlimitedMemberships()
+
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+	classesReadRep := classes.NewReadRepository(testDbInstance)
+
+	bookUseCase := usecases.NewBookUseCase(redRep, wrRep)
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
+
+	// Act
+	_, err := makeReservationUseCase.Book(context.Background(), 1, 1)
+	_, err1 := makeReservationUseCase.Cancel(context.Background(), 1, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, err1)
+	classReservations, err2 := bookUseCase.GetClassesReservations(context.Background(), 1)
+	assert.Nil(t, err2)
+	assert.Len(t, classReservations, 0)
+	class, err3 := classesReadRep.GetById(context.Background(), 1)
+	assert.Nil(t, err3)
+	assert.Equal(t, 0, class.NumRegistrations)
 }
 
-func String(i string) *string {
-	return &i
+func TestCancelReservation_StartedClassKeepsCredit(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	// one class already ended and one in progress
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, class_duration, num_registrations)
+	VALUES('Ended', NOW() - INTERVAL '3 hours', 3, 60, 1), ('Now', NOW() - INTERVAL '10 minutes', 3, 60, 1)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado')`)
+	testDbInstance.DB.Exec(`INSERT INTO membership_plans (plan_name, plan_type, credits, validity_days) VALUES('Pack', 'credits', 10, 30)`)
+	testDbInstance.DB.Exec(`INSERT INTO user_memberships (user_id, plan_id, valid_from, valid_until, remaining_credits)
+	VALUES(1, 1, NOW() - INTERVAL '1 day', NOW() + INTERVAL '1 day', 8)`)
+	testDbInstance.DB.Exec(`INSERT INTO booking (user_id, class_id, reserved_date, membership_id)
+	SELECT 1, c.id, NOW() - INTERVAL '1 day', m.id FROM classes c, user_memberships m`)
+
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
+	attendanceUseCase := usecases.NewAttendanceUseCase(wrRep, utils.SystemClock)
+
+	_, err := attendanceUseCase.MarkNoShows(ctx)
+	assert.Nil(t, err)
+
+	// Act
+	_, err1 := makeReservationUseCase.Cancel(ctx, 1, 1)
+	err2 := wrRep.Remove(ctx, 1, 1, false, time.Now())
+	_, err3 := makeReservationUseCase.Cancel(ctx, 1, 2)
+
+	// assert
+	assert.Equal(t, "Class Already Finished", err1.(utils.Error).Message()["message"])
+	assert.Equal(t, http.StatusUnprocessableEntity, err2.(utils.Error).StatusCode())
+	assert.Equal(t, "Class In Progress", err3.(utils.Error).Message()["message"])
+
+	var remainingCredits int
+	assert.Nil(t, testDbInstance.Get(&remainingCredits, "SELECT remaining_credits FROM user_memberships WHERE user_id = 1"))
+	assert.Equal(t, 8, remainingCredits)
+
+	var noShows, bookings int
+	assert.Nil(t, testDbInstance.Get(&noShows, "SELECT count(*) FROM booking WHERE class_id = 1 AND attendance_status = $1", api.AttendanceNoShow))
+	assert.Nil(t, testDbInstance.Get(&bookings, "SELECT count(*) FROM booking"))
+	assert.Equal(t, 1, noShows)
+	assert.Equal(t, 2, bookings)
+}
+
+func TestCancelReservation_BookingNotFound(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	date := "2030-03-17T12:00:00Z"
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, num_registrations)
+	VALUES('Test', '` + date + `', 3, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado')`)
+	addUnlimitedMemberships()
+
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
+
+	expectedError := utils.E(http.StatusNotFound,
+		nil,
+		map[string]string{"message": "Booking Not Found"},
+		"The specified user has no booking for this class.",
+		"Please provide a valid user ID and class ID.")
+
+	// Act
+	_, err := makeReservationUseCase.Cancel(context.Background(), 1, 1)
+
+	// assert
+	assert.Equal(t, expectedError, err)
+}
+
+func TestCancelReservation_PromotesFirstWaitlistedUser(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	date := "2030-03-17T12:00:00Z"
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, num_registrations)
+	VALUES('Test', '` + date + `', 1, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado'), ('Sergio Folgado'), ('Maria Folgado')`)
+	addUnlimitedMemberships()
+
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+
+	bookUseCase := usecases.NewBookUseCase(redRep, wrRep)
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
+
+	expectedResult := []api.UsersBooked{
+		{ClassId: 1, UserId: 2, UserName: "Sergio Folgado"},
+	}
+
+	// Act
+	_, err := makeReservationUseCase.Book(context.Background(), 1, 1)
+	second, err1 := makeReservationUseCase.Book(context.Background(), 2, 1)
+	third, err2 := makeReservationUseCase.Book(context.Background(), 3, 1)
+	_, err3 := makeReservationUseCase.Cancel(context.Background(), 1, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	assert.Equal(t, 1, second.WaitlistPosition)
+	assert.Equal(t, 2, third.WaitlistPosition)
+
+	classReservations, err4 := bookUseCase.GetClassesReservations(context.Background(), 1)
+	assert.Nil(t, err4)
+	assert.Equal(t, expectedResult, classReservations)
+
+	thirdReservations, err5 := bookUseCase.GetUserReservations(context.Background(), 3)
+	assert.Nil(t, err5)
+	assert.Len(t, thirdReservations, 1)
+	assert.Equal(t, 1, thirdReservations[0].WaitlistPosition)
+}
+
+func TestUpdateClassCapacity_PromotesWaitlistedUsers(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	date := "2030-03-17T12:00:00Z"
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, num_registrations)
+	VALUES('Test', '` + date + `', 1, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado'), ('Sergio Folgado'), ('Maria Folgado')`)
+	addUnlimitedMemberships()
+
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+	classesWrRep := classes.NewWriteRepository(testDbInstance)
+	classesReadRep := classes.NewReadRepository(testDbInstance)
+
+	bookUseCase := usecases.NewBookUseCase(redRep, wrRep)
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
+
+	// Act
+	_, err := makeReservationUseCase.Book(context.Background(), 1, 1)
+	_, err1 := makeReservationUseCase.Book(context.Background(), 2, 1)
+	_, err2 := makeReservationUseCase.Book(context.Background(), 3, 1)
+	_, err3 := classesWrRep.Update(context.Background(), 1, api.UpdateClass{Capacity: Int(2)})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+
+	classReservations, err4 := bookUseCase.GetClassesReservations(context.Background(), 1)
+	assert.Nil(t, err4)
+	assert.Len(t, classReservations, 2)
+
+	class, err5 := classesReadRep.GetById(context.Background(), 1)
+	assert.Nil(t, err5)
+	assert.Equal(t, 2, class.NumRegistrations)
+}
+
+func TestCreateClassesSameTimeDifferentRooms(t *testing.T) {
+	defer cleanupRoomsTableDatabase()
+	// Arrange
+	ctx := context.Background()
+	roomsReadRep := rooms.NewReadRepository(testDbInstance)
+	roomsWrRep := rooms.NewWriteRepository(testDbInstance)
+	assert.Nil(t, roomsWrRep.Add(ctx, api.CreateRoom{Name: "Studio A", MaxCapacity: 20}))
+	assert.Nil(t, roomsWrRep.Add(ctx, api.CreateRoom{Name: "Studio B", MaxCapacity: 12}))
+
+	morning := time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC)
+	data := []api.ClassScheduler{
+		{Name: "Yoga", StartDate: morning, EndDate: morning, Duration: 60, Capacity: 10, RoomId: 1},
+		{Name: "Pilates", StartDate: morning, EndDate: morning, Duration: 60, Capacity: 10, RoomId: 2},
+		{Name: "Crossfit", StartDate: morning.Add(30 * time.Minute), EndDate: morning.Add(30 * time.Minute), Duration: 60, Capacity: 10, RoomId: 1},
+		{Name: "Spinning", StartDate: morning.Add(5 * time.Hour), EndDate: morning.Add(5 * time.Hour), Duration: 60, Capacity: 15, RoomId: 2},
+	}
+
+	expectedRoom2Classes := []api.ReadClass{
+		{Id: 2, Class: api.Class{Name: "Pilates", Date: morning, Duration: 60, Capacity: 10, RoomId: 2, SeriesId: 2}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+	}
+
+	uc := usecases.NewClassesUseCases(classes.NewReadRepository(testDbInstance), classes.NewWriteRepository(testDbInstance), roomsReadRep, instructors.NewReadRepository(testDbInstance), utils.SystemClock)
+
+	// act
+	noPossibleToScheduler1, err1 := uc.CreateClass(ctx, data[0])
+	noPossibleToScheduler2, err2 := uc.CreateClass(ctx, data[1])
+	noPossibleToScheduler3, err3 := uc.CreateClass(ctx, data[2])
+	_, err4 := uc.CreateClass(ctx, data[3])
+	room2Classes, err5 := uc.GetFilteredClasses(ctx, api.ClasseFilters{RoomId: Int(2)})
+
+	// assert
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	assert.Equal(t, http.StatusUnprocessableEntity, err4.(utils.Error).Code)
+	assert.Nil(t, err5)
+	assert.Nil(t, noPossibleToScheduler1)
+	assert.Nil(t, noPossibleToScheduler2)
+	assert.Len(t, noPossibleToScheduler3, 1)
+	assert.Equal(t, expectedRoom2Classes, room2Classes)
+}
+
+func TestUpdateRoom_MaxCapacityBelowUpcomingClass(t *testing.T) {
+	defer cleanupRoomsTableDatabase()
+	// Arrange
+	ctx := context.Background()
+	roomsWrRep := rooms.NewWriteRepository(testDbInstance)
+	assert.Nil(t, roomsWrRep.Add(ctx, api.CreateRoom{Name: "Studio A", MaxCapacity: 20}))
+
+	date := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, room_id, num_registrations)
+	VALUES('Test', '` + date + `', 15, 1, 0)`)
+
+	uc := usecases.NewRoomsUseCases(rooms.NewReadRepository(testDbInstance), roomsWrRep)
+
+	// Act
+	_, err1 := uc.UpdateRoom(ctx, api.PatchRoom{Id: 1, MaxCapacity: Int(10)})
+	_, err2 := uc.UpdateRoom(ctx, api.PatchRoom{Id: 1, MaxCapacity: Int(15)})
+	room, err3 := uc.GetRoomById(ctx, 1)
+
+	// assert
+	assert.Equal(t, http.StatusUnprocessableEntity, err1.(utils.Error).Code)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	assert.Equal(t, api.Room{Id: 1, Name: "Studio A", MaxCapacity: 15}, room)
+}
+
+func TestCreateClassesInstructorInOverlappingClasses(t *testing.T) {
+	defer cleanupInstructorsTableDatabase()
+	// Arrange
+	ctx := context.Background()
+	roomsWrRep := rooms.NewWriteRepository(testDbInstance)
+	assert.Nil(t, roomsWrRep.Add(ctx, api.CreateRoom{Name: "Studio A", MaxCapacity: 20}))
+	assert.Nil(t, roomsWrRep.Add(ctx, api.CreateRoom{Name: "Studio B", MaxCapacity: 20}))
+
+	instructorsUc := usecases.NewInstructorsUseCases(instructors.NewReadRepository(testDbInstance), instructors.NewWriteRepository(testDbInstance))
+	assert.Nil(t, instructorsUc.CreateInstructor(ctx, api.CreateInstructor{Name: "Ana Silva", Email: "ana@studio.com"}))
+	assert.Nil(t, instructorsUc.CreateInstructor(ctx, api.CreateInstructor{Name: "Rui Costa"}))
+	errDuplicatedEmail := instructorsUc.CreateInstructor(ctx, api.CreateInstructor{Name: "Ana Costa", Email: "ana@studio.com"})
+
+	morning := time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC)
+	data := []api.ClassScheduler{
+		{Name: "Yoga", StartDate: morning, EndDate: morning, Duration: 60, Capacity: 10, RoomId: 1, InstructorId: 1},
+		{Name: "Pilates", StartDate: morning.Add(30 * time.Minute), EndDate: morning.Add(30 * time.Minute), Duration: 60, Capacity: 10, RoomId: 2, InstructorId: 1},
+		{Name: "Pilates", StartDate: morning.Add(30 * time.Minute), EndDate: morning.Add(30 * time.Minute), Duration: 60, Capacity: 10, RoomId: 2, InstructorId: 2},
+	}
+
+	expectedInstructorClasses := []api.ReadClass{
+		{Id: 1, Class: api.Class{Name: "Yoga", Date: morning, Duration: 60, Capacity: 10, RoomId: 1, InstructorId: 1, SeriesId: 1}, Status: api.ClassStatusScheduled, NumRegistrations: 0},
+	}
+
+	uc := usecases.NewClassesUseCases(classes.NewReadRepository(testDbInstance), classes.NewWriteRepository(testDbInstance), rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
+
+	// act
+	noPossibleToScheduler1, err1 := uc.CreateClass(ctx, data[0])
+	noPossibleToScheduler2, err2 := uc.CreateClass(ctx, data[1])
+	noPossibleToScheduler3, err3 := uc.CreateClass(ctx, data[2])
+	instructorClasses, err4 := uc.GetFilteredClasses(ctx, api.ClasseFilters{InstructorId: Int(1)})
+	// the series of the rejected class is not kept
+	var series int
+	err5 := testDbInstance.Get(&series, "SELECT COUNT(*) FROM class_series")
+
+	// assert
+	assert.Equal(t, http.StatusConflict, errDuplicatedEmail.(utils.Error).Code)
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	assert.Nil(t, err4)
+	assert.Nil(t, noPossibleToScheduler1)
+	assert.Len(t, noPossibleToScheduler2, 1)
+	assert.Nil(t, noPossibleToScheduler3)
+	assert.Equal(t, expectedInstructorClasses, instructorClasses)
+	assert.Nil(t, err5)
+	assert.Equal(t, 2, series)
+}
+
+func TestCreateClassesFromTwoInstances(t *testing.T) {
+	defer cleanupClassesTableDatabase()
+	// Arrange
+	ctx := context.Background()
+	newUseCases := func() usecases.ClassesUseCases {
+		return usecases.NewClassesUseCases(classes.NewReadRepository(testDbInstance), classes.NewWriteRepository(testDbInstance), rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
+	}
+	// each instance has its own cache, like two replicas
+	first := newUseCases()
+	second := newUseCases()
+
+	evening := time.Date(2030, time.March, 1, 18, 0, 0, 0, time.UTC)
+	warmup := api.ClassScheduler{Name: "Boxing", StartDate: evening.Add(-10 * time.Hour), EndDate: evening.Add(-10 * time.Hour), Capacity: 10}
+	data := api.ClassScheduler{Name: "Yoga", StartDate: evening, EndDate: evening.AddDate(0, 0, 1), Capacity: 10}
+	overlapping := api.ClassScheduler{Name: "Pilates", StartDate: evening.AddDate(0, 0, 1).Add(30 * time.Minute), EndDate: evening.AddDate(0, 0, 2).Add(30 * time.Minute), Capacity: 10}
+	moveTo := evening.Add(30 * time.Minute)
+
+	expectedError := utils.E(http.StatusNotFound,
+		nil,
+		map[string]string{"message": "Date already reserved"},
+		"The selected date is already reserved.",
+		"Please choose a different date or class.")
+
+	// act
+	// the second instance loads the month before the first one schedules the yoga classes
+	_, err := second.CreateClass(ctx, warmup)
+	notScheduled1, err1 := first.CreateClass(ctx, data)
+	notScheduled2, err2 := second.CreateClass(ctx, overlapping)
+	// the rejected class used id 4
+	rowsUpdated, err3 := second.UpdateClass(ctx, api.UpdateClass{Date: &moveTo}, 5)
+	allClasses, err4 := second.GetFilteredClasses(ctx, api.ClasseFilters{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, notScheduled1)
+	assert.Equal(t, []api.Class{{Name: "Pilates", Date: overlapping.StartDate, Duration: 60, Capacity: 10, SeriesId: 3}}, notScheduled2)
+	assert.Equal(t, expectedError, err3)
+	assert.Equal(t, int64(0), rowsUpdated)
+	assert.Nil(t, err4)
+	assert.Len(t, allClasses, 4)
+}
+
+func TestCreateClassesAfterRestart(t *testing.T) {
+	defer cleanupClassesTableDatabase()
+	// Arrange
+	ctx := context.Background()
+	newUseCases := func() usecases.ClassesUseCases {
+		return usecases.NewClassesUseCases(classes.NewReadRepository(testDbInstance), classes.NewWriteRepository(testDbInstance), rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
+	}
+
+	evening := time.Date(2030, time.March, 1, 18, 0, 0, 0, time.UTC)
+	data := api.ClassScheduler{Name: "Yoga", StartDate: evening, EndDate: evening.AddDate(0, 0, 1), Capacity: 10}
+	overlapping := api.ClassScheduler{Name: "Pilates", StartDate: evening.AddDate(0, 0, 1).Add(30 * time.Minute), EndDate: evening.AddDate(0, 0, 2).Add(30 * time.Minute), Capacity: 10}
+
+	// act
+	_, err1 := newUseCases().CreateClass(ctx, data)
+	// the restarted instance loads the month from the database
+	notScheduled, err2 := newUseCases().CreateClass(ctx, overlapping)
+	allClasses, err3 := newUseCases().GetFilteredClasses(ctx, api.ClasseFilters{Name: "Pilates"})
+
+	// assert
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	// rejected by the cache, before a series is created
+	assert.Equal(t, []api.Class{{Name: "Pilates", Date: overlapping.StartDate, Duration: 60, Capacity: 10}}, notScheduled)
+	assert.Equal(t, []api.ReadClass{{Id: 3, Class: api.Class{Name: "Pilates", Date: overlapping.EndDate, Duration: 60, Capacity: 10, SeriesId: 2}, Status: api.ClassStatusScheduled}}, allClasses)
+}
+
+func TestListMonthSchedule(t *testing.T) {
+	defer cleanupInstructorsTableDatabase()
+	// Arrange
+	ctx := context.Background()
+	assert.Nil(t, rooms.NewWriteRepository(testDbInstance).Add(ctx, api.CreateRoom{Name: "Studio A", MaxCapacity: 20}))
+	assert.Nil(t, instructors.NewWriteRepository(testDbInstance).Add(ctx, api.CreateInstructor{Name: "Ana Silva"}))
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, room_id, instructor_id, class_status)
+	VALUES ('NoRoom', '2024-03-01T08:00:00Z', 10, NULL, NULL, 'scheduled'),
+		('Room', '2024-03-01T08:00:00Z', 10, 1, 1, 'scheduled'),
+		('Cancelled', '2024-03-02T08:00:00Z', 10, 1, 1, 'cancelled'),
+		('NextMonth', '2024-04-01T08:00:00Z', 10, 1, 1, 'scheduled')`)
+
+	readRep := classes.NewReadRepository(testDbInstance)
+	march := func(class string, roomId int) api.Class {
+		return api.Class{Name: class, Date: time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC), Duration: 60, Capacity: 10, RoomId: roomId, InstructorId: roomId}
+	}
+
+	testCases := []struct {
+		testName string
+		filter   api.ScheduleFilter
+		expected []api.Class
+	}{
+		{"classes without room", api.ScheduleFilter{Year: 2024, Month: time.March}, []api.Class{march("NoRoom", 0)}},
+		{"room", api.ScheduleFilter{RoomId: 1, Year: 2024, Month: time.March}, []api.Class{march("Room", 1)}},
+		{"instructor", api.ScheduleFilter{InstructorId: 1, Year: 2024, Month: time.March}, []api.Class{march("Room", 1)}},
+		{"empty month", api.ScheduleFilter{RoomId: 1, Year: 2024, Month: time.May}, []api.Class{}},
+	}
+
+	for _, tc := range testCases {
+		// act
+		scheduled, err := readRep.ListMonthSchedule(ctx, tc.filter)
+
+		// assert
+		assert.Nil(t, err, tc.testName)
+		assert.Equal(t, tc.expected, scheduled, tc.testName)
+	}
+}
+
+func TestUpdateSeries_MovesFollowingOccurrences(t *testing.T) {
+	defer cleanupClassesTableDatabase()
+	// Arrange
+	ctx := context.Background()
+	classesReadRep := classes.NewReadRepository(testDbInstance)
+	uc := usecases.NewClassesUseCases(classesReadRep, classes.NewWriteRepository(testDbInstance), rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
+
+	y, m, d := time.Now().AddDate(0, 0, 1).Date()
+	evening := time.Date(y, m, d, 18, 0, 0, 0, time.UTC)
+	data := api.ClassScheduler{Name: "Yoga", StartDate: evening, EndDate: evening.AddDate(0, 0, 3), Capacity: 10}
+	startTime := 20 * time.Hour
+
+	// act
+	_, err := uc.CreateClass(ctx, data)
+	rowsUpdated, err1 := uc.UpdateSeries(ctx, api.UpdateSeries{Name: String("Evening Yoga"), StartTime: &startTime}, 3)
+	allClasses, err2 := uc.GetFilteredClasses(ctx, api.ClasseFilters{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Equal(t, int64(2), rowsUpdated)
+	assert.Len(t, allClasses, 4)
+	sort.Slice(allClasses, func(i, j int) bool {
+		return allClasses[i].Id < allClasses[j].Id
+	})
+	assert.Equal(t, api.Class{Name: "Yoga", Date: evening.AddDate(0, 0, 1), Duration: 60, Capacity: 10, SeriesId: 1}, allClasses[1].Class)
+	assert.Equal(t, api.Class{Name: "Evening Yoga", Date: evening.AddDate(0, 0, 2).Add(2 * time.Hour), Duration: 60, Capacity: 10, SeriesId: 1}, allClasses[2].Class)
+	assert.Equal(t, api.Class{Name: "Evening Yoga", Date: evening.AddDate(0, 0, 3).Add(2 * time.Hour), Duration: 60, Capacity: 10, SeriesId: 1}, allClasses[3].Class)
+}
+
+func TestCancelSeries_ReleasesBookings(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	classesReadRep := classes.NewReadRepository(testDbInstance)
+	uc := usecases.NewClassesUseCases(classesReadRep, classes.NewWriteRepository(testDbInstance), rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado'), ('Sergio Folgado')`)
+	addUnlimitedMemberships()
+
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+	bookUseCase := usecases.NewBookUseCase(redRep, wrRep)
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
+
+	y, m, d := time.Now().AddDate(0, 0, 1).Date()
+	evening := time.Date(y, m, d, 18, 0, 0, 0, time.UTC)
+	data := api.ClassScheduler{Name: "Yoga", StartDate: evening, EndDate: evening.AddDate(0, 0, 2), Capacity: 1}
+
+	// act
+	_, err := uc.CreateClass(ctx, data)
+	_, err1 := makeReservationUseCase.Book(ctx, 1, 1)
+	_, err2 := makeReservationUseCase.Book(ctx, 1, 2)
+	_, err3 := makeReservationUseCase.Book(ctx, 2, 2)
+	rowsCancelled, err4 := uc.CancelSeries(ctx, 2)
+	_, err5 := uc.CancelSeries(ctx, 2)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	assert.Nil(t, err4)
+	assert.Equal(t, int64(2), rowsCancelled)
+	assert.Equal(t, http.StatusUnprocessableEntity, err5.(utils.Error).Code)
+
+	first, err6 := classesReadRep.GetById(ctx, 1)
+	assert.Nil(t, err6)
+	assert.Equal(t, api.ClassStatusScheduled, first.Status)
+	assert.Equal(t, 1, first.NumRegistrations)
+
+	second, err7 := classesReadRep.GetById(ctx, 2)
+	assert.Nil(t, err7)
+	assert.Equal(t, api.ClassStatusCancelled, second.Status)
+	assert.Equal(t, 0, second.NumRegistrations)
+
+	userReservations, err8 := bookUseCase.GetUserReservations(ctx, 2)
+	assert.Nil(t, err8)
+	assert.Len(t, userReservations, 0)
+}
+
+func TestCancelClass_ReleasesBookingsAndNotifiesMembers(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	date := "2030-03-17T12:00:00Z"
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, num_registrations)
+	VALUES('Test', '` + date + `', 1, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado'), ('Sergio Folgado'), ('Maria Folgado')`)
+	addUnlimitedMemberships()
+
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+	bookUseCase := usecases.NewBookUseCase(redRep, wrRep)
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
+	uc := usecases.NewClassesUseCases(classes.NewReadRepository(testDbInstance), classes.NewWriteRepository(testDbInstance), rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
+
+	expectedError := utils.E(http.StatusUnprocessableEntity,
+		nil,
+		map[string]string{"message": "Class Cancelled"},
+		"The specified class is cancelled.",
+		"Please select a scheduled class.")
+
+	// Act
+	_, err := makeReservationUseCase.Book(ctx, 1, 1)
+	_, err1 := makeReservationUseCase.Book(ctx, 2, 1)
+	err2 := uc.CancelClass(ctx, 1)
+	_, err3 := makeReservationUseCase.Book(ctx, 3, 1)
+	cancelled, err4 := uc.GetFilteredClasses(ctx, api.ClasseFilters{Status: api.ClassStatusCancelled})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Equal(t, expectedError, err3)
+	assert.Nil(t, err4)
+	assert.Len(t, cancelled, 1)
+	assert.Equal(t, api.ClassStatusCancelled, cancelled[0].Status)
+	assert.Equal(t, 0, cancelled[0].NumRegistrations)
+
+	classReservations, err5 := bookUseCase.GetClassesReservations(ctx, 1)
+	assert.Nil(t, err5)
+	assert.Len(t, classReservations, 0)
+
+	// only the booked member is notified, the waitlist entry is dropped
+	var notified []int
+	err6 := testDbInstance.Select(&notified, "SELECT user_id FROM notification_outbox WHERE class_id = 1 AND event_type = $1", api.NotificationClassCancelled)
+	assert.Nil(t, err6)
+	assert.Equal(t, []int{1}, notified)
+
+	waitlisted, err7 := bookUseCase.GetUserReservations(ctx, 2)
+	assert.Nil(t, err7)
+	assert.Len(t, waitlisted, 0)
+}
+
+func TestCheckIn_MarksAttendanceAndNoShows(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	// one class starting now and one already ended
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, class_duration, num_registrations)
+	VALUES('Now', NOW(), 3, 60, 0), ('Ended', NOW() - INTERVAL '3 hours', 3, 60, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado'), ('Sergio Folgado'), ('Maria Folgado')`)
+	testDbInstance.DB.Exec(`INSERT INTO booking (user_id, class_id, reserved_date) VALUES(1, 1, NOW()), (2, 1, NOW()), (1, 2, NOW())`)
+
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+	bookUseCase := usecases.NewBookUseCase(redRep, wrRep)
+	attendanceUseCase := usecases.NewAttendanceUseCase(wrRep, utils.SystemClock)
+
+	expectedError := utils.E(http.StatusConflict,
+		nil,
+		map[string]string{"message": "Already Checked In"},
+		"The specified user is already checked in for this class.",
+		"Validate the class attendance.")
+
+	// Act
+	err := attendanceUseCase.CheckIn(ctx, 1, 1, 3)
+	err1 := attendanceUseCase.CheckIn(ctx, 1, 1, 3)
+	rows, err2 := attendanceUseCase.MarkNoShows(ctx)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, expectedError, err1)
+	assert.Nil(t, err2)
+	// only the booking of the ended class, the other class is still running
+	assert.Equal(t, int64(1), rows)
+
+	reservations, err3 := bookUseCase.GetUserReservations(ctx, 1)
+	assert.Nil(t, err3)
+	assert.Len(t, reservations, 2)
+	for _, reservation := range reservations {
+		switch reservation.Id {
+		case 1:
+			assert.Equal(t, api.AttendanceAttended, reservation.AttendanceStatus)
+			assert.NotNil(t, reservation.CheckedInAt)
+		case 2:
+			assert.Equal(t, api.AttendanceNoShow, reservation.AttendanceStatus)
+			assert.Nil(t, reservation.CheckedInAt)
+		}
+	}
+
+	var checkedInBy int
+	err4 := testDbInstance.Get(&checkedInBy, "SELECT checked_in_by FROM booking WHERE user_id = 1 AND class_id = 1")
+	assert.Nil(t, err4)
+	assert.Equal(t, 3, checkedInBy)
+}
+
+func TestBookingPolicies_CutoffAndLateCancellation(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_type, class_date, class_capacity, class_duration, num_registrations)
+	VALUES('Soon', 'yoga', NOW() + INTERVAL '2 hours', 3, 60, 0), ('Spinning', 'spinning', NOW() + INTERVAL '30 minutes', 3, 60, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado')`)
+	addUnlimitedMemberships()
+
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+	spinningCutoff := time.Hour
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{
+		Booking:    config.BookingPolicy{ClosesBefore: 15 * time.Minute, LateCancelWithin: 12 * time.Hour},
+		ClassTypes: map[string]config.PolicyOverride{"spinning": {ClosesBefore: &spinningCutoff}},
+	}), utils.SystemClock)
+
+	// Act
+	_, err := makeReservationUseCase.Book(ctx, 1, 1)
+	_, err1 := makeReservationUseCase.Book(ctx, 1, 2)
+	cancellation, err2 := makeReservationUseCase.Cancel(ctx, 1, 1)
+
+	// assert
+	assert.Nil(t, err)
+	e, ok := err1.(utils.Error)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnprocessableEntity, e.StatusCode())
+	assert.Equal(t, policies.RuleBookingCutoff, e.Message()["rule"])
+	assert.Equal(t, "spinning", e.Message()["policy"])
+	assert.Nil(t, err2)
+	assert.True(t, cancellation.Late)
+
+	var lateCancellations int
+	err3 := testDbInstance.Get(&lateCancellations, "SELECT COUNT(*) FROM late_cancellations WHERE user_id = 1 AND class_id = 1")
+	assert.Nil(t, err3)
+	assert.Equal(t, 1, lateCancellations)
+}
+
+func TestCreateReservation_ClassStart(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	date := "2030-03-17T12:00:00Z"
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, class_duration, num_registrations)
+	VALUES('Test', '` + date + `', 3, 60, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado')`)
+	addUnlimitedMemberships()
+
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+	start := time.Date(2030, time.March, 17, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		testName string
+		now      time.Time
+		message  string
+	}{
+		{"finished class", start.Add(2 * time.Hour), "Class Already Finished"},
+		{"class in progress", start.Add(30 * time.Minute), "Class In Progress"},
+		{"future class", start.Add(-time.Hour), ""},
+	}
+
+	for _, tc := range testCases {
+		now := tc.now
+		clock := utils.ClockFunc(func() time.Time { return now })
+		makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), clock)
+
+		// Act
+		result, err := makeReservationUseCase.Book(ctx, 1, 1)
+
+		// assert
+		if tc.message == "" {
+			assert.Nil(t, err, tc.testName)
+			assert.Equal(t, api.BookingStatusBooked, result.Status, tc.testName)
+			continue
+		}
+
+		e, ok := err.(utils.Error)
+		assert.True(t, ok, tc.testName)
+		assert.Equal(t, http.StatusUnprocessableEntity, e.StatusCode(), tc.testName)
+		assert.Equal(t, tc.message, e.Message()["message"], tc.testName)
+	}
+
+	// the repository checks the locked class row on its own
+	_, err := wrRep.Add(ctx, 1, 1, start.Add(time.Minute), nil)
+	e, ok := err.(utils.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "Class In Progress", e.Message()["message"])
+}
+
+func TestCreateReservation_CreditPackConsumedAndRefunded(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, class_duration, num_registrations)
+	VALUES('Test', '2030-03-17T12:00:00Z', 1, 60, 0), ('Other', '2030-03-18T12:00:00Z', 3, 60, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado'), ('Sergio Folgado')`)
+
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
+	membershipsUseCase := usecases.NewMembershipsUseCases(memberships.NewReadRepository(testDbInstance), memberships.NewWriteRepository(testDbInstance), utils.SystemClock)
+	validFrom := time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	expectedNoCredits := utils.E(http.StatusUnprocessableEntity,
+		nil,
+		map[string]string{"message": "No Credits Left"},
+		"All the credits of the memberships valid for this class are used.",
+		"Please buy a new class pack or an unlimited membership.")
+
+	// Act
+	err := membershipsUseCase.CreatePlan(ctx, api.CreatePlan{Name: "Single", Type: api.PlanTypeCredits, Credits: 1, ValidityDays: 30})
+	_, err1 := membershipsUseCase.AssignMembership(ctx, 1, 1, validFrom)
+	_, err2 := membershipsUseCase.AssignMembership(ctx, 2, 1, validFrom)
+	_, err3 := makeReservationUseCase.Book(ctx, 1, 1)
+	_, err4 := makeReservationUseCase.Book(ctx, 1, 2)
+	// the waitlist entry holds the credit of the second user too
+	waitlisted, err5 := makeReservationUseCase.Book(ctx, 2, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+	assert.Equal(t, expectedNoCredits, err4)
+	assert.Nil(t, err5)
+	assert.Equal(t, api.BookingStatusWaitlisted, waitlisted.Status)
+
+	// the cancellation refunds the first user and promotes the second one with its credit
+	_, err6 := makeReservationUseCase.Cancel(ctx, 1, 1)
+	assert.Nil(t, err6)
+
+	first, err7 := membershipsUseCase.GetUserMemberships(ctx, 1)
+	assert.Nil(t, err7)
+	assert.Len(t, first, 1)
+	assert.Equal(t, 1, *first[0].RemainingCredits)
+
+	second, err8 := membershipsUseCase.GetUserMemberships(ctx, 2)
+	assert.Nil(t, err8)
+	assert.Equal(t, 0, *second[0].RemainingCredits)
+
+	var membershipId int
+	err9 := testDbInstance.Get(&membershipId, "SELECT membership_id FROM booking WHERE user_id = 2 AND class_id = 1")
+	assert.Nil(t, err9)
+	assert.Equal(t, second[0].Id, membershipId)
+}
+
+func TestCreateReservation_NoValidMembership(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, class_duration, num_registrations)
+	VALUES('Test', '2030-03-17T12:00:00Z', 3, 60, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado')`)
+	// valid until the day before the class
+	testDbInstance.DB.Exec(`INSERT INTO membership_plans (plan_name, plan_type, validity_days) VALUES('Monthly', 'unlimited', 30)`)
+	testDbInstance.DB.Exec(`INSERT INTO user_memberships (user_id, plan_id, valid_from, valid_until) VALUES(1, 1, '2030-02-15', '2030-03-17')`)
+
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
+
+	expectedError := utils.E(http.StatusPaymentRequired,
+		nil,
+		map[string]string{"message": "No Valid Membership"},
+		"The user has no membership valid on 2030-03-17.",
+		"Please buy a membership or a class pack covering the class date.")
+
+	// Act
+	_, err := makeReservationUseCase.Book(ctx, 1, 1)
+
+	// assert
+	assert.Equal(t, expectedError, err)
+}
+
+func TestCancelClass_RefundsCredits(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, class_duration, num_registrations)
+	VALUES('Test', '2030-03-17T12:00:00Z', 1, 60, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado'), ('Sergio Folgado')`)
+	testDbInstance.DB.Exec(`INSERT INTO membership_plans (plan_name, plan_type, credits, validity_days) VALUES('Pack', 'credits', 10, 90)`)
+	testDbInstance.DB.Exec(`INSERT INTO user_memberships (user_id, plan_id, valid_from, valid_until, remaining_credits)
+	VALUES(1, 1, '2030-03-01', '2030-05-30', 10), (2, 1, '2030-03-01', '2030-05-30', 10)`)
+
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
+	uc := usecases.NewClassesUseCases(classes.NewReadRepository(testDbInstance), classes.NewWriteRepository(testDbInstance), rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
+
+	// Act
+	_, err := makeReservationUseCase.Book(ctx, 1, 1)
+	_, err1 := makeReservationUseCase.Book(ctx, 2, 1)
+	err2 := uc.CancelClass(ctx, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+
+	var credits []int
+	err3 := testDbInstance.Select(&credits, "SELECT remaining_credits FROM user_memberships ORDER BY user_id")
+	assert.Nil(t, err3)
+	assert.Equal(t, []int{10, 10}, credits)
+}
+
+func TestCancelClass_ClosesDropInPayments(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, class_duration, num_registrations)
+	VALUES('Test', '2030-03-17T12:00:00Z', 2, 60, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado'), ('Sergio Folgado')`)
+
+	settings := config.Payments{Provider: payments.ProviderFake, WebhookSecret: "secret", Currency: "EUR", DropInPrice: 1500}
+	provider := payments.NewFakeProvider(settings.WebhookSecret)
+	paymentsUc := usecases.NewPaymentsUseCases(paymentsdb.NewReadRepository(testDbInstance), paymentsdb.NewWriteRepository(testDbInstance),
+		memberships.NewReadRepository(testDbInstance), classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), provider, settings, utils.SystemClock)
+	uc := usecases.NewClassesUseCases(classes.NewReadRepository(testDbInstance), classes.NewWriteRepository(testDbInstance), rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
+
+	// Act
+	paid, err := paymentsUc.PayDropIn(ctx, 1, 1)
+	succeeded, signature := provider.SignedEvent(payments.Event{Type: payments.EventPaymentSucceeded, Ref: paid.ProviderRef})
+	err1 := paymentsUc.HandleWebhook(ctx, succeeded, signature)
+	pending, err2 := paymentsUc.PayDropIn(ctx, 2, 1)
+	err3 := uc.CancelClass(ctx, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Nil(t, err3)
+
+	var statuses []string
+	err4 := testDbInstance.Select(&statuses, "SELECT payment_status FROM payments WHERE id IN ($1, $2) ORDER BY id", paid.Id, pending.Id)
+	assert.Nil(t, err4)
+	assert.Equal(t, []string{api.PaymentStatusRefunded, api.PaymentStatusFailed}, statuses)
+
+	var ledger []int
+	err5 := testDbInstance.Select(&ledger, "SELECT amount_cents FROM payment_ledger WHERE payment_id = $1 ORDER BY id", paid.Id)
+	assert.Nil(t, err5)
+	assert.Equal(t, []int{1500, 1500, -1500}, ledger)
+}
+
+func TestPayDropIn_ConfirmedByWebhookAndReleasedOnFailure(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, class_duration, num_registrations)
+	VALUES('Test', '2030-03-17T12:00:00Z', 1, 60, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado'), ('Sergio Folgado')`)
+
+	settings := config.Payments{Provider: payments.ProviderFake, WebhookSecret: "secret", Currency: "EUR", DropInPrice: 1500}
+	provider := payments.NewFakeProvider(settings.WebhookSecret)
+	uc := usecases.NewPaymentsUseCases(paymentsdb.NewReadRepository(testDbInstance), paymentsdb.NewWriteRepository(testDbInstance),
+		memberships.NewReadRepository(testDbInstance), classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), provider, settings, utils.SystemClock)
+	bookingInfo := usecases.NewBookUseCase(booking.NewReadRepository(testDbInstance), booking.NewWriteRepository(testDbInstance))
+
+	expectedFull := utils.E(http.StatusConflict,
+		nil,
+		map[string]string{"message": "Class Full"},
+		"The specified class has no free seat for a drop-in.",
+		"Please book the class with a membership to join its waitlist.")
+
+	// Act
+	payment, err := uc.PayDropIn(ctx, 1, 1)
+	// the pending drop-in holds the only seat
+	_, err1 := uc.PayDropIn(ctx, 2, 1)
+	pending, err2 := bookingInfo.GetUserReservations(ctx, 1)
+	succeeded, signature := provider.SignedEvent(payments.Event{Type: payments.EventPaymentSucceeded, Ref: payment.ProviderRef})
+	err3 := uc.HandleWebhook(ctx, succeeded, signature)
+	// the provider may repeat its callbacks
+	err4 := uc.HandleWebhook(ctx, succeeded, signature)
+	confirmed, err5 := bookingInfo.GetUserReservations(ctx, 1)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, api.PaymentStatusPending, payment.Status)
+	assert.Equal(t, 1500, payment.Amount)
+	assert.Equal(t, expectedFull, err1)
+	assert.Nil(t, err2)
+	assert.Equal(t, api.BookingStatusPendingPayment, pending[0].Status)
+	assert.Nil(t, err3)
+	assert.Nil(t, err4)
+	assert.Nil(t, err5)
+	assert.Equal(t, api.BookingStatusBooked, confirmed[0].Status)
+
+	var ledger []string
+	err6 := testDbInstance.Select(&ledger, "SELECT entry_type FROM payment_ledger WHERE payment_id = $1 ORDER BY id", payment.Id)
+	assert.Nil(t, err6)
+	assert.Equal(t, []string{"intent", "capture"}, ledger)
+
+	// the refund releases the seat, and a failed payment releases it again
+	refunded, err7 := uc.Refund(ctx, payment.Id)
+	assert.Nil(t, err7)
+	assert.Equal(t, api.PaymentStatusRefunded, refunded.Status)
+
+	second, err8 := uc.PayDropIn(ctx, 2, 1)
+	assert.Nil(t, err8)
+	failed, signature := provider.SignedEvent(payments.Event{Type: payments.EventPaymentFailed, Ref: second.ProviderRef})
+	err9 := uc.HandleWebhook(ctx, failed, signature)
+	assert.Nil(t, err9)
+
+	var numRegistrations int
+	err10 := testDbInstance.Get(&numRegistrations, "SELECT num_registrations FROM classes WHERE id = 1")
+	assert.Nil(t, err10)
+	assert.Equal(t, 0, numRegistrations)
+}
+
+func TestPayDropIn_CancelledHoldKeepsMembershipBooking(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, class_duration, num_registrations)
+	VALUES('Test', '2030-03-17T12:00:00Z', 1, 60, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado')`)
+	testDbInstance.DB.Exec(`INSERT INTO membership_plans (plan_name, plan_type, credits, validity_days) VALUES('Pack', 'credits', 10, 90)`)
+	testDbInstance.DB.Exec(`INSERT INTO user_memberships (user_id, plan_id, valid_from, valid_until, remaining_credits)
+	VALUES(1, 1, '2030-03-01', '2030-05-30', 10)`)
+
+	settings := config.Payments{Provider: payments.ProviderFake, WebhookSecret: "secret", Currency: "EUR", DropInPrice: 1500}
+	provider := payments.NewFakeProvider(settings.WebhookSecret)
+	paymentsWrRep := paymentsdb.NewWriteRepository(testDbInstance)
+	uc := usecases.NewPaymentsUseCases(paymentsdb.NewReadRepository(testDbInstance), paymentsWrRep,
+		memberships.NewReadRepository(testDbInstance), classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), provider, settings, utils.SystemClock)
+	redRep := booking.NewReadRepository(testDbInstance)
+	wrRep := booking.NewWriteRepository(testDbInstance)
+	makeReservationUseCase := usecases.NewMakeBookUseCase(redRep, wrRep, classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
+
+	// Act
+	dropIn, err := uc.PayDropIn(ctx, 1, 1)
+	_, err1 := makeReservationUseCase.Cancel(ctx, 1, 1)
+	booked, err2 := makeReservationUseCase.Book(ctx, 1, 1)
+	// the provider reports the cancelled drop-in later
+	succeeded, signature := provider.SignedEvent(payments.Event{Type: payments.EventPaymentSucceeded, Ref: dropIn.ProviderRef})
+	err3 := uc.HandleWebhook(ctx, succeeded, signature)
+	// the provider may repeat its callbacks
+	err4 := uc.HandleWebhook(ctx, succeeded, signature)
+	// a pending drop-in payment left behind holds no booking, so it releases none
+	var stale int
+	err5 := testDbInstance.Get(&stale, `INSERT INTO payments (user_id, purpose, class_id, amount_cents, currency, provider)
+	VALUES(1, 'drop_in', 1, 1500, 'EUR', 'fake') RETURNING id`)
+	_, err6 := paymentsWrRep.Fail(ctx, stale, time.Now())
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Equal(t, api.BookingStatusBooked, booked.Status)
+	// the payment failed with the cancellation, the provider refunds it once
+	assert.Nil(t, err3)
+	assert.Nil(t, err4)
+	assert.Nil(t, err5)
+	assert.Nil(t, err6)
+
+	var status string
+	assert.Nil(t, testDbInstance.Get(&status, "SELECT payment_status FROM payments WHERE id = $1", dropIn.Id))
+	assert.Equal(t, api.PaymentStatusRefunded, status)
+
+	var ledger []string
+	assert.Nil(t, testDbInstance.Select(&ledger, "SELECT entry_type FROM payment_ledger WHERE payment_id = $1 ORDER BY id", dropIn.Id))
+	assert.Equal(t, []string{"intent", "failure", "capture", "refund"}, ledger)
+
+	var bookings, numRegistrations, remainingCredits int
+	assert.Nil(t, testDbInstance.Get(&bookings, "SELECT count(*) FROM booking WHERE user_id = 1 AND class_id = 1 AND membership_id IS NOT NULL"))
+	assert.Nil(t, testDbInstance.Get(&numRegistrations, "SELECT num_registrations FROM classes WHERE id = 1"))
+	assert.Nil(t, testDbInstance.Get(&remainingCredits, "SELECT remaining_credits FROM user_memberships WHERE user_id = 1"))
+	assert.Equal(t, 1, bookings)
+	assert.Equal(t, 1, numRegistrations)
+	assert.Equal(t, 9, remainingCredits)
+}
+
+func TestRefund_StartedClassKeepsItsBookings(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, class_duration, num_registrations)
+	VALUES('Test', '2030-03-17T12:00:00Z', 1, 60, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado'), ('Sergio Folgado')`)
+	addUnlimitedMemberships()
+
+	settings := config.Payments{Provider: payments.ProviderFake, WebhookSecret: "secret", Currency: "EUR", DropInPrice: 1500}
+	provider := payments.NewFakeProvider(settings.WebhookSecret)
+	uc := usecases.NewPaymentsUseCases(paymentsdb.NewReadRepository(testDbInstance), paymentsdb.NewWriteRepository(testDbInstance),
+		memberships.NewReadRepository(testDbInstance), classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), provider, settings, utils.SystemClock)
+	makeReservationUseCase := usecases.NewMakeBookUseCase(booking.NewReadRepository(testDbInstance), booking.NewWriteRepository(testDbInstance),
+		classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), utils.SystemClock)
+
+	// Act
+	dropIn, err := uc.PayDropIn(ctx, 1, 1)
+	_, err1 := uc.Capture(ctx, dropIn.Id)
+	waiting, err2 := makeReservationUseCase.Book(ctx, 2, 1)
+	testDbInstance.DB.Exec(`UPDATE classes SET class_date = CURRENT_TIMESTAMP - interval '10 minutes'`)
+	refunded, err3 := uc.Refund(ctx, dropIn.Id)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Equal(t, api.BookingStatusWaitlisted, waiting.Status)
+	assert.Nil(t, err3)
+	assert.Equal(t, api.PaymentStatusRefunded, refunded.Status)
+
+	// the money goes back but the seat of the started class is neither released nor given to the waitlist
+	var holders []int
+	var numRegistrations int
+	assert.Nil(t, testDbInstance.Select(&holders, "SELECT user_id FROM booking WHERE class_id = 1"))
+	assert.Nil(t, testDbInstance.Get(&numRegistrations, "SELECT num_registrations FROM classes WHERE id = 1"))
+	assert.Equal(t, []int{1}, holders)
+	assert.Equal(t, 1, numRegistrations)
+}
+
+func TestExpireDropIns_ReleasesUnpaidSeats(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	testDbInstance.DB.Exec(`INSERT INTO classes (class_name, class_date, class_capacity, class_duration, num_registrations)
+	VALUES('Test', '2030-03-17T12:00:00Z', 1, 60, 0)`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado')`)
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Ana Silva')`)
+
+	settings := config.Payments{Provider: payments.ProviderFake, WebhookSecret: "secret", Currency: "EUR", DropInPrice: 1500, DropInHoldTTL: 15 * time.Minute}
+	provider := payments.NewFakeProvider(settings.WebhookSecret)
+	uc := usecases.NewPaymentsUseCases(paymentsdb.NewReadRepository(testDbInstance), paymentsdb.NewWriteRepository(testDbInstance),
+		memberships.NewReadRepository(testDbInstance), classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}), provider, settings, utils.SystemClock)
+
+	// Act
+	unpaid, err := uc.PayDropIn(ctx, 1, 1)
+	_, err1 := uc.PayDropIn(ctx, 2, 1)
+	notExpired, err2 := uc.ExpireDropIns(ctx)
+	testDbInstance.DB.Exec(`UPDATE payments SET create_date = create_date - interval '20 minutes'`)
+	expired, err3 := uc.ExpireDropIns(ctx)
+	paid, err4 := uc.PayDropIn(ctx, 2, 1)
+	// the provider completes the expired payment later
+	succeeded, signature := provider.SignedEvent(payments.Event{Type: payments.EventPaymentSucceeded, Ref: unpaid.ProviderRef})
+	err5 := uc.HandleWebhook(ctx, succeeded, signature)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusConflict, err1.(utils.Error).StatusCode())
+	assert.Nil(t, err2)
+	assert.Equal(t, int64(0), notExpired)
+	assert.Nil(t, err3)
+	assert.Equal(t, int64(1), expired)
+	assert.Nil(t, err4)
+	assert.Equal(t, api.PaymentStatusPending, paid.Status)
+	assert.Nil(t, err5)
+
+	var status string
+	assert.Nil(t, testDbInstance.Get(&status, "SELECT payment_status FROM payments WHERE id = $1", unpaid.Id))
+	assert.Equal(t, api.PaymentStatusRefunded, status)
+
+	var holders []int
+	assert.Nil(t, testDbInstance.Select(&holders, "SELECT user_id FROM booking WHERE class_id = 1"))
+	assert.Equal(t, []int{2}, holders)
+}
+
+func TestPayMembership_CaptureGrantsMembership(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	testDbInstance.DB.Exec(`INSERT INTO users (user_name) VALUES('Joao Folgado')`)
+	testDbInstance.DB.Exec(`INSERT INTO membership_plans (plan_name, plan_type, credits, validity_days, price_cents) VALUES('Pack', 'credits', 10, 90, 12000)`)
+
+	settings := config.Payments{Provider: payments.ProviderFake, WebhookSecret: "secret", Currency: "EUR", DropInPrice: 1500}
+	uc := usecases.NewPaymentsUseCases(paymentsdb.NewReadRepository(testDbInstance), paymentsdb.NewWriteRepository(testDbInstance),
+		memberships.NewReadRepository(testDbInstance), classes.NewReadRepository(testDbInstance), testPolicies(config.Policies{}),
+		payments.NewFakeProvider(settings.WebhookSecret), settings, utils.SystemClock)
+	membershipsUseCase := usecases.NewMembershipsUseCases(memberships.NewReadRepository(testDbInstance), memberships.NewWriteRepository(testDbInstance), utils.SystemClock)
+
+	// Act
+	payment, err := uc.PayMembership(ctx, 1, 1)
+	before, err1 := membershipsUseCase.GetUserMemberships(ctx, 1)
+	captured, err2 := uc.Capture(ctx, payment.Id)
+	after, err3 := membershipsUseCase.GetUserMemberships(ctx, 1)
+	_, err4 := uc.Capture(ctx, payment.Id)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, 12000, payment.Amount)
+	assert.Nil(t, err1)
+	assert.Len(t, before, 0)
+	assert.Nil(t, err2)
+	assert.Equal(t, api.PaymentStatusCaptured, captured.Status)
+	assert.Nil(t, err3)
+	assert.Len(t, after, 1)
+	assert.Equal(t, captured.MembershipId, after[0].Id)
+	assert.Equal(t, 10, *after[0].RemainingCredits)
+	assert.Equal(t, http.StatusConflict, err4.(utils.Error).StatusCode())
+}
+
+func testPolicies(cfg config.Policies) *policies.Engine {
+	engine, err := policies.NewEngine(cfg)
+	if err != nil {
+		log.Fatalf("Invalid booking policies: %v", err)
+	}
+	return engine
+}
+
+func Int(i int) *int {
+	return &i
+}
+
+func String(i string) *string {
+	return &i
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	tokens, err := auth.NewTokens(config.Auth{TokenSecret: "integration-token-secret-32-characters"}, utils.SystemClock)
+	assert.NoError(t, err)
+	uc := usecases.NewAuthUseCases(users.NewReadRepository(testDbInstance), users.NewWriteRepository(testDbInstance), tokens)
+
+	// Act
+	registered, err1 := uc.Register(ctx, api.Register{Name: "Ana", Email: "Ana@Studio.com", Password: "correct horse"})
+	_, err2 := uc.Register(ctx, api.Register{Name: "Other Ana", Email: "ana@studio.com", Password: "another horse"})
+	loggedIn, err3 := uc.Login(ctx, "ANA@studio.com", "correct horse")
+	_, err4 := uc.Login(ctx, "ana@studio.com", "wrong horse")
+
+	// Assert
+	assert.NoError(t, err1)
+	assert.Equal(t, http.StatusConflict, err2.(utils.Error).Code)
+	assert.NoError(t, err3)
+	assert.Equal(t, http.StatusUnauthorized, err4.(utils.Error).Code)
+
+	registeredId, err := tokens.Verify(registered.AccessToken)
+	assert.NoError(t, err)
+	loggedInId, err := tokens.Verify(loggedIn.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, registeredId, loggedInId)
+
+	user, err := users.NewReadRepository(testDbInstance).GetById(ctx, registeredId.UserId)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ana", user.Name)
+
+	// the contact email is the sign in email
+	newEmail, noEmail := "ana.silva@studio.com", ""
+	_, err5 := users.NewWriteRepository(testDbInstance).Update(ctx, api.PatchUser{Id: registeredId.UserId, Email: &newEmail})
+	_, err6 := uc.Login(ctx, "ana.silva@studio.com", "correct horse")
+	_, err7 := uc.Login(ctx, "ana@studio.com", "correct horse")
+	_, err8 := users.NewWriteRepository(testDbInstance).Update(ctx, api.PatchUser{Id: registeredId.UserId, Email: &noEmail})
+	_, err9 := uc.Register(ctx, api.Register{Name: "Other Ana", Email: "ana@studio.com", Password: "another horse"})
+
+	assert.NoError(t, err5)
+	assert.NoError(t, err6)
+	assert.Equal(t, http.StatusUnauthorized, err7.(utils.Error).Code)
+	assert.Equal(t, http.StatusBadRequest, err8.(utils.Error).Code)
+	assert.NoError(t, err9)
+}
+
+func TestUserRoles_InstructorTeachesLinkedClasses(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	defer cleanupInstructorsTableDatabase()
+	// Arrange
+	ctx := context.Background()
+	tokens, err := auth.NewTokens(config.Auth{TokenSecret: "integration-token-secret-32-characters"}, utils.SystemClock)
+	assert.NoError(t, err)
+	authUc := usecases.NewAuthUseCases(users.NewReadRepository(testDbInstance), users.NewWriteRepository(testDbInstance), tokens)
+	usersUc := usecases.NewUserUseCase(users.NewReadRepository(testDbInstance), users.NewWriteRepository(testDbInstance))
+
+	registered, err := authUc.Register(ctx, api.Register{Name: "Ana Silva", Email: "ana@studio.com", Password: "correct horse"})
+	assert.NoError(t, err)
+	member, err := tokens.Verify(registered.AccessToken)
+	assert.NoError(t, err)
+
+	assert.Nil(t, rooms.NewWriteRepository(testDbInstance).Add(ctx, api.CreateRoom{Name: "Studio A", MaxCapacity: 20}))
+	instructorsUc := usecases.NewInstructorsUseCases(instructors.NewReadRepository(testDbInstance), instructors.NewWriteRepository(testDbInstance))
+	assert.Nil(t, instructorsUc.CreateInstructor(ctx, api.CreateInstructor{Name: "Ana Silva", UserId: member.UserId}))
+	assert.Nil(t, instructorsUc.CreateInstructor(ctx, api.CreateInstructor{Name: "Rui Costa"}))
+
+	morning := time.Date(2030, time.March, 1, 8, 0, 0, 0, time.UTC)
+	classesUc := usecases.NewClassesUseCases(classes.NewReadRepository(testDbInstance), classes.NewWriteRepository(testDbInstance), rooms.NewReadRepository(testDbInstance), instructors.NewReadRepository(testDbInstance), utils.SystemClock)
+	_, err = classesUc.CreateClass(ctx, api.ClassScheduler{Name: "Yoga", StartDate: morning, EndDate: morning, Duration: 60, Capacity: 10, RoomId: 1, InstructorId: 1})
+	assert.NoError(t, err)
+	_, err = classesUc.CreateClass(ctx, api.ClassScheduler{Name: "Pilates", StartDate: morning.Add(2 * time.Hour), EndDate: morning.Add(2 * time.Hour), Duration: 60, Capacity: 10, RoomId: 1, InstructorId: 2})
+	assert.NoError(t, err)
+
+	// act
+	errSameUser := instructorsUc.CreateInstructor(ctx, api.CreateInstructor{Name: "Ana S.", UserId: member.UserId})
+	errRole := usersUc.SetUserRole(ctx, member.UserId, api.RoleInstructor)
+	loggedIn, errLogin := authUc.Login(ctx, "ana@studio.com", "correct horse")
+	teachesYoga, err1 := classes.NewReadRepository(testDbInstance).IsTaughtBy(ctx, 1, member.UserId)
+	teachesPilates, err2 := classes.NewReadRepository(testDbInstance).IsTaughtBy(ctx, 2, member.UserId)
+
+	// assert
+	assert.Equal(t, api.RoleMember, member.Role)
+	assert.Equal(t, http.StatusConflict, errSameUser.(utils.Error).Code)
+	assert.NoError(t, errRole)
+	assert.NoError(t, errLogin)
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.True(t, teachesYoga)
+	assert.False(t, teachesPilates)
+
+	instructor, err := tokens.Verify(loggedIn.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Identity{UserId: member.UserId, Role: api.RoleInstructor}, instructor)
+}
+
+func TestAPIKeys_AuthenticateRecordsUseUntilRevoked(t *testing.T) {
+	defer cleanupAllTablesDatabase()
+	// Arrange
+	ctx := context.Background()
+	assert.Nil(t, users.NewWriteRepository(testDbInstance).Add(ctx, api.CreateUser{Name: "Owner"}))
+	testDbInstance.DB.Exec(`UPDATE users SET user_role = 'owner' WHERE id = 1`)
+	now := time.Date(2030, time.March, 1, 8, 0, 0, 0, time.UTC)
+	clock := utils.ClockFunc(func() time.Time { return now })
+	uc := usecases.NewAPIKeysUseCases(apikeys.NewReadRepository(testDbInstance), apikeys.NewWriteRepository(testDbInstance), clock)
+
+	created, err := uc.CreateAPIKey(ctx, 1, api.CreateAPIKey{Name: "Kiosk", Scopes: []string{"classes:read", "bookings:write"}})
+	assert.NoError(t, err)
+
+	// act
+	identity, err1 := uc.Authenticate(ctx, created.Key)
+	used := now
+	// uses within a minute of the recorded one are not written
+	now = used.Add(30 * time.Second)
+	_, err6 := uc.Authenticate(ctx, created.Key)
+	recent, err7 := uc.GetAPIKeys(ctx)
+	now = used.Add(2 * time.Minute)
+	_, err8 := uc.Authenticate(ctx, created.Key)
+	keys, err2 := uc.GetAPIKeys(ctx)
+	err3 := uc.RevokeAPIKey(ctx, created.Id)
+	_, err4 := uc.Authenticate(ctx, created.Key)
+	err5 := uc.RevokeAPIKey(ctx, created.Id)
+
+	// assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.NoError(t, err3)
+	assert.Equal(t, http.StatusUnauthorized, err4.(utils.Error).Code)
+	assert.Equal(t, http.StatusNotFound, err5.(utils.Error).Code)
+	assert.Equal(t, auth.Identity{UserId: 1, Role: api.RoleOwner, APIKeyId: created.Id, Scopes: []string{"bookings:write", "classes:read"}}, identity)
+
+	assert.NoError(t, err6)
+	assert.NoError(t, err7)
+	assert.NoError(t, err8)
+	assert.True(t, used.Equal(*recent[0].LastUsedDate))
+
+	assert.Len(t, keys, 1)
+	assert.Equal(t, created.Prefix, keys[0].Prefix)
+	assert.True(t, used.Add(2*time.Minute).Equal(*keys[0].LastUsedDate))
+
+	var storedHash string
+	assert.Nil(t, testDbInstance.Get(&storedHash, "SELECT key_hash FROM api_keys WHERE id = $1", created.Id))
+	assert.Equal(t, auth.HashAPIKey(created.Key), storedHash)
+}
+
+func TestMigrate_ConcurrentServersAndSchemaAhead(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	var version uint
+	assert.Nil(t, testDbInstance.Get(&version, "SELECT version FROM schema_migrations"))
+
+	// act
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, _, err := migrations.Migrate(ctx, testDbInstance.DB)
+			errs <- err
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, <-errs)
+	}
+
+	_, err := testDbInstance.Exec("UPDATE schema_migrations SET version = $1", version+1)
+	assert.NoError(t, err)
+	_, _, errAhead := migrations.Migrate(ctx, testDbInstance.DB)
+	_, err = testDbInstance.Exec("UPDATE schema_migrations SET version = $1", version)
+	assert.NoError(t, err)
+
+	// assert
+	assert.ErrorIs(t, errAhead, migrations.ErrSchemaAhead)
+}
+
+func TestSeed(t *testing.T) {
+	defer cleanupInstructorsTableDatabase()
+	defer cleanupAllTablesDatabase()
+
+	// Arrange
+	ctx := context.Background()
+	month := time.Date(2031, time.February, 1, 0, 0, 0, 0, time.UTC)
+	opts := seed.Options{Users: 12, Month: month, BookingsPerUser: 5, Seed: 7, Password: "secret-password"}
+
+	// act
+	summary, err := seed.NewSeeder(testDbInstance).Run(ctx, opts)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 12, summary.Users)
+	assert.Equal(t, 3, summary.Rooms)
+	assert.Equal(t, 5, summary.Instructors)
+	assert.Greater(t, summary.Classes, 0)
+	assert.Greater(t, summary.Bookings, 0)
+
+	var overbooked int
+	assert.Nil(t, testDbInstance.Get(&overbooked, "SELECT count(*) FROM classes WHERE num_registrations > class_capacity"))
+	assert.Equal(t, 0, overbooked)
+
+	var bookings, waitlisted int
+	assert.Nil(t, testDbInstance.Get(&bookings, "SELECT count(*) FROM booking"))
+	assert.Nil(t, testDbInstance.Get(&waitlisted, "SELECT count(*) FROM waitlist"))
+	assert.Equal(t, summary.Bookings, bookings)
+	assert.Equal(t, 0, waitlisted)
+
+	var owners int
+	assert.Nil(t, testDbInstance.Get(&owners, "SELECT count(*) FROM users WHERE email = $1 AND user_role = $2", seed.OwnerEmail, api.RoleOwner))
+	assert.Equal(t, 1, owners)
 }
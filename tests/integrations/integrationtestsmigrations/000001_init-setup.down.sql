@@ -1,3 +0,0 @@
-DROP TABLE IF EXISTS booking;
-DROP TABLE IF EXISTS classes;
-DROP TABLE IF EXISTS users;
@@ -5,13 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"path/filepath"
-	"runtime"
 	"time"
 
+	"github.com/Flgado/fitnessStudioApp/internal/database/migrations"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/jackc/pgx/v4/stdlib"
 	"github.com/jmoiron/sqlx"
 	"github.com/testcontainers/testcontainers-go"
@@ -100,17 +98,15 @@ func createContainer(ctx context.Context) (testcontainers.Container, *sqlx.DB, s
 	return container, db, dbAddr, nil
 }
 
+// migrateDb runs the migrations embedded in the binaries, the same as in production.
 func migrateDb(dbAddr string) error {
-
-	_, path, _, ok := runtime.Caller(0)
-	if !ok {
-		return fmt.Errorf("failed to get path")
+	src, err := migrations.Source()
+	if err != nil {
+		return err
 	}
-	pathToMigrationFiles := filepath.Dir(path) + "/integrationtestsmigrations"
 
-	fmt.Printf(fmt.Sprintf("file:%s", pathToMigrationFiles))
 	databaseURL := fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable", DbUser, DbPass, dbAddr, DbName)
-	m, err := migrate.New(fmt.Sprintf("file:%s", pathToMigrationFiles), databaseURL)
+	m, err := migrate.NewWithSourceInstance(migrations.SourceName, src, databaseURL)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,18 @@
+package utils
+
+import "time"
+
+// Clock gives the current time, so the code depending on it can be tested at a fixed time.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface.
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the wall clock.
+var SystemClock Clock = ClockFunc(time.Now)
@@ -1,9 +1,6 @@
 package utils
 
-func GetConfigPath() string {
-	return "./config/config-local"
-}
-
-func GetMigrationConfigPath() string {
-	return "./config/config-migrations"
+// GetConfigPath returns the configuration file of a profile, e.g. ./config/config-local for local.
+func GetConfigPath(profile string) string {
+	return "./config/config-" + profile
 }